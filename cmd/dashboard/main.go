@@ -4,17 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"aws-serverless-rag/internal/analytics"
+	"aws-serverless-rag/internal/storage"
+	"aws-serverless-rag/internal/utils"
 )
 
+// recentQueryLimit caps the /queries/recent response size.
+const recentQueryLimit = 20
+
 type SystemStats struct {
 	TotalDocuments int     `json:"total_documents"`
 	TotalQueries   int     `json:"total_queries"`
@@ -35,9 +40,26 @@ type Document struct {
 	Size       int64     `json:"size"`
 	Type       string    `json:"type"`
 	UploadedAt time.Time `json:"uploaded_at"`
+	Checksum   string    `json:"checksum,omitempty"`
+}
+
+// DashboardHandler serves the admin dashboard's stats/documents endpoints against an
+// injected ObjectStore and an optional analytics Recorder, so it can be unit tested
+// without real AWS credentials.
+type DashboardHandler struct {
+	store     storage.ObjectStore
+	prefix    string
+	analytics *analytics.Recorder
 }
 
-func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+// NewDashboardHandler creates a handler backed by store, listing documents under
+// prefix. recorder may be nil, in which case /stats reports zero queries and users
+// and /queries/recent returns an empty list.
+func NewDashboardHandler(store storage.ObjectStore, prefix string, recorder *analytics.Recorder) *DashboardHandler {
+	return &DashboardHandler{store: store, prefix: prefix, analytics: recorder}
+}
+
+func (h *DashboardHandler) Handle(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	// Debug: log all requests to this function
 	fmt.Printf("DASHBOARD: Path=%s Method=%s\n", request.Path, request.HTTPMethod)
 
@@ -51,8 +73,8 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	// Handle different endpoints
 	switch request.Path {
 	case "/stats":
-		// Get actual counts from S3
-		documents, _ := getDocumentsFromS3()
+		// Get actual counts from the document store
+		documents, _ := h.getDocuments(ctx)
 		documentCount := len(documents)
 
 		// Calculate total storage used
@@ -62,13 +84,21 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		}
 		storageUsedGB := totalSize / (1024 * 1024 * 1024) // Convert bytes to GB
 
-		// For now, use reasonable default values for queries and users
-		// These could be fetched from CloudWatch metrics or DynamoDB in the future
 		stats := SystemStats{
 			TotalDocuments: documentCount,
-			TotalQueries:   157, // TODO: Fetch from CloudWatch metrics
 			StorageUsed:    storageUsedGB,
-			ActiveUsers:    3, // TODO: Fetch from session tracking
+		}
+		if h.analytics != nil {
+			if count, err := h.analytics.CountLast24h(ctx); err != nil {
+				fmt.Printf("Warning: failed to count recent queries: %v\n", err)
+			} else {
+				stats.TotalQueries = count
+			}
+			if users, err := h.analytics.DistinctUsersLast24h(ctx); err != nil {
+				fmt.Printf("Warning: failed to count active users: %v\n", err)
+			} else {
+				stats.ActiveUsers = users
+			}
 		}
 		body, _ := json.Marshal(stats)
 		return events.APIGatewayProxyResponse{
@@ -78,9 +108,22 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		}, nil
 
 	case "/queries/recent":
-		// TODO: In production, this would fetch from CloudWatch logs or DynamoDB
-		// For now, return empty array since no real query tracking is implemented
 		queries := []RecentQuery{}
+		if h.analytics != nil {
+			logs, err := h.analytics.RecentN(ctx, recentQueryLimit)
+			if err != nil {
+				fmt.Printf("Warning: failed to fetch recent queries: %v\n", err)
+			} else {
+				for _, log := range logs {
+					queries = append(queries, RecentQuery{
+						ID:        log.UserID + ":" + log.Timestamp.Format(time.RFC3339Nano),
+						Question:  log.Question,
+						Timestamp: log.Timestamp,
+						UserID:    log.UserID,
+					})
+				}
+			}
+		}
 		body, _ := json.Marshal(queries)
 		return events.APIGatewayProxyResponse{
 			StatusCode: 200,
@@ -90,9 +133,9 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 	case "/documents":
 		if request.HTTPMethod == "GET" {
-			documents, err := getDocumentsFromS3()
+			documents, err := h.getDocuments(ctx)
 			if err != nil {
-				fmt.Printf("Error getting documents from S3: %v\n", err)
+				fmt.Printf("Error getting documents: %v\n", err)
 				return events.APIGatewayProxyResponse{
 					StatusCode: 500,
 					Headers:    headers,
@@ -115,76 +158,42 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	}, nil
 }
 
-func getDocumentsFromS3() ([]Document, error) {
-	// Create AWS session
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String("ap-northeast-1"),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create AWS session: %w", err)
-	}
-
-	// Create S3 service client
-	svc := s3.New(sess)
-
-	// Get bucket name from environment variable
-	bucketName := os.Getenv("DOCUMENT_BUCKET")
-	if bucketName == "" {
-		bucketName = "aws-serverless-rag-prod-documents-prod" // fallback
-	}
-
-	// List objects in the documents folder
-	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(bucketName),
-		Prefix: aws.String("documents/prod/"),
-	}
-
-	result, err := svc.ListObjectsV2(input)
+// getDocuments lists every object under the configured prefix and enriches each with
+// the metadata (document ID, content type) recorded at upload time.
+func (h *DashboardHandler) getDocuments(ctx context.Context) ([]Document, error) {
+	objects, err := h.store.List(ctx, h.prefix)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list S3 objects: %w", err)
+		return nil, fmt.Errorf("failed to list documents: %w", err)
 	}
 
 	var documents []Document
-	for _, item := range result.Contents {
-		// Skip directory markers
-		if strings.HasSuffix(*item.Key, "/") {
-			continue
-		}
-
-		// Extract filename from key
-		keyParts := strings.Split(*item.Key, "/")
+	for _, item := range objects {
+		keyParts := strings.Split(item.Key, "/")
 		filename := keyParts[len(keyParts)-1]
 
-		// Get object metadata to retrieve document ID
-		headInput := &s3.HeadObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    item.Key,
-		}
-
-		headResult, err := svc.HeadObject(headInput)
+		metadata, err := h.store.Head(ctx, item.Key)
 		if err != nil {
-			fmt.Printf("Warning: Failed to get metadata for %s: %v\n", *item.Key, err)
+			fmt.Printf("Warning: Failed to get metadata for %s: %v\n", item.Key, err)
 			continue
 		}
 
-		// Extract document ID from metadata
 		docID := filename // fallback to filename
-		if headResult.Metadata["document-id"] != nil {
-			docID = *headResult.Metadata["document-id"]
+		if id, ok := metadata.UserMetadata["document-id"]; ok && id != "" {
+			docID = id
 		}
 
-		// Determine content type
-		contentType := "application/octet-stream" // default
-		if headResult.ContentType != nil {
-			contentType = *headResult.ContentType
+		contentType := metadata.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
 		}
 
 		documents = append(documents, Document{
 			ID:         docID,
 			Name:       filename,
-			Size:       *item.Size,
+			Size:       item.Size,
 			Type:       contentType,
-			UploadedAt: *item.LastModified,
+			UploadedAt: item.LastModified,
+			Checksum:   metadata.UserMetadata[storage.ChecksumMetadataKey],
 		})
 	}
 
@@ -192,5 +201,20 @@ func getDocumentsFromS3() ([]Document, error) {
 }
 
 func main() {
-	lambda.Start(handler)
+	sess, err := session.NewSession()
+	if err != nil {
+		panic(fmt.Sprintf("failed to create AWS session: %v", err))
+	}
+
+	client := storage.NewS3Client(sess, storage.NewS3ClientConfigFromEnv())
+	store := storage.NewS3ObjectStore(client, storage.DocumentBucketFromEnv())
+
+	var recorder *analytics.Recorder
+	if tableName := utils.GetAnalyticsTableName(); tableName != "" {
+		recorder = analytics.NewRecorder(dynamodb.New(sess), tableName)
+	}
+
+	handler := NewDashboardHandler(store, storage.DocumentPrefixFromEnv(), recorder)
+
+	lambda.Start(handler.Handle)
 }
@@ -5,28 +5,60 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/url"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/opensearch-project/opensearch-go/v2"
+
+	"aws-serverless-rag/internal/models"
+	"aws-serverless-rag/internal/storage"
+	"aws-serverless-rag/internal/utils"
+	"aws-serverless-rag/internal/vectorstore"
 )
 
 type DeleteRequest struct {
 	DocumentID string `json:"document_id"`
 }
 
+// DeleteResponse reports per-subsystem outcomes so a partial failure (e.g. vectors left
+// behind after the S3 object is gone) is distinguishable from a total failure.
 type DeleteResponse struct {
-	Message    string `json:"message"`
-	DocumentID string `json:"document_id"`
-	Success    bool   `json:"success"`
+	Message         string `json:"message"`
+	DocumentID      string `json:"document_id"`
+	Success         bool   `json:"success"`
+	S3Deleted       bool   `json:"s3_deleted"`
+	VectorsDeleted  bool   `json:"vectors_deleted"`
+	MetadataDeleted bool   `json:"metadata_deleted"`
+}
+
+// DeleteHandler handles document deletion requests against an injected S3 client,
+// so the list/head/delete matching logic can be unit tested without real AWS credentials.
+// vectorDeleter and failedDeletions are optional: when nil, cascade deletion into
+// OpenSearch/metadata is skipped and the S3 removal is reported on its own.
+type DeleteHandler struct {
+	s3Client        storage.S3APIClient
+	bucketName      string
+	vectorDeleter   vectorstore.Deleter
+	failedDeletions *vectorstore.FailedDeletionRecorder
 }
 
-func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+// NewDeleteHandler creates a handler backed by the given S3 client and bucket.
+func NewDeleteHandler(s3Client storage.S3APIClient, bucketName string, vectorDeleter vectorstore.Deleter, failedDeletions *vectorstore.FailedDeletionRecorder) *DeleteHandler {
+	return &DeleteHandler{
+		s3Client:        s3Client,
+		bucketName:      bucketName,
+		vectorDeleter:   vectorDeleter,
+		failedDeletions: failedDeletions,
+	}
+}
+
+func (h *DeleteHandler) Handle(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	fmt.Println("Document delete handler called")
 
 	headers := map[string]string{
@@ -36,7 +68,6 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		"Access-Control-Allow-Headers": "*",
 	}
 
-	// Handle OPTIONS request for CORS
 	if request.HTTPMethod == "OPTIONS" {
 		return events.APIGatewayProxyResponse{
 			StatusCode: 200,
@@ -53,7 +84,6 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		}, nil
 	}
 
-	// Parse document ID from path parameters
 	documentID, exists := request.PathParameters["document_id"]
 	if !exists || documentID == "" {
 		return events.APIGatewayProxyResponse{
@@ -63,97 +93,12 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		}, nil
 	}
 
-	// URL decode the document ID to handle Japanese characters and special chars
-	decodedDocumentID, err := url.QueryUnescape(documentID)
-	if err != nil {
-		log.Printf("Failed to URL decode document ID: %v", err)
-		// Use original if decode fails
-		decodedDocumentID = documentID
-	}
-
-	fmt.Printf("Deleting document ID: %s (decoded: %s)\n", documentID, decodedDocumentID)
-
-	// Initialize AWS session
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(os.Getenv("AWS_REGION")),
-	})
-	if err != nil {
-		log.Printf("Failed to create AWS session: %v", err)
-		return events.APIGatewayProxyResponse{
-			StatusCode: 500,
-			Headers:    headers,
-			Body:       `{"error": "Failed to initialize AWS session"}`,
-		}, nil
-	}
-
-	s3Client := s3.New(sess)
-
-	// Get bucket name from environment
-	bucketName := os.Getenv("S3_BUCKET_NAME")
-
-	if bucketName == "" {
-		bucketName = "aws-serverless-rag-prod-documents-prod" // fallback
-	}
-
-	// Find document in S3 by searching for files with matching document-id metadata or filename
-	listInput := &s3.ListObjectsV2Input{
-		Bucket: aws.String(bucketName),
-		Prefix: aws.String("documents/prod/"),
-	}
+	fmt.Printf("Deleting document ID: %s\n", documentID)
 
-	listResult, err := s3Client.ListObjectsV2(listInput)
+	locator := storage.NewDocumentLocator(h.s3Client, h.bucketName, "documents/prod/")
+	s3Key, err := locator.Locate(documentID)
 	if err != nil {
-		log.Printf("Failed to list S3 objects: %v", err)
-		return events.APIGatewayProxyResponse{
-			StatusCode: 500,
-			Headers:    headers,
-			Body:       `{"error": "Failed to list documents"}`,
-		}, nil
-	}
-
-	var s3Key string
-	var found bool
-
-	// Search for matching document
-	for _, item := range listResult.Contents {
-		// Skip directory markers
-		if strings.HasSuffix(*item.Key, "/") {
-			continue
-		}
-
-		// Extract filename from key
-		keyParts := strings.Split(*item.Key, "/")
-		filename := keyParts[len(keyParts)-1]
-
-		// Get object metadata
-		headInput := &s3.HeadObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    item.Key,
-		}
-
-		headResult, err := s3Client.HeadObject(headInput)
-		if err != nil {
-			continue // Skip files we can't access
-		}
-
-		// Check if this is the document we're looking for
-		docID := filename // fallback to filename
-		if headResult.Metadata["document-id"] != nil {
-			docID = *headResult.Metadata["document-id"]
-		}
-
-		// Check exact match first, then partial match
-		if docID == documentID || docID == decodedDocumentID ||
-			strings.Contains(docID, documentID) || strings.Contains(docID, decodedDocumentID) ||
-			strings.Contains(filename, documentID) || strings.Contains(filename, decodedDocumentID) {
-			s3Key = *item.Key
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		log.Printf("Document not found in S3: %s", documentID)
+		log.Printf("Document not found in S3: %s (%v)", documentID, err)
 		return events.APIGatewayProxyResponse{
 			StatusCode: 404,
 			Headers:    headers,
@@ -161,33 +106,48 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		}, nil
 	}
 
-	fmt.Printf("Deleting S3 object: bucket=%s, key=%s\n", bucketName, s3Key)
+	fmt.Printf("Deleting S3 object: bucket=%s, key=%s\n", h.bucketName, s3Key)
 
-	// Delete from S3
-	deleteObjectInput := &s3.DeleteObjectInput{
-		Bucket: aws.String(bucketName),
+	_, err = h.s3Client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(h.bucketName),
 		Key:    aws.String(s3Key),
-	}
-
-	_, err = s3Client.DeleteObject(deleteObjectInput)
+	})
 	if err != nil {
 		log.Printf("Failed to delete S3 object: %v", err)
+		mapping := models.MapAWSError(err)
+		if mapping.RetryAfter != "" {
+			headers["Retry-After"] = mapping.RetryAfter
+		}
+		body, _ := json.Marshal(mapping.Response)
 		return events.APIGatewayProxyResponse{
-			StatusCode: 500,
+			StatusCode: mapping.StatusCode,
 			Headers:    headers,
-			Body:       `{"error": "Failed to delete file from S3"}`,
+			Body:       string(body),
 		}, nil
 	}
 
 	fmt.Printf("Successfully deleted S3 object: %s\n", s3Key)
 
-	// TODO: Also delete from OpenSearch if needed
-	// For now, we'll leave vectors in OpenSearch as they won't interfere
-
 	response := DeleteResponse{
-		Message:    "Document deleted successfully",
 		DocumentID: documentID,
-		Success:    true,
+		S3Deleted:  true,
+	}
+
+	response.VectorsDeleted = h.deleteVectors(ctx, documentID)
+	// This handler has no processing-metadata (DynamoDB) store, so it deletes no
+	// metadata records; MetadataDeleted is always reported as true.
+	response.MetadataDeleted = true
+
+	response.Success = response.S3Deleted && response.VectorsDeleted && response.MetadataDeleted
+	if response.Success {
+		response.Message = "Document deleted successfully"
+	} else {
+		response.Message = "Document partially deleted"
+	}
+
+	statusCode := 200
+	if !response.Success {
+		statusCode = 207 // multi-status: some subsystems succeeded, others didn't
 	}
 
 	responseBody, err := json.Marshal(response)
@@ -201,12 +161,70 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	}
 
 	return events.APIGatewayProxyResponse{
-		StatusCode: 200,
+		StatusCode: statusCode,
 		Headers:    headers,
 		Body:       string(responseBody),
 	}, nil
 }
 
+// deleteVectors cascades the deletion into the vector index. Failures are recorded to
+// the failed_deletions table (best effort) for a retry Lambda, and reported to the
+// caller via the VectorsDeleted flag rather than failing the whole request.
+func (h *DeleteHandler) deleteVectors(ctx context.Context, documentID string) bool {
+	if h.vectorDeleter == nil {
+		return true
+	}
+
+	err := h.vectorDeleter.DeleteByDocumentID(ctx, documentID)
+	if err == nil {
+		return true
+	}
+
+	log.Printf("Failed to delete vectors for document %s: %v", documentID, err)
+
+	if h.failedDeletions != nil {
+		if recordErr := h.failedDeletions.Record(vectorstore.FailedDeletion{
+			DocumentID: documentID,
+			Subsystem:  "vectors",
+			Reason:     err.Error(),
+			FailedAt:   time.Now(),
+		}); recordErr != nil {
+			log.Printf("Failed to record failed deletion for document %s: %v", documentID, recordErr)
+		}
+	}
+
+	return false
+}
+
 func main() {
-	lambda.Start(handler)
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(os.Getenv("AWS_REGION")),
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to create AWS session: %v", err))
+	}
+
+	bucketName := os.Getenv("S3_BUCKET_NAME")
+	if bucketName == "" {
+		bucketName = "aws-serverless-rag-prod-documents-prod" // fallback
+	}
+
+	var vectorDeleter vectorstore.Deleter
+	if endpoint := utils.GetOpenSearchEndpoint(); endpoint != "" {
+		osClient, err := opensearch.NewClient(opensearch.Config{Addresses: []string{endpoint}})
+		if err != nil {
+			log.Printf("Failed to create OpenSearch client, vector cascade disabled: %v", err)
+		} else {
+			indexName := fmt.Sprintf("rag-documents-%s", utils.GetEnvironment())
+			vectorDeleter = vectorstore.NewOpenSearchDeleter(osClient, indexName, utils.NewLogger())
+		}
+	}
+
+	var failedDeletions *vectorstore.FailedDeletionRecorder
+	if tableName := os.Getenv("FAILED_DELETIONS_TABLE"); tableName != "" {
+		failedDeletions = vectorstore.NewFailedDeletionRecorder(dynamodb.New(sess), tableName)
+	}
+
+	handler := NewDeleteHandler(s3.New(sess), bucketName, vectorDeleter, failedDeletions)
+	lambda.Start(handler.Handle)
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage/mocks"
+)
+
+// fakeVectorDeleter is a minimal vectorstore.Deleter for exercising the partial-failure path.
+type fakeVectorDeleter struct {
+	err error
+}
+
+func (f *fakeVectorDeleter) DeleteByDocumentID(ctx context.Context, documentID string) error {
+	return f.err
+}
+
+func TestDeleteHandler_Handle_Success(t *testing.T) {
+	client := &mocks.S3Client{
+		ListObjectsV2Func: func(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+			return &s3.ListObjectsV2Output{
+				Contents: []*s3.Object{{Key: aws.String("documents/prod/report.pdf")}},
+			}, nil
+		},
+		HeadObjectFunc: func(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return &s3.HeadObjectOutput{Metadata: map[string]*string{"document-id": aws.String("doc-123")}}, nil
+		},
+	}
+
+	handler := NewDeleteHandler(client, "test-bucket", nil, nil)
+
+	resp, err := handler.Handle(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod:     "DELETE",
+		PathParameters: map[string]string{"document_id": "doc-123"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("got status %d, want 200: %s", resp.StatusCode, resp.Body)
+	}
+	if len(client.DeleteObjectCalls) != 1 {
+		t.Fatalf("expected DeleteObject to be called once, got %d", len(client.DeleteObjectCalls))
+	}
+	if got := *client.DeleteObjectCalls[0].Key; got != "documents/prod/report.pdf" {
+		t.Errorf("deleted wrong key: %s", got)
+	}
+}
+
+func TestDeleteHandler_Handle_NotFound(t *testing.T) {
+	client := &mocks.S3Client{}
+	handler := NewDeleteHandler(client, "test-bucket", nil, nil)
+
+	resp, err := handler.Handle(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod:     "DELETE",
+		PathParameters: map[string]string{"document_id": "missing"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 404 {
+		t.Fatalf("got status %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestDeleteHandler_Handle_PartialFailureReturns207(t *testing.T) {
+	client := &mocks.S3Client{
+		ListObjectsV2Func: func(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+			return &s3.ListObjectsV2Output{
+				Contents: []*s3.Object{{Key: aws.String("documents/prod/report.pdf")}},
+			}, nil
+		},
+		HeadObjectFunc: func(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return &s3.HeadObjectOutput{Metadata: map[string]*string{"document-id": aws.String("doc-123")}}, nil
+		},
+	}
+
+	handler := NewDeleteHandler(client, "test-bucket", &fakeVectorDeleter{err: errors.New("index unavailable")}, nil)
+
+	resp, err := handler.Handle(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod:     "DELETE",
+		PathParameters: map[string]string{"document_id": "doc-123"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 207 {
+		t.Fatalf("got status %d, want 207: %s", resp.StatusCode, resp.Body)
+	}
+}
+
+func TestDeleteHandler_Handle_MissingDocumentID(t *testing.T) {
+	handler := NewDeleteHandler(&mocks.S3Client{}, "test-bucket", nil, nil)
+
+	resp, err := handler.Handle(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod: "DELETE",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 400 {
+		t.Fatalf("got status %d, want 400", resp.StatusCode)
+	}
+}
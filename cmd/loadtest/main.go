@@ -0,0 +1,153 @@
+// Command loadtest drives mixed upload/query/embed workloads against a deployed
+// RAG API (and, for embed_batch/mixed stages, directly against a Bedrock, OpenAI-
+// compatible, or mock embedding backend) from a JSON scenario file, and prints a
+// JSON report of per-stage throughput and latency percentiles.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"aws-serverless-rag/internal/backend"
+	"aws-serverless-rag/internal/backend/openaiprovider"
+	"aws-serverless-rag/internal/embedcache"
+	"aws-serverless-rag/internal/loadtest"
+	"aws-serverless-rag/internal/services"
+	"aws-serverless-rag/internal/utils"
+	"aws-serverless-rag/pkg/ragclient"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to a JSON scenario file (required)")
+	noCache := flag.Bool("no-cache", false, "disable the in-memory embedding cache for embed_batch/mixed stages")
+	flag.Parse()
+
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "loadtest: -config is required")
+		os.Exit(2)
+	}
+
+	if err := run(*configPath, *noCache, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "loadtest: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, noCache bool, out *os.File) error {
+	scenario, err := loadtest.LoadScenario(configPath)
+	if err != nil {
+		return err
+	}
+
+	client := ragclient.NewClient(scenario.Global.Endpoint, scenario.Global.Timeout.Duration())
+	client.AuthToken = scenario.Global.AuthToken
+
+	embedder, err := newEmbedder(scenario, noCache)
+	if err != nil {
+		return err
+	}
+
+	harness := loadtest.NewHarness(client, embedder)
+	report, err := harness.Run(context.Background(), scenario)
+	if err != nil {
+		return fmt.Errorf("run failed: %w", err)
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// newEmbedder registers the "mock" backend and, if scenario.Global.Backend selects
+// one of "bedrock", "openai", "titan-v1", "titan-v2" or "cohere", that backend
+// too, then returns the selected one.
+// Only stages with kind embed_batch or mixed (see hasEmbedStage) actually call the
+// returned embedder, so a scenario without one never pays for AWS credential
+// setup. Unless noCache is set, the bedrock backend caches embeddings in-process
+// (see embedcache.LRUCache), so a scenario that repeats texts across iterations
+// doesn't pay for a redundant Bedrock call on every repeat.
+func newEmbedder(scenario *loadtest.ScenarioConfig, noCache bool) (backend.EmbeddingProvider, error) {
+	if !hasEmbedStage(scenario) {
+		return nil, nil
+	}
+
+	backend.Register("mock", backend.Backend{Embedding: backend.NewMockProvider()})
+
+	name := scenario.Global.Backend
+	if name == "" {
+		name = backend.DefaultBackendName
+	}
+
+	switch name {
+	case "mock":
+		// Already registered above.
+	case "openai":
+		// OPENAI_BASE_URL also accepts a local Ollama instance's OpenAI-compat
+		// endpoint (typically http://localhost:11434/v1), making "openai" double
+		// as the "ollama" backend without a separate implementation.
+		provider := openaiprovider.NewProvider(os.Getenv("OPENAI_BASE_URL"), os.Getenv("OPENAI_API_KEY"))
+		if model := os.Getenv("OPENAI_EMBEDDING_MODEL"); model != "" {
+			provider.EmbeddingModel = model
+		}
+		backend.Register("openai", backend.Backend{Embedding: provider})
+	case "titan-v1":
+		awsConfig, err := utils.NewAWSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize AWS config: %w", err)
+		}
+		provider := backend.NewTitanV1Provider(awsConfig.BedrockClient, utils.NewLogger())
+		backend.Register("titan-v1", backend.Backend{Embedding: provider})
+	case "titan-v2":
+		awsConfig, err := utils.NewAWSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize AWS config: %w", err)
+		}
+		backend.Register("titan-v2", backend.Backend{Embedding: backend.NewTitanV2Provider(awsConfig.BedrockClient, utils.NewLogger())})
+	case "cohere":
+		awsConfig, err := utils.NewAWSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize AWS config: %w", err)
+		}
+		backend.Register("cohere", backend.Backend{Embedding: backend.NewCohereEnglishV3Provider(awsConfig.BedrockClient, utils.NewLogger())})
+	default:
+		awsConfig, err := utils.NewAWSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize AWS config: %w", err)
+		}
+		logger := utils.NewLogger()
+		bedrockClient := services.NewBedrockClient(awsConfig, logger)
+		if !noCache {
+			bedrockClient.WithEmbeddingCache(embedcache.NewLRUCache(embedcache.DefaultLRUCapacity))
+		}
+		backend.Register(backend.DefaultBackendName, backend.Backend{
+			Embedding: backend.NewBedrockProvider(bedrockClient),
+		})
+	}
+
+	b, err := backend.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select backend %q: %w", name, err)
+	}
+	return b.Embedding, nil
+}
+
+// hasEmbedStage reports whether scenario has any stage (directly, or as a mixed
+// sub-kind) that needs an embedding backend.
+func hasEmbedStage(scenario *loadtest.ScenarioConfig) bool {
+	for _, stage := range scenario.Stages {
+		if stage.Kind == loadtest.StageEmbed {
+			return true
+		}
+		if stage.Kind == loadtest.StageMixed {
+			for _, mixed := range stage.Mixed {
+				if mixed.Kind == loadtest.StageEmbed {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
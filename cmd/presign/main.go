@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/google/uuid"
+
+	"aws-serverless-rag/internal/models"
+)
+
+// presignTTL is how long the returned PUT URL remains valid.
+const presignTTL = 15 * time.Minute
+
+type PresignRequest struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+}
+
+type PresignResponse struct {
+	DocumentID string            `json:"document_id"`
+	UploadURL  string            `json:"upload_url"`
+	S3Key      string            `json:"s3_key"`
+	Headers    map[string]string `json:"required_headers"`
+	ExpiresAt  string            `json:"expires_at"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	fmt.Println("Presign handler called")
+
+	headers := map[string]string{
+		"Content-Type":                 "application/json",
+		"Access-Control-Allow-Origin":  "*",
+		"Access-Control-Allow-Methods": "*",
+		"Access-Control-Allow-Headers": "*",
+	}
+
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers:    headers,
+			Body:       `{"message": "OK"}`,
+		}, nil
+	}
+
+	if request.HTTPMethod != "POST" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 405,
+			Headers:    headers,
+			Body:       `{"error": "Method not allowed"}`,
+		}, nil
+	}
+
+	var presignRequest PresignRequest
+	if err := json.Unmarshal([]byte(request.Body), &presignRequest); err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 400,
+			Headers:    headers,
+			Body:       fmt.Sprintf(`{"error": "Invalid request body: %s"}`, err.Error()),
+		}, nil
+	}
+
+	if presignRequest.FileName == "" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 400,
+			Headers:    headers,
+			Body:       `{"error": "file_name is required"}`,
+		}, nil
+	}
+
+	if !models.IsValidFileType(presignRequest.ContentType) {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 400,
+			Headers:    headers,
+			Body:       fmt.Sprintf(`{"error": "unsupported content_type: %s"}`, presignRequest.ContentType),
+		}, nil
+	}
+
+	response, err := createPresignedUpload(presignRequest.FileName, presignRequest.ContentType)
+	if err != nil {
+		fmt.Printf("Presign error: %v\n", err)
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Headers:    headers,
+			Body:       fmt.Sprintf(`{"error": "Failed to create upload URL: %s"}`, err.Error()),
+		}, nil
+	}
+
+	responseBody, err := json.Marshal(response)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Headers:    headers,
+			Body:       `{"error": "Failed to marshal response"}`,
+		}, nil
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Headers:    headers,
+		Body:       string(responseBody),
+	}, nil
+}
+
+// createPresignedUpload builds a time-bounded S3 PUT URL for the given file, bypassing
+// API Gateway's payload limit. The client must echo the returned headers on its PUT
+// so the object ends up tagged with the same document_id the processing pipeline expects.
+func createPresignedUpload(fileName, contentType string) (*PresignResponse, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String("ap-northeast-1"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+
+	svc := s3.New(sess)
+
+	bucketName := os.Getenv("S3_BUCKET_NAME")
+	if bucketName == "" {
+		bucketName = "aws-serverless-rag-prod-documents-prod" // fallback
+	}
+
+	documentID := uuid.New().String()
+	s3Key := fmt.Sprintf("documents/prod/%s", fileName)
+
+	req, _ := svc.PutObjectRequest(&s3.PutObjectInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(s3Key),
+		ContentType: aws.String(contentType),
+		Metadata: map[string]*string{
+			"document-id": aws.String(documentID),
+			"file-type":   aws.String(contentType),
+		},
+	})
+
+	uploadURL, err := req.Presign(presignTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign request: %w", err)
+	}
+
+	return &PresignResponse{
+		DocumentID: documentID,
+		UploadURL:  uploadURL,
+		S3Key:      s3Key,
+		Headers: map[string]string{
+			"Content-Type":           contentType,
+			"x-amz-meta-document-id": documentID,
+			"x-amz-meta-file-type":   contentType,
+		},
+		ExpiresAt: time.Now().Add(presignTTL).Format(time.RFC3339),
+	}, nil
+}
+
+func main() {
+	lambda.Start(handler)
+}
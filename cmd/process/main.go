@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -20,7 +21,11 @@ import (
 	"github.com/aws/aws-sdk-go/service/bedrockruntime"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
-	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/embedcache"
+	"aws-serverless-rag/internal/services"
+	"aws-serverless-rag/internal/storage"
+	"aws-serverless-rag/internal/utils"
 )
 
 type Document struct {
@@ -28,19 +33,25 @@ type Document struct {
 	FileName    string    `json:"file_name"`
 	FileType    string    `json:"file_type"`
 	S3Key       string    `json:"s3_key"`
-	Status      string    `json:"status"`
+	Status      string    `json:"status"` // "processed" or, if the Lambda ran out of time, "partial"
 	ProcessedAt time.Time `json:"processed_at"`
 	ChunkCount  int       `json:"chunk_count"`
 	TextLength  int       `json:"text_length"`
+	// IndexedChunkIDs lists the chunks successfully indexed so far. It's only
+	// populated for a "partial" Status, so a subsequent invocation (or an operator)
+	// can see exactly what already made it into OpenSearch; ChunkCount plus the
+	// checkpoint already cover that for a "processed" document.
+	IndexedChunkIDs []string `json:"indexed_chunk_ids,omitempty"`
 }
 
-type Chunk struct {
-	ChunkID    string    `json:"chunk_id"`
-	DocumentID string    `json:"document_id"`
-	Content    string    `json:"content"`
-	ChunkIndex int       `json:"chunk_index"`
-	CreatedAt  time.Time `json:"created_at"`
-	Vector     []float64 `json:"vector,omitempty"`
+// ProcessingCheckpoint records how far a document got through streaming
+// extraction/embedding/indexing, so a Lambda invocation that times out partway
+// through a large document can resume from LastProcessedChunkIndex instead of
+// starting over and re-indexing chunks that already made it into OpenSearch.
+type ProcessingCheckpoint struct {
+	DocumentID              string    `json:"document_id"`
+	LastProcessedChunkIndex int       `json:"last_processed_chunk_index"`
+	UpdatedAt               time.Time `json:"updated_at"`
 }
 
 type BedrockEmbeddingRequest struct {
@@ -109,7 +120,7 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	}
 
 	// Process the document
-	result, err := processDocument(requestBody.DocumentID)
+	result, err := processDocument(ctx, requestBody.DocumentID)
 	if err != nil {
 		fmt.Printf("Processing error: %v\n", err)
 		return events.APIGatewayProxyResponse{
@@ -135,7 +146,7 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	}, nil
 }
 
-func processDocument(documentID string) (*Document, error) {
+func processDocument(ctx context.Context, documentID string) (*Document, error) {
 	// Create AWS session
 	sess, err := session.NewSession(&aws.Config{
 		Region: aws.String("ap-northeast-1"),
@@ -144,22 +155,22 @@ func processDocument(documentID string) (*Document, error) {
 		return nil, fmt.Errorf("failed to create AWS session: %w", err)
 	}
 
-	s3Client := s3.New(sess)
-
-	// Get bucket name from environment variable
-	bucketName := os.Getenv("DOCUMENT_BUCKET")
-	if bucketName == "" {
-		bucketName = "aws-serverless-rag-prod-documents-prod" // fallback
+	// Build the object store selected by STORAGE_PROVIDER (s3/minio/oss/gcs), so
+	// this handler runs unmodified against any of them instead of hard-coding the
+	// AWS S3 SDK.
+	store, err := storage.NewObjectStoreFromEnv(sess)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build object store: %w", err)
 	}
 
-	// Find the document in S3 by metadata
-	s3Key, metadata, err := findDocumentInS3(s3Client, bucketName, documentID)
+	// Find the document in the store by metadata
+	objectKey, metadata, err := findDocumentInStore(ctx, store, documentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find document: %w", err)
 	}
 
 	// Extract metadata
-	fileName := getStringFromMetadata(metadata, "original-name", strings.Split(s3Key, "/")[len(strings.Split(s3Key, "/"))-1])
+	fileName := getStringFromMetadata(metadata, "original-name", strings.Split(objectKey, "/")[len(strings.Split(objectKey, "/"))-1])
 	fileType := detectFileType(fileName)
 
 	// Check if file type is supported (only .txt and .md)
@@ -169,180 +180,304 @@ func processDocument(documentID string) (*Document, error) {
 
 	fmt.Printf("Processing document: %s (%s)\n", fileName, fileType)
 
-	// Download file content from S3
-	content, err := downloadFileFromS3(s3Client, bucketName, s3Key)
+	// Stream the object instead of io.ReadAll-ing it into memory: for S3 (and
+	// MinIO/S3-compatible endpoints) this reads fixed-size byte-range windows via
+	// storage.S3RangeReader; OSS and GCS don't have a ranged reader yet, so they
+	// fall back to a full buffered download.
+	var textReader io.Reader
+	if rangeReader, ok := storage.NewRangeReaderFromEnv(sess, objectKey); ok {
+		textReader = rangeReader
+	} else {
+		content, err := store.Get(ctx, objectKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download file: %w", err)
+		}
+		if !utf8.Valid(content) {
+			return nil, fmt.Errorf("file content is not valid UTF-8")
+		}
+		textReader = strings.NewReader(string(content))
+	}
+
+	extractor := services.NewTextExtractor()
+	segments, err := extractor.ExtractTextStream(textReader, fileType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download file: %w", err)
+		return nil, fmt.Errorf("failed to start streaming extraction: %w", err)
 	}
 
-	// Extract text (for .txt and .md, this is just the raw content)
-	text := string(content)
-	if !utf8.Valid(content) {
-		return nil, fmt.Errorf("file content is not valid UTF-8")
+	// Resume from a prior invocation's checkpoint, if any: a Lambda that timed out
+	// partway through a large document doesn't re-embed and re-index chunks a
+	// previous invocation already got into OpenSearch.
+	checkpoint, err := loadCheckpoint(ctx, sess, documentID)
+	if err != nil {
+		fmt.Printf("Warning: failed to load checkpoint, processing from the start: %v\n", err)
+		checkpoint = ProcessingCheckpoint{DocumentID: documentID, LastProcessedChunkIndex: -1}
+	} else if checkpoint.LastProcessedChunkIndex >= 0 {
+		fmt.Printf("Resuming document %s after chunk %d\n", documentID, checkpoint.LastProcessedChunkIndex)
 	}
 
-	fmt.Printf("Extracted text length: %d characters\n", len(text))
+	bedrockClient := services.NewBedrockClient(&utils.AWSConfig{BedrockClient: bedrockruntime.New(sess)}, utils.NewLogger())
+	if cacheTable := os.Getenv("EMBEDDING_CACHE_TABLE_NAME"); cacheTable != "" {
+		bedrockClient = bedrockClient.WithEmbeddingCache(embedcache.NewDynamoCache(dynamodb.New(sess), cacheTable))
+	}
 
-	// Split text into chunks
-	chunks := chunkText(text, 1000) // 1000 character chunks
-	fmt.Printf("Created %d chunks\n", len(chunks))
+	opensearchEndpoint := os.Getenv("OPENSEARCH_ENDPOINT")
+	var indexer *bulkIndexer
+	if opensearchEndpoint != "" {
+		indexName, err := ensureVectorIndexExists(ctx, sess, opensearchEndpoint)
+		if err != nil {
+			fmt.Printf("Warning: failed to ensure vector index exists: %v\n", err)
+		} else {
+			indexer = newBulkIndexer(ctx, sess, opensearchEndpoint, indexName)
+		}
+	}
 
-	// Generate embeddings for chunks using Bedrock
-	bedrockClient := bedrockruntime.New(sess)
-	processedChunks, err := generateEmbeddings(bedrockClient, documentID, chunks)
-	if err != nil {
-		fmt.Printf("Warning: Failed to generate embeddings: %v\n", err)
-		// Continue without embeddings for now
-		processedChunks = chunks
+	textLength := 0
+	processedCount := 0
+	chunkIndex := -1
+	var indexedChunkIDs []string
+	stoppedForDeadline := false
+
+	// applyBulkResult records each document the indexer just confirmed (after its own
+	// per-item retry), advancing the checkpoint past it, and warns about any that still
+	// failed. Like the old per-chunk loop this replaced, a permanently-failed chunk is
+	// not retried on a future invocation once a later chunk's checkpoint write passes it.
+	applyBulkResult := func(result *bulkIndexResult) {
+		if result == nil {
+			return
+		}
+		for _, chunkID := range result.Failed {
+			fmt.Printf("Warning: failed to index %s after bulk retry\n", chunkID)
+		}
+		for _, doc := range result.Indexed {
+			processedCount++
+			indexedChunkIDs = append(indexedChunkIDs, doc.ChunkID)
+			if err := saveCheckpoint(ctx, sess, documentID, doc.ChunkIndex); err != nil {
+				fmt.Printf("Warning: failed to save checkpoint after chunk %d: %v\n", doc.ChunkIndex, err)
+			}
+		}
 	}
 
-	// Store vectors in OpenSearch if embeddings were generated
-	if len(processedChunks) > 0 && len(processedChunks[0].Vector) > 0 {
-		err = storeVectorsInOpenSearch(sess, processedChunks)
+	type pendingChunk struct {
+		index   int
+		content string
+	}
+
+	// pending accumulates up to embeddingBatchSize chunks before calling
+	// GenerateEmbeddingsPartial, so the Bedrock client's worker pool actually has a
+	// batch to fan out across instead of being handed one chunk (and therefore
+	// running with concurrency=1) at a time.
+	var pending []pendingChunk
+
+	embedAndIndexPending := func() {
+		if len(pending) == 0 {
+			return
+		}
+
+		texts := make([]string, len(pending))
+		for i, p := range pending {
+			texts[i] = p.content
+		}
+
+		vectors, failedIndices, err := bedrockClient.GenerateEmbeddingsPartial(ctx, texts)
 		if err != nil {
-			fmt.Printf("Warning: Failed to store vectors in OpenSearch: %v\n", err)
-			// Continue without failing the entire process
+			fmt.Printf("Warning: failed to embed chunks %d-%d: %v\n", pending[0].index, pending[len(pending)-1].index, err)
+			pending = pending[:0]
+			return
 		}
+
+		failed := make(map[int]bool, len(failedIndices))
+		for _, fi := range failedIndices {
+			failed[fi] = true
+		}
+
+		for i, p := range pending {
+			if failed[i] {
+				fmt.Printf("Warning: failed to embed chunk %d\n", p.index)
+				continue
+			}
+
+			vector := make([]float64, len(vectors[i]))
+			for j, v := range vectors[i] {
+				vector[j] = float64(v)
+			}
+
+			if indexer != nil {
+				doc := OpenSearchDocument{
+					DocumentID: documentID,
+					ChunkID:    fmt.Sprintf("chunk_%d", p.index),
+					Content:    p.content,
+					ChunkIndex: p.index,
+					Vector:     vector,
+					CreatedAt:  time.Now(),
+				}
+				result, err := indexer.Add(doc)
+				if err != nil {
+					fmt.Printf("Warning: failed to bulk-index chunk %d: %v\n", p.index, err)
+				}
+				applyBulkResult(result)
+			} else {
+				processedCount++
+				indexedChunkIDs = append(indexedChunkIDs, fmt.Sprintf("chunk_%d", p.index))
+				if err := saveCheckpoint(ctx, sess, documentID, p.index); err != nil {
+					fmt.Printf("Warning: failed to save checkpoint after chunk %d: %v\n", p.index, err)
+				}
+			}
+		}
+
+		pending = pending[:0]
+	}
+
+	for content := range segments {
+		chunkIndex++
+		textLength += len(content)
+
+		if chunkIndex <= checkpoint.LastProcessedChunkIndex {
+			continue // already embedded and indexed by a previous invocation
+		}
+
+		// Stop starting new chunks once we're within lambdaDeadlineReserve of the
+		// invocation's deadline, so an embed/index call in flight when the Lambda is
+		// killed doesn't leave DynamoDB/OpenSearch without a record of how far we got.
+		// The segments channel is drained in the background so ExtractTextStream's
+		// goroutine isn't left blocked sending to it forever.
+		if deadlineApproaching(ctx, lambdaDeadlineReserve()) {
+			fmt.Printf("Warning: approaching Lambda deadline, stopping before chunk %d\n", chunkIndex)
+			stoppedForDeadline = true
+			go func() {
+				for range segments {
+				}
+			}()
+			break
+		}
+
+		pending = append(pending, pendingChunk{index: chunkIndex, content: content})
+		if len(pending) >= embeddingBatchSize {
+			embedAndIndexPending()
+		}
+	}
+	embedAndIndexPending()
+
+	if indexer != nil {
+		result, err := indexer.Flush()
+		if err != nil {
+			fmt.Printf("Warning: failed to flush final bulk batch: %v\n", err)
+		}
+		applyBulkResult(result)
+	}
+
+	status := "processed"
+	if stoppedForDeadline {
+		status = "partial"
 	}
 
 	// Create document record
 	document := &Document{
-		ID:          documentID,
-		FileName:    fileName,
-		FileType:    fileType,
-		S3Key:       s3Key,
-		Status:      "processed",
-		ProcessedAt: time.Now(),
-		ChunkCount:  len(processedChunks),
-		TextLength:  len(text),
+		ID:              documentID,
+		FileName:        fileName,
+		FileType:        fileType,
+		S3Key:           objectKey,
+		Status:          status,
+		ProcessedAt:     time.Now(),
+		ChunkCount:      len(indexedChunkIDs),
+		TextLength:      textLength,
+		IndexedChunkIDs: indexedChunkIDs,
 	}
 
 	// Save document metadata to DynamoDB
-	err = saveToDynamoDB(sess, document)
+	err = saveToDynamoDB(ctx, sess, document)
 	if err != nil {
 		fmt.Printf("Warning: Failed to save to DynamoDB: %v\n", err)
 		// Don't fail the entire process, just log the warning
 	}
 
-	// For now, we just return the document info
-	// In Phase 2, we will add vector storage to OpenSearch
-	fmt.Printf("Document processed successfully: %s\n", documentID)
+	if stoppedForDeadline {
+		fmt.Printf("Document processing left partial: %s (%d chunks indexed so far, resume with another invocation)\n", documentID, len(indexedChunkIDs))
+	} else {
+		fmt.Printf("Document processed successfully: %s (%d/%d chunks embedded and indexed)\n", documentID, processedCount, chunkIndex+1)
+	}
 
 	return document, nil
 }
 
-func findDocumentInS3(s3Client *s3.S3, bucketName, documentID string) (string, map[string]*string, error) {
-	fmt.Printf("Searching for document ID: %s in bucket: %s\n", documentID, bucketName)
+// embeddingBatchSize is how many streamed chunks processDocument/ReindexAll
+// accumulate before calling GenerateEmbeddingsPartial, so the batch is large enough
+// for BedrockClient's worker pool (see BEDROCK_EMBEDDING_CONCURRENCY) to actually
+// fan requests out concurrently instead of degenerating to one call at a time.
+const embeddingBatchSize = 8
+
+// defaultLambdaDeadlineReserveSeconds is how long before ctx.Deadline() the ingestion loop
+// stops starting new chunks, leaving time to flush the bulk indexer and write a
+// partial-status document instead of being killed mid-chunk. Overridable via
+// DEADLINE_RESERVE_SECONDS for local testing against a shorter or longer budget.
+const defaultLambdaDeadlineReserveSeconds = 5
+
+func lambdaDeadlineReserve() time.Duration {
+	if n, err := strconv.Atoi(os.Getenv("DEADLINE_RESERVE_SECONDS")); err == nil && n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	return defaultLambdaDeadlineReserveSeconds * time.Second
+}
 
-	// List objects in the documents folder
-	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(bucketName),
-		Prefix: aws.String("documents/prod/"),
+// deadlineApproaching reports whether ctx has a deadline and less than reserve remains
+// before it. A context with no deadline (e.g. in tests, or a Lambda runtime that
+// doesn't set one) never reports true.
+func deadlineApproaching(ctx context.Context, reserve time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return false
 	}
+	return time.Until(deadline) < reserve
+}
+
+// findDocumentInStore searches the document prefix (DOCUMENT_PREFIX, defaulting to
+// "documents/prod/") for the object whose "document-id" user metadata or filename
+// matches documentID. It depends only on storage.ObjectStore, so it works the same
+// way against S3, MinIO, OSS, or GCS.
+func findDocumentInStore(ctx context.Context, store storage.ObjectStore, documentID string) (string, map[string]string, error) {
+	prefix := storage.DocumentPrefixFromEnv()
+	fmt.Printf("Searching for document ID: %s under prefix: %s\n", documentID, prefix)
 
-	result, err := s3Client.ListObjectsV2(input)
+	objects, err := store.List(ctx, prefix)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to list S3 objects: %w", err)
+		return "", nil, fmt.Errorf("failed to list objects: %w", err)
 	}
 
-	fmt.Printf("Found %d objects with prefix 'documents/prod/'\n", len(result.Contents))
+	fmt.Printf("Found %d objects with prefix '%s'\n", len(objects), prefix)
 
 	// Find object with matching document ID in metadata
-	for _, item := range result.Contents {
-		if strings.HasSuffix(*item.Key, "/") {
-			continue // Skip directory markers
-		}
-
-		fmt.Printf("Checking object: %s\n", *item.Key)
+	for _, item := range objects {
+		fmt.Printf("Checking object: %s\n", item.Key)
 
-		// Get object metadata
-		headInput := &s3.HeadObjectInput{
-			Bucket: aws.String(bucketName),
-			Key:    item.Key,
-		}
-
-		headResult, err := s3Client.HeadObject(headInput)
+		metadata, err := store.Head(ctx, item.Key)
 		if err != nil {
-			fmt.Printf("Failed to get metadata for %s: %v\n", *item.Key, err)
+			fmt.Printf("Failed to get metadata for %s: %v\n", item.Key, err)
 			continue // Skip if can't get metadata
 		}
 
-		// Log all metadata for debugging
-		fmt.Printf("Metadata for %s:\n", *item.Key)
-		for k, v := range headResult.Metadata {
-			if v != nil {
-				fmt.Printf("  %s: %s\n", k, *v)
-			}
-		}
-
 		// Check if document ID matches (try both formats)
-		var docID *string
-		if id := headResult.Metadata["Document-Id"]; id != nil {
-			docID = id
-		} else if id := headResult.Metadata["document-id"]; id != nil {
-			docID = id
+		docID := metadata.UserMetadata["Document-Id"]
+		if docID == "" {
+			docID = metadata.UserMetadata["document-id"]
 		}
 
 		// Extract filename from key for comparison
-		keyParts := strings.Split(*item.Key, "/")
+		keyParts := strings.Split(item.Key, "/")
 		filename := keyParts[len(keyParts)-1]
-
-		fmt.Printf("Comparing:\n  Searching for: '%s' (len=%d)\n  DocID: '%s'\n  Filename: '%s' (len=%d)\n",
-			documentID, len(documentID),
-			func() string {
-				if docID != nil {
-					return *docID
-				} else {
-					return "nil"
-				}
-			}(),
-			filename, len(filename))
-
-		// Debug: Check if strings match
 		filenameMatch := filename == documentID
-		fmt.Printf("  Filename match: %t\n", filenameMatch)
-		if !filenameMatch && len(filename) == len(documentID) {
-			for i, r := range documentID {
-				if i < len(filename) && rune(filename[i]) != r {
-					fmt.Printf("  Diff at pos %d: got %q, want %q\n", i, filename[i], r)
-					break
-				}
-			}
-		}
 
 		// Match by document ID or filename
-		if (docID != nil && *docID == documentID) || filenameMatch {
-			fmt.Printf("Found matching document: %s\n", *item.Key)
-			return *item.Key, headResult.Metadata, nil
+		if docID == documentID || filenameMatch {
+			fmt.Printf("Found matching document: %s\n", item.Key)
+			return item.Key, metadata.UserMetadata, nil
 		}
 	}
 
 	return "", nil, fmt.Errorf("document with ID %s not found", documentID)
 }
 
-func downloadFileFromS3(s3Client *s3.S3, bucketName, s3Key string) ([]byte, error) {
-	input := &s3.GetObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(s3Key),
-	}
-
-	result, err := s3Client.GetObject(input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get object from S3: %w", err)
-	}
-	defer result.Body.Close()
-
-	content, err := io.ReadAll(result.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read object content: %w", err)
-	}
-
-	return content, nil
-}
-
-func getStringFromMetadata(metadata map[string]*string, key, defaultValue string) string {
-	if value, exists := metadata[key]; exists && value != nil {
-		return *value
+func getStringFromMetadata(metadata map[string]string, key, defaultValue string) string {
+	if value, exists := metadata[key]; exists && value != "" {
+		return value
 	}
 	return defaultValue
 }
@@ -369,216 +504,450 @@ func isSupportedFileType(fileType string) bool {
 	return false
 }
 
-func chunkText(text string, maxChunkSize int) []Chunk {
-	var chunks []Chunk
-	chunkIndex := 0
+// vectorIndexAlias is the name callers (and the query Lambda's OpenSearch config, see
+// internal/opensearch) address documents through. It never names a concrete index
+// directly; it's kept pointed at whichever generation (vectorIndexAlias-000001,
+// -000002, ...) is currently live, so ReindexAll can cut over to a new generation
+// without any caller-visible downtime or config change.
+const vectorIndexAlias = "rag-documents-prod"
+
+// defaultBulkMaxBatchBytes is the default ndjson payload size _bulk requests are
+// batched up to before being flushed, overridable via OPENSEARCH_BULK_MAX_BYTES.
+const defaultBulkMaxBatchBytes = 5 * 1024 * 1024
+
+// vectorIndexMapping builds the mapping used for every generation of the vector
+// index, parameterized by embedding dimension so ReindexAll can create a new
+// generation for a different embedding model (e.g. Titan v1's 1536 dims vs v2's
+// 1024) without duplicating this mapping by hand.
+func vectorIndexMapping(dimension int) map[string]interface{} {
+	return map[string]interface{}{
+		"mappings": map[string]interface{}{
+			"properties": map[string]interface{}{
+				"document_id": map[string]interface{}{
+					"type": "keyword",
+				},
+				"chunk_id": map[string]interface{}{
+					"type": "keyword",
+				},
+				"content": map[string]interface{}{
+					"type":     "text",
+					"analyzer": "standard",
+				},
+				"chunk_index": map[string]interface{}{
+					"type": "integer",
+				},
+				"vector": map[string]interface{}{
+					"type":      "knn_vector",
+					"dimension": dimension,
+					"method": map[string]interface{}{
+						"name":       "hnsw",
+						"space_type": "cosinesimilarity", // Unified with services
+						"engine":     "nmslib",
+						"parameters": map[string]interface{}{
+							"ef_construction": 512,
+							"m":               16,
+						},
+					},
+				},
+				"created_at": map[string]interface{}{
+					"type":   "date",
+					"format": "strict_date_optional_time||epoch_millis",
+				},
+			},
+		},
+		"settings": map[string]interface{}{
+			"index": map[string]interface{}{
+				"knn":                      true,
+				"knn.algo_param.ef_search": 512,
+			},
+		},
+	}
+}
 
-	// Simple chunking by character count with word boundary preservation
-	for i := 0; i < len(text); i += maxChunkSize {
-		end := i + maxChunkSize
-		if end > len(text) {
-			end = len(text)
+// aliasTargets resolves the concrete index names alias currently points at, returning
+// (nil, nil) if the alias doesn't exist yet.
+func aliasTargets(ctx context.Context, sess *session.Session, endpoint, alias string) ([]string, error) {
+	respBody, err := makeOpenSearchRawRequest(ctx, sess, "GET", endpoint, "/_alias/"+alias, "application/json", nil)
+	if err != nil {
+		if strings.Contains(err.Error(), "status 404") {
+			return nil, nil
 		}
+		return nil, err
+	}
 
-		chunkText := text[i:end]
-
-		// Try to end at word boundary if not at the end of text
-		if end < len(text) {
-			if lastSpace := strings.LastIndex(chunkText, " "); lastSpace > maxChunkSize/2 {
-				chunkText = chunkText[:lastSpace]
-				end = i + lastSpace
-			}
-		}
+	var parsed map[string]json.RawMessage
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse alias response: %w", err)
+	}
 
-		// Skip empty chunks
-		if strings.TrimSpace(chunkText) == "" {
-			continue
-		}
+	indexes := make([]string, 0, len(parsed))
+	for index := range parsed {
+		indexes = append(indexes, index)
+	}
+	return indexes, nil
+}
 
-		chunk := Chunk{
-			ChunkID:    fmt.Sprintf("chunk_%d", chunkIndex),
-			Content:    strings.TrimSpace(chunkText),
-			ChunkIndex: chunkIndex,
-			CreatedAt:  time.Now(),
-		}
+// nextIndexGeneration returns currentIndex with its trailing "-NNNNNN" generation
+// suffix incremented by one, e.g. "rag-documents-prod-000001" -> "rag-documents-prod-000002".
+func nextIndexGeneration(currentIndex string) (string, error) {
+	sep := strings.LastIndex(currentIndex, "-")
+	if sep == -1 {
+		return "", fmt.Errorf("index name %q has no generation suffix", currentIndex)
+	}
+	generation, err := strconv.Atoi(currentIndex[sep+1:])
+	if err != nil {
+		return "", fmt.Errorf("index name %q has a non-numeric generation suffix: %w", currentIndex, err)
+	}
+	return fmt.Sprintf("%s-%06d", currentIndex[:sep], generation+1), nil
+}
 
-		chunks = append(chunks, chunk)
-		chunkIndex++
+// ensureVectorIndexExists makes sure vectorIndexAlias resolves to a concrete index,
+// creating the first generation (vectorIndexAlias-000001) and pointing the alias at
+// it if this is the first time the Lambda has run against this OpenSearch domain. It
+// returns the concrete index name documents should be written to.
+func ensureVectorIndexExists(ctx context.Context, sess *session.Session, endpoint string) (string, error) {
+	targets, err := aliasTargets(ctx, sess, endpoint, vectorIndexAlias)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve alias %s: %w", vectorIndexAlias, err)
+	}
+	if len(targets) > 0 {
+		fmt.Printf("Alias %s already points at %s, skipping creation\n", vectorIndexAlias, targets[0])
+		return targets[0], nil
+	}
 
-		// Adjust loop counter for word boundary
-		if end < len(text) && end != i+maxChunkSize {
-			i = end - maxChunkSize
-		}
+	indexName := vectorIndexAlias + "-000001"
+	fmt.Printf("Creating index %s and pointing alias %s at it\n", indexName, vectorIndexAlias)
+	if err := makeOpenSearchRequest(ctx, sess, "PUT", endpoint, "/"+indexName, vectorIndexMapping(1536)); err != nil {
+		return "", fmt.Errorf("failed to create index %s: %w", indexName, err)
+	}
+	if err := swapAlias(ctx, sess, endpoint, vectorIndexAlias, "", indexName); err != nil {
+		return "", fmt.Errorf("failed to point alias %s at %s: %w", vectorIndexAlias, indexName, err)
 	}
+	return indexName, nil
+}
 
-	return chunks
+// swapAlias atomically repoints alias from oldIndex (if non-empty) to newIndex using
+// the _aliases actions API, so readers never see alias resolve to neither index or to
+// both at once.
+func swapAlias(ctx context.Context, sess *session.Session, endpoint, alias, oldIndex, newIndex string) error {
+	actions := []map[string]interface{}{
+		{"add": map[string]interface{}{"index": newIndex, "alias": alias}},
+	}
+	if oldIndex != "" {
+		actions = append(actions, map[string]interface{}{"remove": map[string]interface{}{"index": oldIndex, "alias": alias}})
+	}
+	return makeOpenSearchRequest(ctx, sess, "POST", endpoint, "/_aliases", map[string]interface{}{"actions": actions})
 }
 
-func generateEmbeddings(bedrockClient *bedrockruntime.BedrockRuntime, documentID string, chunks []Chunk) ([]Chunk, error) {
-	// Use Titan Text Embeddings V2 (if available) or fallback to V1
-	modelID := "amazon.titan-embed-text-v2:0"
+// ReindexAll creates a new generation of the vector index with newMapping (or, if
+// newMapping is empty, the standard mapping built for newDimension), replays every
+// document DynamoDB has a record of into it by re-downloading, re-chunking, and
+// re-embedding each one, and then atomically swaps vectorIndexAlias over to the new
+// generation. This is how an embedding model upgrade (e.g. Titan v1's 1536 dims to
+// v2's 1024) rolls out without downtime: queries keep hitting the old generation
+// through the alias until the new one is fully populated.
+//
+// bedrockClient must already be configured for the target embedding model; ReindexAll
+// only handles re-chunking and re-indexing, not choosing which model produces
+// newDimension-sized vectors.
+func ReindexAll(ctx context.Context, sess *session.Session, store storage.ObjectStore, bedrockClient *services.BedrockClient, endpoint string, newDimension int, newMapping map[string]interface{}) error {
+	currentTargets, err := aliasTargets(ctx, sess, endpoint, vectorIndexAlias)
+	if err != nil {
+		return fmt.Errorf("failed to resolve alias %s: %w", vectorIndexAlias, err)
+	}
+	if len(currentTargets) == 0 {
+		return fmt.Errorf("alias %s has no current target to reindex from", vectorIndexAlias)
+	}
+	currentIndex := currentTargets[0]
 
-	processedChunks := make([]Chunk, len(chunks))
+	newIndex, err := nextIndexGeneration(currentIndex)
+	if err != nil {
+		return fmt.Errorf("failed to compute next index generation: %w", err)
+	}
 
-	for i, chunk := range chunks {
-		// Set document ID for chunk
-		chunk.DocumentID = documentID
+	mapping := newMapping
+	if len(mapping) == 0 {
+		mapping = vectorIndexMapping(newDimension)
+	}
 
-		// Create embedding request
-		requestBody := BedrockEmbeddingRequest{
-			InputText: chunk.Content,
-		}
+	fmt.Printf("ReindexAll: creating %s\n", newIndex)
+	if err := makeOpenSearchRequest(ctx, sess, "PUT", endpoint, "/"+newIndex, mapping); err != nil {
+		return fmt.Errorf("failed to create index %s: %w", newIndex, err)
+	}
 
-		requestJSON, err := json.Marshal(requestBody)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal embedding request: %w", err)
-		}
+	documents, err := scanAllDocuments(ctx, sess)
+	if err != nil {
+		return fmt.Errorf("failed to scan documents table: %w", err)
+	}
 
-		// Call Bedrock to generate embedding
-		input := &bedrockruntime.InvokeModelInput{
-			ModelId:     aws.String(modelID),
-			ContentType: aws.String("application/json"),
-			Accept:      aws.String("application/json"),
-			Body:        requestJSON,
-		}
+	extractor := services.NewTextExtractor()
+	indexer := newBulkIndexer(ctx, sess, endpoint, newIndex)
 
-		result, err := bedrockClient.InvokeModel(input)
+	for _, document := range documents {
+		content, err := store.Get(ctx, document.S3Key)
 		if err != nil {
-			// If V2 fails, try V1
-			if strings.Contains(err.Error(), "AccessDeniedException") && modelID == "amazon.titan-embed-text-v2:0" {
-				fmt.Printf("V2 embeddings not available, trying V1...\n")
-				modelID = "amazon.titan-embed-text-v1"
-				input.ModelId = aws.String(modelID)
-				result, err = bedrockClient.InvokeModel(input)
+			fmt.Printf("Warning: ReindexAll: failed to download %s (%s): %v\n", document.ID, document.S3Key, err)
+			continue
+		}
+
+		chunks := extractor.ChunkText(string(content), services.ChunkTextOptions{
+			MaxTokens: services.DefaultMaxTokens,
+			Strategy:  services.ChunkStrategySentence,
+		})
+
+		// Embed in windows of embeddingBatchSize rather than one chunk at a time, so
+		// BedrockClient's worker pool has a real batch to fan out across.
+		for start := 0; start < len(chunks); start += embeddingBatchSize {
+			end := start + embeddingBatchSize
+			if end > len(chunks) {
+				end = len(chunks)
 			}
+			batch := chunks[start:end]
 
+			vectors, failedIndices, err := bedrockClient.GenerateEmbeddingsPartial(ctx, batch)
 			if err != nil {
-				return nil, fmt.Errorf("failed to generate embedding for chunk %d: %w", i, err)
+				fmt.Printf("Warning: ReindexAll: failed to embed %s chunks %d-%d: %v\n", document.ID, start, end-1, err)
+				continue
 			}
-		}
 
-		// Parse embedding response
-		var response BedrockEmbeddingResponse
-		if err := json.Unmarshal(result.Body, &response); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal embedding response: %w", err)
+			failed := make(map[int]bool, len(failedIndices))
+			for _, fi := range failedIndices {
+				failed[fi] = true
+			}
+
+			for i, chunkText := range batch {
+				chunkIndex := start + i
+				if failed[i] {
+					fmt.Printf("Warning: ReindexAll: failed to embed %s chunk %d\n", document.ID, chunkIndex)
+					continue
+				}
+
+				vector := make([]float64, len(vectors[i]))
+				for j, v := range vectors[i] {
+					vector[j] = float64(v)
+				}
+
+				result, err := indexer.Add(OpenSearchDocument{
+					DocumentID: document.ID,
+					ChunkID:    fmt.Sprintf("chunk_%d", chunkIndex),
+					Content:    chunkText,
+					ChunkIndex: chunkIndex,
+					Vector:     vector,
+					CreatedAt:  time.Now(),
+				})
+				if err != nil {
+					fmt.Printf("Warning: ReindexAll: failed to bulk-index %s chunk %d: %v\n", document.ID, chunkIndex, err)
+				}
+				for _, chunkID := range result.failedIDs() {
+					fmt.Printf("Warning: ReindexAll: failed to index %s %s after bulk retry\n", document.ID, chunkID)
+				}
+			}
 		}
+	}
 
-		// Add vector to chunk
-		chunk.Vector = response.Embedding
-		processedChunks[i] = chunk
+	if result, err := indexer.Flush(); err != nil {
+		fmt.Printf("Warning: ReindexAll: failed to flush final bulk batch: %v\n", err)
+	} else {
+		for _, chunkID := range result.failedIDs() {
+			fmt.Printf("Warning: ReindexAll: failed to index %s after bulk retry\n", chunkID)
+		}
+	}
 
-		fmt.Printf("Generated embedding for chunk %d (dimension: %d)\n", i, len(response.Embedding))
+	fmt.Printf("ReindexAll: swapping alias %s from %s to %s\n", vectorIndexAlias, currentIndex, newIndex)
+	if err := swapAlias(ctx, sess, endpoint, vectorIndexAlias, currentIndex, newIndex); err != nil {
+		return fmt.Errorf("failed to swap alias %s from %s to %s: %w", vectorIndexAlias, currentIndex, newIndex, err)
 	}
 
-	fmt.Printf("Successfully generated embeddings for %d chunks\n", len(processedChunks))
-	return processedChunks, nil
+	return nil
 }
 
-func storeVectorsInOpenSearch(sess *session.Session, chunks []Chunk) error {
-	// Get OpenSearch endpoint from environment variable
-	opensearchEndpoint := os.Getenv("OPENSEARCH_ENDPOINT")
-	if opensearchEndpoint == "" {
-		return fmt.Errorf("OPENSEARCH_ENDPOINT environment variable not set")
+// scanAllDocuments reads every document record out of the documents table, paging
+// through DynamoDB's Scan with LastEvaluatedKey until it's exhausted.
+func scanAllDocuments(ctx context.Context, sess *session.Session) ([]Document, error) {
+	tableName := os.Getenv("DYNAMODB_TABLE_NAME")
+	if tableName == "" {
+		tableName = "aws-serverless-rag-prod-documents-prod" // fallback, matches saveToDynamoDB
 	}
+	dynamoClient := dynamodb.New(sess)
 
-	// Ensure index exists first
-	err := ensureVectorIndexExists(sess, opensearchEndpoint)
-	if err != nil {
-		return fmt.Errorf("failed to ensure vector index exists: %w", err)
-	}
+	var documents []Document
+	input := &dynamodb.ScanInput{TableName: aws.String(tableName)}
+	for {
+		result, err := dynamoClient.ScanWithContext(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", tableName, err)
+		}
 
-	// Store each chunk as a document
-	for i, chunk := range chunks {
-		if len(chunk.Vector) == 0 {
-			fmt.Printf("Skipping chunk %d: no vector data\n", i)
-			continue
+		for _, item := range result.Items {
+			var document Document
+			if err := dynamodbattribute.UnmarshalMap(item, &document); err != nil {
+				fmt.Printf("Warning: failed to unmarshal document record, skipping: %v\n", err)
+				continue
+			}
+			documents = append(documents, document)
 		}
 
-		doc := OpenSearchDocument{
-			DocumentID: chunk.DocumentID,
-			ChunkID:    chunk.ChunkID,
-			Content:    chunk.Content,
-			ChunkIndex: chunk.ChunkIndex,
-			Vector:     chunk.Vector,
-			CreatedAt:  chunk.CreatedAt,
+		if result.LastEvaluatedKey == nil {
+			return documents, nil
 		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// bulkIndexResult is what a bulkIndexer flush produced: Indexed are the documents
+// confirmed written (whether on the first bulk attempt or after an individual retry),
+// Failed are the ChunkIDs that still failed after that retry.
+type bulkIndexResult struct {
+	Indexed []OpenSearchDocument
+	Failed  []string
+}
+
+func (r *bulkIndexResult) failedIDs() []string {
+	if r == nil {
+		return nil
+	}
+	return r.Failed
+}
+
+// bulkIndexer batches OpenSearchDocument writes into ndjson payloads for OpenSearch's
+// _bulk endpoint, flushing once the buffered payload would exceed maxBatchBytes (and
+// on an explicit final Flush), rather than the one-PUT-per-chunk indexDocument calls
+// this replaced. A batch that comes back with per-item errors has just the failed
+// items retried individually, so one bad chunk doesn't cost the whole batch.
+type bulkIndexer struct {
+	ctx           context.Context
+	sess          *session.Session
+	endpoint      string
+	indexName     string
+	maxBatchBytes int
+
+	buf  bytes.Buffer
+	docs []OpenSearchDocument
+}
+
+func newBulkIndexer(ctx context.Context, sess *session.Session, endpoint, indexName string) *bulkIndexer {
+	maxBatchBytes := defaultBulkMaxBatchBytes
+	if n, err := strconv.Atoi(os.Getenv("OPENSEARCH_BULK_MAX_BYTES")); err == nil && n > 0 {
+		maxBatchBytes = n
+	}
+	return &bulkIndexer{ctx: ctx, sess: sess, endpoint: endpoint, indexName: indexName, maxBatchBytes: maxBatchBytes}
+}
+
+// Add appends doc to the pending batch, flushing the batch first (and returning that
+// flush's result) if doc would push it over maxBatchBytes.
+func (b *bulkIndexer) Add(doc OpenSearchDocument) (*bulkIndexResult, error) {
+	action, err := json.Marshal(map[string]interface{}{
+		"index": map[string]interface{}{"_index": b.indexName, "_id": doc.ChunkID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal bulk action: %w", err)
+	}
+	source, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal document: %w", err)
+	}
 
-		err := indexDocument(sess, opensearchEndpoint, doc)
+	var flushed *bulkIndexResult
+	entrySize := len(action) + len(source) + 2
+	if b.buf.Len() > 0 && b.buf.Len()+entrySize > b.maxBatchBytes {
+		flushed, err = b.Flush()
 		if err != nil {
-			fmt.Printf("Failed to index chunk %d: %v\n", i, err)
-			// Continue with other chunks
-		} else {
-			fmt.Printf("Successfully indexed chunk %d to OpenSearch\n", i)
+			return flushed, err
 		}
 	}
 
-	return nil
+	b.buf.Write(action)
+	b.buf.WriteByte('\n')
+	b.buf.Write(source)
+	b.buf.WriteByte('\n')
+	b.docs = append(b.docs, doc)
+
+	return flushed, nil
 }
 
-func ensureVectorIndexExists(sess *session.Session, endpoint string) error {
-	indexMapping := map[string]interface{}{
-		"mappings": map[string]interface{}{
-			"properties": map[string]interface{}{
-				"document_id": map[string]interface{}{
-					"type": "keyword",
-				},
-				"chunk_id": map[string]interface{}{
-					"type": "keyword",
-				},
-				"content": map[string]interface{}{
-					"type":     "text",
-					"analyzer": "standard",
-				},
-				"chunk_index": map[string]interface{}{
-					"type": "integer",
-				},
-				"vector": map[string]interface{}{
-					"type":      "knn_vector",
-					"dimension": 1536, // Titan embedding dimension - unified with services
-					"method": map[string]interface{}{
-						"name":       "hnsw",
-						"space_type": "cosinesimilarity", // Unified with services
-						"engine":     "nmslib",
-						"parameters": map[string]interface{}{
-							"ef_construction": 512,
-							"m":               16,
-						},
-					},
-				},
-				"created_at": map[string]interface{}{
-					"type":   "date",
-					"format": "strict_date_optional_time||epoch_millis",
-				},
-			},
-		},
-		"settings": map[string]interface{}{
-			"index": map[string]interface{}{
-				"knn":                      true,
-				"knn.algo_param.ef_search": 512,
-			},
-		},
+// bulkResponseItem is the subset of a single _bulk response item this Lambda reads.
+type bulkResponseItem struct {
+	Index struct {
+		ID     string `json:"_id"`
+		Status int    `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error,omitempty"`
+	} `json:"index"`
+}
+
+type bulkResponse struct {
+	Errors bool               `json:"errors"`
+	Items  []bulkResponseItem `json:"items"`
+}
+
+// Flush sends the pending batch to _bulk and clears it, whether or not this call was
+// triggered automatically by Add. It returns nil, nil if there was nothing pending.
+func (b *bulkIndexer) Flush() (*bulkIndexResult, error) {
+	if len(b.docs) == 0 {
+		return nil, nil
 	}
 
-	// First check if index exists
-	err := makeOpenSearchRequest(sess, "HEAD", endpoint, "/rag-documents-prod", nil)
-	if err == nil {
-		// Index exists, no need to create
-		fmt.Println("Index rag-documents-prod already exists, skipping creation")
-		return nil
+	docs := b.docs
+	body := append([]byte(nil), b.buf.Bytes()...)
+	b.docs = nil
+	b.buf.Reset()
+
+	respBody, err := makeOpenSearchRawRequest(b.ctx, b.sess, "POST", b.endpoint, "/_bulk", "application/x-ndjson", body)
+	if err != nil {
+		// The whole request failed at the transport/cluster level rather than
+		// per-item, so there's no per-item status to act on: fall back to
+		// indexing each document one at a time.
+		return b.retryIndividually(docs), nil
+	}
+
+	var parsed bulkResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return b.retryIndividually(docs), nil
+	}
+	if !parsed.Errors {
+		return &bulkIndexResult{Indexed: docs}, nil
+	}
+
+	result := &bulkIndexResult{}
+	var toRetry []OpenSearchDocument
+	for i, item := range parsed.Items {
+		if i >= len(docs) {
+			break
+		}
+		if item.Index.Error == nil && (item.Index.Status == 200 || item.Index.Status == 201) {
+			result.Indexed = append(result.Indexed, docs[i])
+		} else {
+			toRetry = append(toRetry, docs[i])
+		}
 	}
 
-	// Index doesn't exist, create it
-	fmt.Println("Creating index rag-documents-prod")
-	return makeOpenSearchRequest(sess, "PUT", endpoint, "/rag-documents-prod", indexMapping)
+	retried := b.retryIndividually(toRetry)
+	result.Indexed = append(result.Indexed, retried.Indexed...)
+	result.Failed = append(result.Failed, retried.Failed...)
+	return result, nil
+}
+
+func (b *bulkIndexer) retryIndividually(docs []OpenSearchDocument) *bulkIndexResult {
+	result := &bulkIndexResult{}
+	for _, doc := range docs {
+		if err := indexDocument(b.ctx, b.sess, b.endpoint, b.indexName, doc); err != nil {
+			result.Failed = append(result.Failed, doc.ChunkID)
+			continue
+		}
+		result.Indexed = append(result.Indexed, doc)
+	}
+	return result
 }
 
-func indexDocument(sess *session.Session, endpoint string, doc OpenSearchDocument) error {
+func indexDocument(ctx context.Context, sess *session.Session, endpoint, indexName string, doc OpenSearchDocument) error {
 	// Use chunk_id as document ID for OpenSearch
-	path := fmt.Sprintf("/rag-documents-prod/_doc/%s", doc.ChunkID)
-	return makeOpenSearchRequest(sess, "PUT", endpoint, path, doc)
+	path := fmt.Sprintf("/%s/_doc/%s", indexName, doc.ChunkID)
+	return makeOpenSearchRequest(ctx, sess, "PUT", endpoint, path, doc)
 }
 
-func makeOpenSearchRequest(sess *session.Session, method, endpoint, path string, body interface{}) error {
+func makeOpenSearchRequest(ctx context.Context, sess *session.Session, method, endpoint, path string, body interface{}) error {
 	var reqBody []byte
 	var err error
 
@@ -589,37 +958,47 @@ func makeOpenSearchRequest(sess *session.Session, method, endpoint, path string,
 		}
 	}
 
-	// Create the request
+	_, err = makeOpenSearchRawRequest(ctx, sess, method, endpoint, path, "application/json", reqBody)
+	return err
+}
+
+// makeOpenSearchRawRequest signs and sends a request with an arbitrary content type
+// and body (JSON for everything except _bulk, which needs application/x-ndjson), and
+// returns the raw response body so callers that need to parse it (alias resolution,
+// _bulk per-item results) can do so themselves. The request is bound to ctx so a
+// Bedrock/Lambda deadline that expires mid-request cancels it instead of running for
+// the full client timeout.
+func makeOpenSearchRawRequest(ctx context.Context, sess *session.Session, method, endpoint, path, contentType string, reqBody []byte) ([]byte, error) {
 	url := fmt.Sprintf("https://%s%s", endpoint, path)
-	req, err := http.NewRequest(method, url, bytes.NewReader(reqBody))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(reqBody))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 
 	// Sign the request using AWS Signature Version 4
 	signer := v4.NewSigner(sess.Config.Credentials)
 	_, err = signer.Sign(req, bytes.NewReader(reqBody), "es", *sess.Config.Region, time.Now())
 	if err != nil {
-		return fmt.Errorf("failed to sign request: %w", err)
+		return nil, fmt.Errorf("failed to sign request: %w", err)
 	}
 
 	// Make the request
 	client := &http.Client{
-		Timeout: 30 * time.Second,
+		Timeout: 60 * time.Second,
 	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to make request: %w", err)
+		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read response
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Check for success status codes
@@ -630,14 +1009,14 @@ func makeOpenSearchRequest(sess *session.Session, method, endpoint, path string,
 		} else {
 			fmt.Printf("OpenSearch request failed with status %d: %s\n", resp.StatusCode, string(respBody))
 		}
-		return fmt.Errorf("OpenSearch request failed with status %d", resp.StatusCode)
+		return respBody, fmt.Errorf("OpenSearch request failed with status %d", resp.StatusCode)
 	}
 
 	fmt.Printf("OpenSearch %s %s: %d\n", method, path, resp.StatusCode)
-	return nil
+	return respBody, nil
 }
 
-func saveToDynamoDB(sess *session.Session, document *Document) error {
+func saveToDynamoDB(ctx context.Context, sess *session.Session, document *Document) error {
 	// Get table name from environment variable
 	tableName := os.Getenv("DYNAMODB_TABLE_NAME")
 	if tableName == "" {
@@ -658,7 +1037,7 @@ func saveToDynamoDB(sess *session.Session, document *Document) error {
 	item["updated_at"] = &dynamodb.AttributeValue{S: aws.String(document.ProcessedAt.Format(time.RFC3339))}
 
 	// Put item to DynamoDB
-	_, err = dynamoClient.PutItem(&dynamodb.PutItemInput{
+	_, err = dynamoClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(tableName),
 		Item:      item,
 	})
@@ -671,6 +1050,68 @@ func saveToDynamoDB(sess *session.Session, document *Document) error {
 	return nil
 }
 
+// checkpointTableName returns the table checkpoints are stored in: CHECKPOINT_TABLE_NAME
+// if set, otherwise the same table (and fallback) saveToDynamoDB uses for document
+// metadata, so a deployment doesn't need a second DynamoDB table provisioned just
+// for checkpoints.
+func checkpointTableName() string {
+	if table := os.Getenv("CHECKPOINT_TABLE_NAME"); table != "" {
+		return table
+	}
+	if table := os.Getenv("DYNAMODB_TABLE_NAME"); table != "" {
+		return table
+	}
+	return "aws-serverless-rag-prod-documents-prod" // fallback, matches saveToDynamoDB
+}
+
+// loadCheckpoint fetches documentID's checkpoint, defaulting to LastProcessedChunkIndex
+// -1 (nothing processed yet) when no checkpoint item exists.
+func loadCheckpoint(ctx context.Context, sess *session.Session, documentID string) (ProcessingCheckpoint, error) {
+	dynamoClient := dynamodb.New(sess)
+
+	result, err := dynamoClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(checkpointTableName()),
+		Key: map[string]*dynamodb.AttributeValue{
+			"document_id": {S: aws.String(documentID)},
+		},
+	})
+	if err != nil {
+		return ProcessingCheckpoint{}, fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+	if result.Item == nil {
+		return ProcessingCheckpoint{DocumentID: documentID, LastProcessedChunkIndex: -1}, nil
+	}
+
+	var checkpoint ProcessingCheckpoint
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &checkpoint); err != nil {
+		return ProcessingCheckpoint{}, fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+	}
+	return checkpoint, nil
+}
+
+// saveCheckpoint records that every chunk up to and including lastProcessedChunkIndex
+// has been embedded and indexed for documentID.
+func saveCheckpoint(ctx context.Context, sess *session.Session, documentID string, lastProcessedChunkIndex int) error {
+	item, err := dynamodbattribute.MarshalMap(ProcessingCheckpoint{
+		DocumentID:              documentID,
+		LastProcessedChunkIndex: lastProcessedChunkIndex,
+		UpdatedAt:               time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	dynamoClient := dynamodb.New(sess)
+	_, err = dynamoClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(checkpointTableName()),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put checkpoint: %w", err)
+	}
+	return nil
+}
+
 func main() {
 	lambda.Start(handler)
 }
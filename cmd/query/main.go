@@ -1,32 +1,60 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/aws/signer/v4"
 	"github.com/aws/aws-sdk-go/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"aws-serverless-rag/internal/analytics"
+	"aws-serverless-rag/internal/backend"
+	"aws-serverless-rag/internal/opensearch"
+	"aws-serverless-rag/internal/performance"
+	"aws-serverless-rag/internal/querycache"
+	"aws-serverless-rag/internal/services"
+	"aws-serverless-rag/internal/utils"
 )
 
 type QueryRequest struct {
 	Question   string `json:"question"`
 	MaxResults int    `json:"max_results,omitempty"`
+	// SearchMode selects the retrieval strategy: "vector" (k-NN only), "keyword"
+	// (BM25 only), or "hybrid" (both, fused with Reciprocal Rank Fusion). Defaults
+	// to "hybrid".
+	SearchMode string `json:"search_mode,omitempty"`
+	// Rerank opts into a Cohere Rerank pass over the retrieved candidates. When
+	// true, searchSimilarDocuments over-fetches and rerankSources narrows back down
+	// to MaxResults.
+	Rerank bool `json:"rerank,omitempty"`
+	// BypassCache skips both cache tiers, for debugging a suspect cached response.
+	BypassCache bool `json:"bypass_cache,omitempty"`
 }
 
 type QueryResponse struct {
-	Answer    string   `json:"answer"`
-	Sources   []Source `json:"sources"`
-	QueryTime string   `json:"query_time"`
+	Answer    string     `json:"answer"`
+	Sources   []Source   `json:"sources"`
+	Citations []Citation `json:"citations,omitempty"`
+	QueryTime string     `json:"query_time"`
+}
+
+// Citation links a span of the answer text to the Source that supports it, so a UI
+// can render footnotes and highlight the supporting chunk on click.
+type Citation struct {
+	AnswerSpanStart int    `json:"answer_span_start"`
+	AnswerSpanEnd   int    `json:"answer_span_end"`
+	SourceIndex     int    `json:"source_index"`
+	ChunkID         string `json:"chunk_id"`
 }
 
 type Source struct {
@@ -34,14 +62,48 @@ type Source struct {
 	ChunkID    string  `json:"chunk_id"`
 	Content    string  `json:"content"`
 	Score      float64 `json:"score"`
+	// VectorScore, BM25Score and RRFScore surface the per-source component scores
+	// so callers can debug relevance; they're populated according to SearchMode.
+	VectorScore float64 `json:"vector_score,omitempty"`
+	BM25Score   float64 `json:"bm25_score,omitempty"`
+	RRFScore    float64 `json:"rrf_score,omitempty"`
+	// RerankScore is the Cohere Rerank relevance score, set only when
+	// QueryRequest.Rerank is true and the rerank call succeeds.
+	RerankScore float64 `json:"rerank_score,omitempty"`
+}
+
+const (
+	searchModeVector  = "vector"
+	searchModeKeyword = "keyword"
+	searchModeHybrid  = "hybrid"
+
+	// defaultRerankModelID is used when RERANK_MODEL_ID is unset, for deployments
+	// in regions where Bedrock Cohere Rerank is available.
+	defaultRerankModelID = "cohere.rerank-v3-5:0"
+
+	// rerankOverfetchFactor controls how many extra candidates searchSimilarDocuments
+	// retrieves when reranking is requested, so the rerank model has a wider pool to
+	// choose the top MaxResults from.
+	rerankOverfetchFactor = 3
+
+	// rerankCharBudget truncates each candidate's content before it's submitted to
+	// the rerank model, to stay within the model's input token budget.
+	rerankCharBudget = 2000
+)
+
+type CohereRerankRequest struct {
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n"`
 }
 
-type BedrockEmbeddingRequest struct {
-	InputText string `json:"inputText"`
+type CohereRerankResponse struct {
+	Results []CohereRerankResult `json:"results"`
 }
 
-type BedrockEmbeddingResponse struct {
-	Embedding []float64 `json:"embedding"`
+type CohereRerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
 }
 
 type BedrockLLMRequest struct {
@@ -64,30 +126,11 @@ type TitanResult struct {
 	OutputText string `json:"outputText"`
 }
 
-type OpenSearchKNNQuery struct {
-	Size  int                    `json:"size"`
-	Query map[string]interface{} `json:"query"`
-}
-
-type OpenSearchHit struct {
-	Index  string                 `json:"_index"`
-	ID     string                 `json:"_id"`
-	Score  float64                `json:"_score"`
-	Source map[string]interface{} `json:"_source"`
-}
-
-type OpenSearchResponse struct {
-	Hits struct {
-		Total struct {
-			Value int `json:"value"`
-		} `json:"total"`
-		Hits []OpenSearchHit `json:"hits"`
-	} `json:"hits"`
-}
-
 func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	fmt.Println("Query handler called")
 
+	start := time.Now()
+
 	headers := map[string]string{
 		"Content-Type":                 "application/json",
 		"Access-Control-Allow-Origin":  "*",
@@ -135,6 +178,10 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		queryRequest.MaxResults = 5
 	}
 
+	if queryRequest.SearchMode == "" {
+		queryRequest.SearchMode = searchModeHybrid
+	}
+
 	fmt.Printf("Processing query: %s (max_results: %d)\n", queryRequest.Question, queryRequest.MaxResults)
 
 	// Create AWS session
@@ -149,22 +196,78 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		}, nil
 	}
 
-	// Generate embedding for the question
+	var cache *querycache.Cache
+	if tableName := os.Getenv("QUERY_CACHE_TABLE"); tableName != "" {
+		cache = querycache.NewCache(dynamodb.New(sess), tableName)
+	}
+
+	var recorder *analytics.Recorder
+	if tableName := os.Getenv("ANALYTICS_TABLE_NAME"); tableName != "" {
+		recorder = analytics.NewRecorder(dynamodb.New(sess), tableName)
+	}
+	userID := request.RequestContext.Identity.CognitoIdentityID
+
+	answerKey := querycache.AnswerKey(queryRequest.Question, queryRequest.MaxResults, queryRequest.SearchMode)
+	if cache != nil && !queryRequest.BypassCache {
+		var cached QueryResponse
+		hit, err := cache.GetAnswer(answerKey, &cached)
+		if err != nil {
+			fmt.Printf("WARNING: answer cache lookup failed: %v\n", err)
+		} else if hit {
+			responseBody, err := json.Marshal(cached)
+			if err == nil {
+				headers["X-Cache"] = "HIT"
+				recordQuery(ctx, recorder, userID, queryRequest.Question, cached.Answer, len(cached.Sources), start)
+				return events.APIGatewayProxyResponse{StatusCode: 200, Headers: headers, Body: string(responseBody)}, nil
+			}
+		}
+	}
+
+	// Generate embedding for the question, using the embedding cache when available.
 	bedrockClient := bedrockruntime.New(sess)
-	questionEmbedding, err := generateQuestionEmbedding(bedrockClient, queryRequest.Question)
-	if err != nil {
-		fmt.Printf("Failed to generate embedding: %v\n", err)
-		return events.APIGatewayProxyResponse{
-			StatusCode: 500,
-			Headers:    headers,
-			Body:       fmt.Sprintf(`{"error": "Failed to generate question embedding: %s"}`, err.Error()),
-		}, nil
+	cacheStatus := "MISS-ANSWER"
+	questionHash := querycache.QuestionHash(queryRequest.Question)
+
+	var questionEmbedding []float64
+	embeddingCacheHit := false
+	if cache != nil && !queryRequest.BypassCache {
+		cachedEmbedding, hit, err := cache.GetEmbedding(questionHash)
+		if err != nil {
+			fmt.Printf("WARNING: embedding cache lookup failed: %v\n", err)
+		} else if hit {
+			questionEmbedding = cachedEmbedding
+			embeddingCacheHit = true
+		}
+	}
+
+	if !embeddingCacheHit {
+		cacheStatus = "MISS-EMBEDDING"
+		questionEmbedding, err = generateQuestionEmbedding(ctx, queryRequest.Question)
+		if err != nil {
+			fmt.Printf("Failed to generate embedding: %v\n", err)
+			return events.APIGatewayProxyResponse{
+				StatusCode: 500,
+				Headers:    headers,
+				Body:       fmt.Sprintf(`{"error": "Failed to generate question embedding: %s"}`, err.Error()),
+			}, nil
+		}
+		if cache != nil && !queryRequest.BypassCache {
+			if err := cache.PutEmbedding(questionHash, questionEmbedding); err != nil {
+				fmt.Printf("WARNING: failed to cache embedding: %v\n", err)
+			}
+		}
 	}
 
 	fmt.Printf("Generated question embedding (dimension: %d)\n", len(questionEmbedding))
 
-	// Search for similar documents in OpenSearch
-	sources, err := searchSimilarDocuments(sess, questionEmbedding, queryRequest.MaxResults)
+	// Search for similar documents in OpenSearch. When reranking is requested,
+	// over-fetch candidates so the rerank model has a wider pool to choose from.
+	fetchCount := queryRequest.MaxResults
+	if queryRequest.Rerank {
+		fetchCount = queryRequest.MaxResults * rerankOverfetchFactor
+	}
+
+	sources, err := searchSimilarDocuments(ctx, sess, queryRequest.Question, questionEmbedding, fetchCount, queryRequest.SearchMode)
 	if err != nil {
 		fmt.Printf("Vector search failed: %v\n", err)
 		return events.APIGatewayProxyResponse{
@@ -176,8 +279,12 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 
 	fmt.Printf("Found %d relevant sources\n", len(sources))
 
+	if queryRequest.Rerank {
+		sources = rerankSources(bedrockClient, queryRequest.Question, sources, queryRequest.MaxResults)
+	}
+
 	// Generate answer using LLM
-	answer, err := generateAnswerWithLLM(bedrockClient, queryRequest.Question, sources)
+	answer, citations, usedSources, err := generateAnswerWithLLM(bedrockClient, queryRequest.Question, sources)
 	if err != nil {
 		fmt.Printf("Failed to generate answer: %v\n", err)
 		return events.APIGatewayProxyResponse{
@@ -190,7 +297,8 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	// Create response
 	response := QueryResponse{
 		Answer:    answer,
-		Sources:   sources,
+		Sources:   usedSources,
+		Citations: citations,
 		QueryTime: time.Now().Format(time.RFC3339),
 	}
 
@@ -203,6 +311,14 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		}, nil
 	}
 
+	if cache != nil && !queryRequest.BypassCache {
+		if err := cache.PutAnswer(answerKey, response); err != nil {
+			fmt.Printf("WARNING: failed to cache answer: %v\n", err)
+		}
+	}
+
+	headers["X-Cache"] = cacheStatus
+	recordQuery(ctx, recorder, userID, queryRequest.Question, answer, len(usedSources), start)
 	return events.APIGatewayProxyResponse{
 		StatusCode: 200,
 		Headers:    headers,
@@ -210,155 +326,305 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	}, nil
 }
 
-func generateQuestionEmbedding(bedrockClient *bedrockruntime.BedrockRuntime, question string) ([]float64, error) {
-	// Use Titan Text Embeddings V2
-	modelID := "amazon.titan-embed-text-v2:0"
-
-	requestBody := BedrockEmbeddingRequest{
-		InputText: question,
+// recordQuery logs an answered query to recorder, if configured. A failure to
+// record is a warning, not a request failure: analytics shouldn't be able to break
+// the query endpoint.
+func recordQuery(ctx context.Context, recorder *analytics.Recorder, userID, question, answer string, sourceCount int, start time.Time) {
+	if recorder == nil {
+		return
 	}
+	if err := recorder.Record(ctx, userID, question, answer, sourceCount, time.Since(start)); err != nil {
+		fmt.Printf("WARNING: failed to record query analytics: %v\n", err)
+	}
+}
 
-	requestJSON, err := json.Marshal(requestBody)
+// queryEmbeddingRate/queryEmbeddingBurst seed embeddingLimiter. Like
+// performance.GenerateEmbeddingsConcurrently's defaultEmbeddingRate/Burst, these
+// are deliberately generous starting points the AIMD loop tunes down on the
+// first throttle rather than a measured ceiling.
+const (
+	queryEmbeddingRate  = 10.0
+	queryEmbeddingBurst = 10.0
+)
+
+// embeddingLimiter paces generateQuestionEmbedding's calls to the selected
+// backend.EmbeddingProvider and self-tunes via AIMD (see performance.Limiter): a
+// burst of queries sharing a warm Lambda container can hit the same Bedrock
+// throttling a bulk ingestion run does.
+var embeddingLimiter = performance.NewLimiter(queryEmbeddingRate, queryEmbeddingBurst)
+
+// generateQuestionEmbedding embeds question via the backend.EmbeddingProvider
+// selected by the BACKEND environment variable (see internal/backend and this
+// file's main, which registers "bedrock" by default). Calls are paced through
+// embeddingLimiter and retried with performance.CallWithRetry's AIMD backoff:
+// a throttling error halves the limiter's rate before the next attempt, and a
+// success restores it.
+func generateQuestionEmbedding(ctx context.Context, question string) ([]float64, error) {
+	b, err := backend.FromEnv()
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal embedding request: %w", err)
+		return nil, fmt.Errorf("failed to select embedding backend: %w", err)
 	}
 
-	input := &bedrockruntime.InvokeModelInput{
-		ModelId:     aws.String(modelID),
-		ContentType: aws.String("application/json"),
-		Accept:      aws.String("application/json"),
-		Body:        requestJSON,
+	var embedding []float32
+	policy := performance.RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  200 * time.Millisecond,
+		CapDelay:   5 * time.Second,
+		Limiter:    embeddingLimiter,
+		Classify:   classifyQueryEmbeddingError,
 	}
 
-	result, err := bedrockClient.InvokeModel(input)
-	if err != nil {
-		// Try V1 if V2 fails
-		if modelID == "amazon.titan-embed-text-v2:0" {
-			fmt.Println("V2 embeddings not available, trying V1...")
-			modelID = "amazon.titan-embed-text-v1"
-			input.ModelId = aws.String(modelID)
-			result, err = bedrockClient.InvokeModel(input)
-		}
-
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate embedding: %w", err)
+	err = performance.CallWithRetry(ctx, policy, func() error {
+		embeddings, _, embedErr := b.Embedding.Embed(ctx, []string{question})
+		if embedErr != nil {
+			return embedErr
 		}
+		embedding = embeddings[0]
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate embedding: %w", err)
 	}
 
-	var response BedrockEmbeddingResponse
-	if err := json.Unmarshal(result.Body, &response); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal embedding response: %w", err)
+	result := make([]float64, len(embedding))
+	for i, v := range embedding {
+		result[i] = float64(v)
 	}
+	return result, nil
+}
 
-	return response.Embedding, nil
+// classifyQueryEmbeddingError gives CallWithRetry a RetryDecision for an
+// embedding backend error, using the same throttling heuristic
+// performance.GenerateEmbeddingsConcurrently applies during ingestion.
+func classifyQueryEmbeddingError(err error) performance.RetryDecision {
+	if strings.Contains(strings.ToLower(err.Error()), "throttl") {
+		return performance.Throttle
+	}
+	return performance.Retryable
 }
 
-func searchSimilarDocuments(sess *session.Session, embedding []float64, maxResults int) ([]Source, error) {
-	opensearchEndpoint := os.Getenv("OPENSEARCH_ENDPOINT")
-	if opensearchEndpoint == "" {
-		return nil, fmt.Errorf("OPENSEARCH_ENDPOINT environment variable not set")
-	}
-
-	// Create KNN query
-	query := OpenSearchKNNQuery{
-		Size: maxResults,
-		Query: map[string]interface{}{
-			"knn": map[string]interface{}{
-				"vector": map[string]interface{}{
-					"vector": embedding,
-					"k":      maxResults,
-				},
-			},
-		},
+func searchSimilarDocuments(ctx context.Context, sess *session.Session, question string, embedding []float64, maxResults int, searchMode string) ([]Source, error) {
+	cfg, err := opensearch.NewConfigFromEnv()
+	if err != nil {
+		return nil, err
 	}
 
-	queryJSON, err := json.Marshal(query)
+	client, err := opensearch.NewClient(sess, cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal query: %w", err)
+		return nil, fmt.Errorf("failed to create OpenSearch client: %w", err)
 	}
 
-	// Create HTTP request
-	url := fmt.Sprintf("https://%s/rag-documents-prod/_search", opensearchEndpoint)
-	req, err := http.NewRequest("POST", url, bytes.NewReader(queryJSON))
+	var hits []opensearch.SearchHit
+	switch searchMode {
+	case searchModeKeyword:
+		hits, err = opensearch.KeywordSearch(ctx, client, cfg.IndexName, question, maxResults)
+	case searchModeVector:
+		hits, err = opensearch.VectorSearch(ctx, client, cfg.IndexName, embedding, maxResults)
+	default:
+		hits, err = opensearch.HybridSearch(ctx, client, cfg.IndexName, embedding, question, maxResults)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	sources := make([]Source, 0, len(hits))
+	for _, hit := range hits {
+		sources = append(sources, Source{
+			DocumentID:  hit.DocumentID,
+			ChunkID:     hit.ChunkID,
+			Content:     hit.Content,
+			Score:       hit.Score,
+			VectorScore: hit.VectorScore,
+			BM25Score:   hit.BM25Score,
+			RRFScore:    hit.RRFScore,
+		})
+	}
 
-	// Sign the request
-	signer := v4.NewSigner(sess.Config.Credentials)
-	_, err = signer.Sign(req, bytes.NewReader(queryJSON), "es", *sess.Config.Region, time.Now())
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign request: %w", err)
+	return sources, nil
+}
+
+// rerankSources reorders sources by Bedrock Cohere Rerank relevance and narrows the
+// result down to topN. If the rerank call fails at any step, it logs a warning and
+// falls back to returning sources unchanged (truncated to topN).
+func rerankSources(bedrockClient *bedrockruntime.BedrockRuntime, question string, sources []Source, topN int) []Source {
+	if len(sources) == 0 {
+		return sources
 	}
 
-	// Execute request
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	modelID := os.Getenv("RERANK_MODEL_ID")
+	if modelID == "" {
+		modelID = defaultRerankModelID
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute search: %w", err)
+	documents := make([]string, len(sources))
+	for i, source := range sources {
+		documents[i] = truncateForRerank(source.Content)
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
+	requestJSON, err := json.Marshal(CohereRerankRequest{
+		Query:     question,
+		Documents: documents,
+		TopN:      topN,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		fmt.Printf("WARNING: failed to marshal rerank request, falling back to original order: %v\n", err)
+		return truncateSources(sources, topN)
+	}
+
+	input := &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(modelID),
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+		Body:        requestJSON,
 	}
 
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("search failed with status %d: %s", resp.StatusCode, string(respBody))
+	result, err := bedrockClient.InvokeModel(input)
+	if err != nil {
+		fmt.Printf("WARNING: rerank call failed, falling back to original order: %v\n", err)
+		return truncateSources(sources, topN)
 	}
 
-	// Parse response
-	var searchResult OpenSearchResponse
-	if err := json.Unmarshal(respBody, &searchResult); err != nil {
-		return nil, fmt.Errorf("failed to parse search response: %w", err)
+	var response CohereRerankResponse
+	if err := json.Unmarshal(result.Body, &response); err != nil {
+		fmt.Printf("WARNING: failed to unmarshal rerank response, falling back to original order: %v\n", err)
+		return truncateSources(sources, topN)
 	}
 
-	// Convert to sources
-	sources := make([]Source, 0, len(searchResult.Hits.Hits))
-	for _, hit := range searchResult.Hits.Hits {
-		source := Source{
-			ChunkID: hit.ID,
-			Score:   hit.Score,
+	reranked := make([]Source, 0, len(response.Results))
+	for _, result := range response.Results {
+		if result.Index < 0 || result.Index >= len(sources) {
+			continue
 		}
+		source := sources[result.Index]
+		source.RerankScore = result.RelevanceScore
+		reranked = append(reranked, source)
+	}
 
-		if docID, ok := hit.Source["document_id"].(string); ok {
-			source.DocumentID = docID
-		}
-		if content, ok := hit.Source["content"].(string); ok {
-			source.Content = content
-		}
+	if len(reranked) == 0 {
+		fmt.Println("WARNING: rerank returned no usable results, falling back to original order")
+		return truncateSources(sources, topN)
+	}
+
+	return reranked
+}
 
-		sources = append(sources, source)
+func truncateSources(sources []Source, topN int) []Source {
+	if topN > 0 && len(sources) > topN {
+		return sources[:topN]
 	}
+	return sources
+}
 
-	return sources, nil
+func truncateForRerank(content string) string {
+	if len(content) <= rerankCharBudget {
+		return content
+	}
+	return content[:rerankCharBudget]
+}
+
+// citationPattern matches inline citation markers like "[1]" or "[12]" that the
+// prompt instructs the model to emit after each supported sentence.
+var citationPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// generateAnswerWithLLM asks Titan Text to answer the question, citing the supplied
+// sources with inline "[N]" markers. It extracts those markers into citations,
+// re-prompting once with a corrective instruction if the model cites a context
+// number outside the supplied sources, then drops any sources no valid citation
+// referenced. usedSources preserves the original ordering of sources.
+func generateAnswerWithLLM(bedrockClient *bedrockruntime.BedrockRuntime, question string, sources []Source) (answer string, citations []Citation, usedSources []Source, err error) {
+	prompt := buildAnswerPrompt(question, sources, "")
+
+	answer, err = invokeLLMForAnswer(bedrockClient, prompt)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	citations, invalid := extractCitations(answer, sources)
+	if len(invalid) > 0 {
+		correctivePrompt := buildAnswerPrompt(question, sources, correctiveInstruction(invalid))
+		answer, err = invokeLLMForAnswer(bedrockClient, correctivePrompt)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		citations, _ = extractCitations(answer, sources)
+	}
+
+	usedSources = filterUsedSources(sources, citations)
+	return answer, citations, usedSources, nil
 }
 
-func generateAnswerWithLLM(bedrockClient *bedrockruntime.BedrockRuntime, question string, sources []Source) (string, error) {
-	// Build context from sources
+// buildAnswerPrompt renders the numbered-context prompt, requiring inline "[N]"
+// citations. corrective, when non-empty, is appended as a follow-up instruction for
+// the re-prompt after a hallucinated citation number.
+func buildAnswerPrompt(question string, sources []Source, corrective string) string {
 	context := ""
 	for i, source := range sources {
 		context += fmt.Sprintf("Context %d:\n%s\n\n", i+1, source.Content)
 	}
 
-	// Create prompt
-	prompt := fmt.Sprintf(`Based on the following context, please answer the question. If the context doesn't contain enough information to answer the question, say so.
+	prompt := fmt.Sprintf(`Based on the following numbered contexts, answer the question. After every sentence that relies on a context, cite it with its bracketed number, e.g. [1] or [1][2]. Only cite context numbers listed below. If the context doesn't contain enough information to answer the question, say so.
 
 Context:
 %s
 
 Question: %s
 
-Answer: 
+Answer:
 
 `, context, question)
 
+	if corrective != "" {
+		prompt += corrective + "\n\n"
+	}
+
+	return prompt
+}
+
+func correctiveInstruction(invalidCitations []int) string {
+	return fmt.Sprintf("Your previous answer cited context number(s) %v, which were not among the contexts provided. Rewrite the answer, citing only the context numbers listed above.", invalidCitations)
+}
+
+// extractCitations scans answer for "[N]" markers and resolves each to the
+// corresponding Source. Markers whose N falls outside 1..len(sources) are omitted
+// from citations and their numbers are returned in invalid.
+func extractCitations(answer string, sources []Source) (citations []Citation, invalid []int) {
+	for _, match := range citationPattern.FindAllStringSubmatchIndex(answer, -1) {
+		num, err := strconv.Atoi(answer[match[2]:match[3]])
+		if err != nil {
+			continue
+		}
+		if num < 1 || num > len(sources) {
+			invalid = append(invalid, num)
+			continue
+		}
+		citations = append(citations, Citation{
+			AnswerSpanStart: match[0],
+			AnswerSpanEnd:   match[1],
+			SourceIndex:     num,
+			ChunkID:         sources[num-1].ChunkID,
+		})
+	}
+	return citations, invalid
+}
+
+// filterUsedSources drops sources that no citation referenced, preserving the
+// original order of the ones that remain.
+func filterUsedSources(sources []Source, citations []Citation) []Source {
+	cited := make(map[int]bool, len(citations))
+	for _, citation := range citations {
+		cited[citation.SourceIndex] = true
+	}
+
+	used := make([]Source, 0, len(cited))
+	for i, source := range sources {
+		if cited[i+1] {
+			used = append(used, source)
+		}
+	}
+	return used
+}
+
+func invokeLLMForAnswer(bedrockClient *bedrockruntime.BedrockRuntime, prompt string) (string, error) {
 	// Use Titan Text G1 Express for answer generation
 	modelID := "amazon.titan-text-express-v1"
 
@@ -402,5 +668,29 @@ Answer:
 }
 
 func main() {
-	lambda.Start(handler)
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String("ap-northeast-1"),
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to create AWS session: %v", err))
+	}
+
+	// Register the default embedding backend generateQuestionEmbedding selects via
+	// backend.FromEnv(), so this handler embeds through the same pluggable
+	// backend.EmbeddingProvider abstraction cmd/loadtest uses instead of calling
+	// Bedrock directly. BACKEND can still override this to "mock", "openai",
+	// "titan-v1", "titan-v2", or "cohere" (see cmd/loadtest's newEmbedder), as long
+	// as whatever's registered under that name got wired up elsewhere; only
+	// "bedrock" is registered here.
+	bedrockClient := services.NewBedrockClient(&utils.AWSConfig{BedrockClient: bedrockruntime.New(sess)}, utils.NewLogger())
+	backend.Register(backend.DefaultBackendName, backend.Backend{Embedding: backend.NewBedrockProvider(bedrockClient)})
+
+	var h utils.APIGatewayHandlerFunc = handler
+
+	if tableName := os.Getenv("IDEMPOTENCY_TABLE_NAME"); tableName != "" {
+		idempotency := utils.NewIdempotency(dynamodb.New(sess), tableName, 0)
+		h = idempotency.WithIdempotency(h)
+	}
+
+	lambda.Start(h)
 }
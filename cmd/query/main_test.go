@@ -0,0 +1,92 @@
+package main
+
+import "testing"
+
+func TestTruncateForRerank(t *testing.T) {
+	short := "a short chunk"
+	if got := truncateForRerank(short); got != short {
+		t.Fatalf("expected short content unchanged, got %q", got)
+	}
+
+	long := make([]byte, rerankCharBudget+500)
+	for i := range long {
+		long[i] = 'x'
+	}
+	got := truncateForRerank(string(long))
+	if len(got) != rerankCharBudget {
+		t.Fatalf("expected truncation to %d chars, got %d", rerankCharBudget, len(got))
+	}
+}
+
+func TestTruncateSources(t *testing.T) {
+	sources := []Source{{ChunkID: "a"}, {ChunkID: "b"}, {ChunkID: "c"}}
+
+	got := truncateSources(sources, 2)
+	if len(got) != 2 || got[0].ChunkID != "a" || got[1].ChunkID != "b" {
+		t.Fatalf("expected first 2 sources, got %+v", got)
+	}
+
+	if got := truncateSources(sources, 0); len(got) != len(sources) {
+		t.Fatalf("expected topN<=0 to leave sources unchanged, got %d", len(got))
+	}
+}
+
+func TestRerankSources_EmptyInput(t *testing.T) {
+	got := rerankSources(nil, "question", nil, 5)
+	if len(got) != 0 {
+		t.Fatalf("expected empty input to return empty, got %+v", got)
+	}
+}
+
+func TestExtractCitations(t *testing.T) {
+	sources := []Source{
+		{ChunkID: "chunk-1"},
+		{ChunkID: "chunk-2"},
+	}
+
+	answer := "AI is artificial intelligence [1]. It powers many products [2][1]."
+	citations, invalid := extractCitations(answer, sources)
+
+	if len(invalid) != 0 {
+		t.Fatalf("expected no invalid citations, got %v", invalid)
+	}
+	if len(citations) != 3 {
+		t.Fatalf("expected 3 citations, got %d", len(citations))
+	}
+	if citations[0].SourceIndex != 1 || citations[0].ChunkID != "chunk-1" {
+		t.Fatalf("expected first citation to resolve to chunk-1, got %+v", citations[0])
+	}
+	if answer[citations[0].AnswerSpanStart:citations[0].AnswerSpanEnd] != "[1]" {
+		t.Fatalf("expected span to cover the marker text, got %q", answer[citations[0].AnswerSpanStart:citations[0].AnswerSpanEnd])
+	}
+}
+
+func TestExtractCitations_InvalidNumbers(t *testing.T) {
+	sources := []Source{{ChunkID: "chunk-1"}}
+
+	citations, invalid := extractCitations("This cites a nonexistent context [5].", sources)
+
+	if len(citations) != 0 {
+		t.Fatalf("expected no valid citations, got %+v", citations)
+	}
+	if len(invalid) != 1 || invalid[0] != 5 {
+		t.Fatalf("expected invalid=[5], got %v", invalid)
+	}
+}
+
+func TestFilterUsedSources(t *testing.T) {
+	sources := []Source{
+		{ChunkID: "chunk-1"},
+		{ChunkID: "chunk-2"},
+		{ChunkID: "chunk-3"},
+	}
+	citations := []Citation{
+		{SourceIndex: 2, ChunkID: "chunk-2"},
+	}
+
+	used := filterUsedSources(sources, citations)
+
+	if len(used) != 1 || used[0].ChunkID != "chunk-2" {
+		t.Fatalf("expected only chunk-2 to remain, got %+v", used)
+	}
+}
@@ -3,48 +3,16 @@ package test
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 
 	"aws-serverless-rag/internal/models"
+	"aws-serverless-rag/internal/testfixtures"
 )
 
-// mockBedrockClient implements the BedrockClient interface for testing
-type mockBedrockClient struct {
-	shouldFailEmbedding bool
-	shouldFailAnswer    bool
-	mockEmbedding       []float32
-	mockAnswer          string
-}
-
-func (m *mockBedrockClient) GenerateEmbedding(text string) ([]float32, error) {
-	if m.shouldFailEmbedding {
-		return nil, models.ErrEmbeddingFailed
-	}
-	return m.mockEmbedding, nil
-}
-
-func (m *mockBedrockClient) GenerateAnswer(question string, sources []models.Source) (string, error) {
-	if m.shouldFailAnswer {
-		return "", models.ErrLLMGenerationFailed
-	}
-	return m.mockAnswer, nil
-}
-
-// mockOpenSearchClient implements the OpenSearchClient interface for testing
-type mockOpenSearchClient struct {
-	shouldFail  bool
-	mockSources []models.Source
-}
-
-func (m *mockOpenSearchClient) VectorSearch(ctx context.Context, embedding []float32, maxResults int) ([]models.Source, error) {
-	if m.shouldFail {
-		return nil, models.ErrVectorSearchFailed
-	}
-	return m.mockSources, nil
-}
-
 func TestQueryHandler_ValidRequest(t *testing.T) {
 	// This is a unit test for the query logic
 	// We'll test the main processing flow without AWS dependencies
@@ -176,12 +144,17 @@ func TestQueryHandler_ValidRequest(t *testing.T) {
 				// Test the business logic flow
 				if !tt.expectError || tt.embeddingFails || tt.searchFails || tt.answerFails {
 					// Test embedding generation
-					mockBedrock := &mockBedrockClient{
-						shouldFailEmbedding: tt.embeddingFails,
-						shouldFailAnswer:    tt.answerFails,
-						mockEmbedding:       tt.mockEmbedding,
-						mockAnswer:          tt.mockAnswer,
+					bedrockOpts := []testfixtures.BedrockOption{
+						testfixtures.WithEmbedding(tt.mockEmbedding),
+						testfixtures.WithAnswer(tt.mockAnswer),
 					}
+					if tt.embeddingFails {
+						bedrockOpts = append(bedrockOpts, testfixtures.WithFailEmbedding())
+					}
+					if tt.answerFails {
+						bedrockOpts = append(bedrockOpts, testfixtures.WithFailAnswer())
+					}
+					mockBedrock := testfixtures.NewMockBedrock(bedrockOpts...)
 
 					if !tt.embeddingFails {
 						embedding, err := mockBedrock.GenerateEmbedding(queryRequest.Question)
@@ -197,10 +170,11 @@ func TestQueryHandler_ValidRequest(t *testing.T) {
 						}
 
 						// Test vector search
-						mockOpenSearch := &mockOpenSearchClient{
-							shouldFail:  tt.searchFails,
-							mockSources: tt.mockSources,
+						searchOpts := []testfixtures.OpenSearchOption{testfixtures.WithSources(tt.mockSources)}
+						if tt.searchFails {
+							searchOpts = append(searchOpts, testfixtures.WithFailSearch())
 						}
+						mockOpenSearch := testfixtures.NewMockOpenSearch(searchOpts...)
 
 						if !tt.searchFails {
 							sources, err := mockOpenSearch.VectorSearch(context.Background(), embedding, queryRequest.MaxResults)
@@ -235,6 +209,59 @@ func TestQueryHandler_ValidRequest(t *testing.T) {
 	}
 }
 
+// TestMockBedrockClient_GenerateAnswerStream_ReconstructsFullAnswer drains the
+// channel GenerateAnswerStream writes to and checks the concatenated tokens
+// match the full answer, plus that the sources passed in are still available
+// once the stream ends to build a final "done" frame.
+func TestMockBedrockClient_GenerateAnswerStream_ReconstructsFullAnswer(t *testing.T) {
+	mock := testfixtures.NewMockBedrock(testfixtures.WithStream(
+		[]string{"AI ", "stands ", "for ", "Artificial Intelligence."}, time.Millisecond,
+	))
+	sources := []models.Source{
+		{DocumentID: "doc1", ChunkID: "chunk1", Content: "AI is artificial intelligence", Score: 0.95},
+	}
+
+	out := make(chan string)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- mock.GenerateAnswerStream(context.Background(), "What is AI?", sources, out)
+	}()
+
+	var full strings.Builder
+	for token := range out {
+		full.WriteString(token)
+	}
+
+	if err := <-errCh; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const want = "AI stands for Artificial Intelligence."
+	if full.String() != want {
+		t.Errorf("expected reconstructed answer %q, got %q", want, full.String())
+	}
+
+	// The final SSE frame carries the sources alongside the completed answer.
+	if len(sources) != 1 || sources[0].ChunkID != "chunk1" {
+		t.Errorf("expected sources to still be available for the final frame, got %+v", sources)
+	}
+}
+
+func TestMockBedrockClient_GenerateAnswerStream_PropagatesFailure(t *testing.T) {
+	mock := testfixtures.NewMockBedrock(testfixtures.WithFailStream())
+	out := make(chan string)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- mock.GenerateAnswerStream(context.Background(), "q", nil, out) }()
+
+	for range out {
+		t.Error("expected no tokens to be sent on failure")
+	}
+	if err := <-errCh; err == nil {
+		t.Error("expected an error to be returned")
+	}
+}
+
 func TestQueryRequest_Validation(t *testing.T) {
 	tests := []struct {
 		name        string
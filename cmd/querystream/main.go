@@ -0,0 +1,510 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/bedrockruntime"
+
+	"aws-serverless-rag/internal/opensearch"
+)
+
+// QueryRequest mirrors cmd/query's request shape; POST /query/stream takes the same
+// question/max_results payload as POST /query.
+type QueryRequest struct {
+	Question   string `json:"question"`
+	MaxResults int    `json:"max_results,omitempty"`
+	SearchMode string `json:"search_mode,omitempty"`
+	Rerank     bool   `json:"rerank,omitempty"`
+}
+
+type Source struct {
+	DocumentID  string  `json:"document_id"`
+	ChunkID     string  `json:"chunk_id"`
+	Content     string  `json:"content"`
+	Score       float64 `json:"score"`
+	VectorScore float64 `json:"vector_score,omitempty"`
+	BM25Score   float64 `json:"bm25_score,omitempty"`
+	RRFScore    float64 `json:"rrf_score,omitempty"`
+	RerankScore float64 `json:"rerank_score,omitempty"`
+}
+
+const (
+	searchModeVector  = "vector"
+	searchModeKeyword = "keyword"
+	searchModeHybrid  = "hybrid"
+
+	defaultRerankModelID  = "cohere.rerank-v3-5:0"
+	rerankOverfetchFactor = 3
+	rerankCharBudget      = 2000
+)
+
+type CohereRerankRequest struct {
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	TopN      int      `json:"top_n"`
+}
+
+type CohereRerankResponse struct {
+	Results []CohereRerankResult `json:"results"`
+}
+
+type CohereRerankResult struct {
+	Index          int     `json:"index"`
+	RelevanceScore float64 `json:"relevance_score"`
+}
+
+// sseEvent is one Server-Sent Event written to the POST /query/stream response
+// body: a "sources" event fired once with the full ranked source list, "delta"
+// events as answer tokens arrive from Bedrock, and a final "done" event carrying
+// token usage and the stop reason.
+//
+// aws-lambda-go v1.46.0 has no API Gateway response-streaming support (lambda.Start
+// always waits for the handler to return before flushing bytes), so this handler
+// can't push these events to the client incrementally. It assembles the full
+// sequence as events are produced internally and returns them as one
+// text/event-stream body, so a client already speaking SSE works unchanged once
+// real response streaming becomes available.
+type sseEvent struct {
+	name string
+	data interface{}
+}
+
+func (e sseEvent) encode() (string, error) {
+	dataJSON, err := json.Marshal(e.data)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("event: %s\ndata: %s\n\n", e.name, string(dataJSON)), nil
+}
+
+type deltaEventData struct {
+	Text string `json:"text"`
+}
+
+type doneEventData struct {
+	Usage      usageData `json:"usage"`
+	StopReason string    `json:"stop_reason"`
+}
+
+type usageData struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// streamUsage accumulates the token counts and stop reason Titan reports on the
+// final chunk of a streamed response.
+type streamUsage struct {
+	promptTokens     int
+	completionTokens int
+	stopReason       string
+}
+
+func (u streamUsage) asUsageData() usageData {
+	return usageData{PromptTokens: u.promptTokens, CompletionTokens: u.completionTokens}
+}
+
+type BedrockEmbeddingRequest struct {
+	InputText string `json:"inputText"`
+}
+
+type BedrockEmbeddingResponse struct {
+	Embedding []float64 `json:"embedding"`
+}
+
+type BedrockLLMRequest struct {
+	InputText            string                    `json:"inputText"`
+	TextGenerationConfig TitanTextGenerationConfig `json:"textGenerationConfig"`
+}
+
+type TitanTextGenerationConfig struct {
+	MaxTokenCount int      `json:"maxTokenCount"`
+	Temperature   float64  `json:"temperature"`
+	TopP          float64  `json:"topP"`
+	StopSequences []string `json:"stopSequences"`
+}
+
+// TitanStreamChunk is one decoded chunk event from InvokeModelWithResponseStream.
+// InputTextTokenCount and TotalOutputTextTokenCount are only populated on the final
+// chunk, once Titan knows the full token counts for the request.
+type TitanStreamChunk struct {
+	OutputText                string `json:"outputText"`
+	InputTextTokenCount       int    `json:"inputTextTokenCount"`
+	TotalOutputTextTokenCount int    `json:"totalOutputTextTokenCount"`
+	CompletionReason          string `json:"completionReason"`
+}
+
+func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	fmt.Println("Query stream handler called")
+
+	headers := map[string]string{
+		"Content-Type":                 "text/event-stream",
+		"Cache-Control":                "no-cache",
+		"Access-Control-Allow-Origin":  "*",
+		"Access-Control-Allow-Methods": "*",
+		"Access-Control-Allow-Headers": "*",
+	}
+
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 200,
+			Headers:    headers,
+			Body:       `{"message": "OK"}`,
+		}, nil
+	}
+
+	if request.HTTPMethod != "POST" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 405,
+			Headers:    headers,
+			Body:       `{"error": "Method not allowed"}`,
+		}, nil
+	}
+
+	var queryRequest QueryRequest
+	if err := json.Unmarshal([]byte(request.Body), &queryRequest); err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 400,
+			Headers:    headers,
+			Body:       fmt.Sprintf(`{"error": "Invalid request body: %s"}`, err.Error()),
+		}, nil
+	}
+
+	if queryRequest.Question == "" {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 400,
+			Headers:    headers,
+			Body:       `{"error": "question is required"}`,
+		}, nil
+	}
+
+	if queryRequest.MaxResults == 0 {
+		queryRequest.MaxResults = 5
+	}
+
+	if queryRequest.SearchMode == "" {
+		queryRequest.SearchMode = searchModeHybrid
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String("ap-northeast-1"),
+	})
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Headers:    headers,
+			Body:       fmt.Sprintf(`{"error": "Failed to create AWS session: %s"}`, err.Error()),
+		}, nil
+	}
+
+	bedrockClient := bedrockruntime.New(sess)
+	questionEmbedding, err := generateQuestionEmbedding(bedrockClient, queryRequest.Question)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Headers:    headers,
+			Body:       fmt.Sprintf(`{"error": "Failed to generate question embedding: %s"}`, err.Error()),
+		}, nil
+	}
+
+	fetchCount := queryRequest.MaxResults
+	if queryRequest.Rerank {
+		fetchCount = queryRequest.MaxResults * rerankOverfetchFactor
+	}
+
+	sources, err := searchSimilarDocuments(ctx, sess, queryRequest.Question, questionEmbedding, fetchCount, queryRequest.SearchMode)
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Headers:    headers,
+			Body:       fmt.Sprintf(`{"error": "Vector search failed: %s"}`, err.Error()),
+		}, nil
+	}
+
+	if queryRequest.Rerank {
+		sources = rerankSources(bedrockClient, queryRequest.Question, sources, queryRequest.MaxResults)
+	}
+
+	sseEvents := []sseEvent{{name: "sources", data: sources}}
+
+	usage, err := streamAnswerFromLLM(ctx, bedrockClient, queryRequest.Question, sources, func(token string) {
+		sseEvents = append(sseEvents, sseEvent{name: "delta", data: deltaEventData{Text: token}})
+	})
+	if err != nil {
+		return events.APIGatewayProxyResponse{
+			StatusCode: 500,
+			Headers:    headers,
+			Body:       fmt.Sprintf(`{"error": "Failed to generate answer: %s"}`, err.Error()),
+		}, nil
+	}
+
+	sseEvents = append(sseEvents, sseEvent{name: "done", data: doneEventData{Usage: usage.asUsageData(), StopReason: usage.stopReason}})
+
+	var body strings.Builder
+	for _, event := range sseEvents {
+		encoded, err := event.encode()
+		if err != nil {
+			return events.APIGatewayProxyResponse{
+				StatusCode: 500,
+				Headers:    headers,
+				Body:       `{"error": "Failed to marshal stream event"}`,
+			}, nil
+		}
+		body.WriteString(encoded)
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: 200,
+		Headers:    headers,
+		Body:       body.String(),
+	}, nil
+}
+
+func generateQuestionEmbedding(bedrockClient *bedrockruntime.BedrockRuntime, question string) ([]float64, error) {
+	modelID := "amazon.titan-embed-text-v2:0"
+
+	requestBody := BedrockEmbeddingRequest{
+		InputText: question,
+	}
+
+	requestJSON, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embedding request: %w", err)
+	}
+
+	input := &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(modelID),
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+		Body:        requestJSON,
+	}
+
+	result, err := bedrockClient.InvokeModel(input)
+	if err != nil {
+		if modelID == "amazon.titan-embed-text-v2:0" {
+			modelID = "amazon.titan-embed-text-v1"
+			input.ModelId = aws.String(modelID)
+			result, err = bedrockClient.InvokeModel(input)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embedding: %w", err)
+		}
+	}
+
+	var response BedrockEmbeddingResponse
+	if err := json.Unmarshal(result.Body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal embedding response: %w", err)
+	}
+
+	return response.Embedding, nil
+}
+
+func searchSimilarDocuments(ctx context.Context, sess *session.Session, question string, embedding []float64, maxResults int, searchMode string) ([]Source, error) {
+	cfg, err := opensearch.NewConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := opensearch.NewClient(sess, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenSearch client: %w", err)
+	}
+
+	var hits []opensearch.SearchHit
+	switch searchMode {
+	case searchModeKeyword:
+		hits, err = opensearch.KeywordSearch(ctx, client, cfg.IndexName, question, maxResults)
+	case searchModeVector:
+		hits, err = opensearch.VectorSearch(ctx, client, cfg.IndexName, embedding, maxResults)
+	default:
+		hits, err = opensearch.HybridSearch(ctx, client, cfg.IndexName, embedding, question, maxResults)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]Source, 0, len(hits))
+	for _, hit := range hits {
+		sources = append(sources, Source{
+			DocumentID:  hit.DocumentID,
+			ChunkID:     hit.ChunkID,
+			Content:     hit.Content,
+			Score:       hit.Score,
+			VectorScore: hit.VectorScore,
+			BM25Score:   hit.BM25Score,
+			RRFScore:    hit.RRFScore,
+		})
+	}
+
+	return sources, nil
+}
+
+// rerankSources reorders sources by Bedrock Cohere Rerank relevance and narrows the
+// result down to topN, falling back to the original ordering (truncated to topN) and
+// a logged warning if the rerank call fails.
+func rerankSources(bedrockClient *bedrockruntime.BedrockRuntime, question string, sources []Source, topN int) []Source {
+	if len(sources) == 0 {
+		return sources
+	}
+
+	modelID := os.Getenv("RERANK_MODEL_ID")
+	if modelID == "" {
+		modelID = defaultRerankModelID
+	}
+
+	documents := make([]string, len(sources))
+	for i, source := range sources {
+		documents[i] = truncateForRerank(source.Content)
+	}
+
+	requestJSON, err := json.Marshal(CohereRerankRequest{
+		Query:     question,
+		Documents: documents,
+		TopN:      topN,
+	})
+	if err != nil {
+		fmt.Printf("WARNING: failed to marshal rerank request, falling back to original order: %v\n", err)
+		return truncateSources(sources, topN)
+	}
+
+	input := &bedrockruntime.InvokeModelInput{
+		ModelId:     aws.String(modelID),
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+		Body:        requestJSON,
+	}
+
+	result, err := bedrockClient.InvokeModel(input)
+	if err != nil {
+		fmt.Printf("WARNING: rerank call failed, falling back to original order: %v\n", err)
+		return truncateSources(sources, topN)
+	}
+
+	var response CohereRerankResponse
+	if err := json.Unmarshal(result.Body, &response); err != nil {
+		fmt.Printf("WARNING: failed to unmarshal rerank response, falling back to original order: %v\n", err)
+		return truncateSources(sources, topN)
+	}
+
+	reranked := make([]Source, 0, len(response.Results))
+	for _, result := range response.Results {
+		if result.Index < 0 || result.Index >= len(sources) {
+			continue
+		}
+		source := sources[result.Index]
+		source.RerankScore = result.RelevanceScore
+		reranked = append(reranked, source)
+	}
+
+	if len(reranked) == 0 {
+		fmt.Println("WARNING: rerank returned no usable results, falling back to original order")
+		return truncateSources(sources, topN)
+	}
+
+	return reranked
+}
+
+func truncateSources(sources []Source, topN int) []Source {
+	if topN > 0 && len(sources) > topN {
+		return sources[:topN]
+	}
+	return sources
+}
+
+func truncateForRerank(content string) string {
+	if len(content) <= rerankCharBudget {
+		return content
+	}
+	return content[:rerankCharBudget]
+}
+
+// streamAnswerFromLLM invokes Titan Text via InvokeModelWithResponseStream and calls
+// onToken for each outputText chunk as it arrives from Bedrock. It returns the token
+// usage and stop reason Titan reports on the stream's final chunk.
+func streamAnswerFromLLM(ctx context.Context, bedrockClient *bedrockruntime.BedrockRuntime, question string, sources []Source, onToken func(string)) (streamUsage, error) {
+	context := ""
+	for i, source := range sources {
+		context += fmt.Sprintf("Context %d:\n%s\n\n", i+1, source.Content)
+	}
+
+	prompt := fmt.Sprintf(`Based on the following context, please answer the question. If the context doesn't contain enough information to answer the question, say so.
+
+Context:
+%s
+
+Question: %s
+
+Answer:
+
+`, context, question)
+
+	modelID := "amazon.titan-text-express-v1"
+
+	requestBody := BedrockLLMRequest{
+		InputText: prompt,
+		TextGenerationConfig: TitanTextGenerationConfig{
+			MaxTokenCount: 1000,
+			Temperature:   0.1,
+			TopP:          0.9,
+			StopSequences: []string{"User:"},
+		},
+	}
+
+	requestJSON, err := json.Marshal(requestBody)
+	if err != nil {
+		return streamUsage{}, fmt.Errorf("failed to marshal LLM request: %w", err)
+	}
+
+	input := &bedrockruntime.InvokeModelWithResponseStreamInput{
+		ModelId:     aws.String(modelID),
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+		Body:        requestJSON,
+	}
+
+	output, err := bedrockClient.InvokeModelWithResponseStreamWithContext(ctx, input)
+	if err != nil {
+		return streamUsage{}, fmt.Errorf("failed to invoke streaming model: %w", err)
+	}
+
+	stream := output.GetStream()
+	defer stream.Close()
+
+	var usage streamUsage
+	for event := range stream.Events() {
+		part, ok := event.(*bedrockruntime.PayloadPart)
+		if !ok {
+			continue
+		}
+
+		var chunk TitanStreamChunk
+		if err := json.Unmarshal(part.Bytes, &chunk); err != nil {
+			return streamUsage{}, fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+		}
+		onToken(chunk.OutputText)
+
+		if chunk.CompletionReason != "" {
+			usage.promptTokens = chunk.InputTextTokenCount
+			usage.completionTokens = chunk.TotalOutputTextTokenCount
+			usage.stopReason = chunk.CompletionReason
+		}
+	}
+
+	if err := stream.Err(); err != nil {
+		return streamUsage{}, fmt.Errorf("response stream error: %w", err)
+	}
+
+	return usage, nil
+}
+
+func main() {
+	lambda.Start(handler)
+}
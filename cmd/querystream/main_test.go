@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestHandler_OptionsRequest(t *testing.T) {
+	resp, err := handler(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: "OPTIONS"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("got status %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestHandler_MethodNotAllowed(t *testing.T) {
+	resp, err := handler(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: "GET"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 405 {
+		t.Fatalf("got status %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestHandler_InvalidBody(t *testing.T) {
+	resp, err := handler(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod: "POST",
+		Body:       `{"question": "what is AI?"`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 400 {
+		t.Fatalf("got status %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestTruncateForRerank(t *testing.T) {
+	short := "a short chunk"
+	if got := truncateForRerank(short); got != short {
+		t.Fatalf("expected short content unchanged, got %q", got)
+	}
+
+	long := make([]byte, rerankCharBudget+500)
+	for i := range long {
+		long[i] = 'x'
+	}
+	if got := truncateForRerank(string(long)); len(got) != rerankCharBudget {
+		t.Fatalf("expected truncation to %d chars, got %d", rerankCharBudget, len(got))
+	}
+}
+
+func TestRerankSources_EmptyInput(t *testing.T) {
+	got := rerankSources(nil, "question", nil, 5)
+	if len(got) != 0 {
+		t.Fatalf("expected empty input to return empty, got %+v", got)
+	}
+}
+
+func TestHandler_MissingQuestion(t *testing.T) {
+	resp, err := handler(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod: "POST",
+		Body:       `{"question": ""}`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 400 {
+		t.Fatalf("got status %d, want 400", resp.StatusCode)
+	}
+}
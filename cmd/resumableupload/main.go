@@ -0,0 +1,198 @@
+// Command resumableupload serves the resumable chunked upload protocol:
+//
+//	POST /documents/uploads                   start a session
+//	PATCH /documents/uploads/{upload_id}       append one chunk
+//	PUT /documents/uploads/{upload_id}         finalize and kick off processing
+//	HEAD /documents/uploads/{upload_id}        resume point for an interrupted client
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage"
+	"aws-serverless-rag/internal/upload"
+)
+
+// ResumableUploadHandler serves the POST/PATCH/PUT/HEAD resumable-upload routes
+// against an injected Manager, so the request parsing can be unit tested without
+// real AWS credentials.
+type ResumableUploadHandler struct {
+	manager *upload.Manager
+	prefix  string
+}
+
+// NewResumableUploadHandler creates a handler backed by manager, storing objects
+// under prefix.
+func NewResumableUploadHandler(manager *upload.Manager, prefix string) *ResumableUploadHandler {
+	return &ResumableUploadHandler{manager: manager, prefix: prefix}
+}
+
+func (h *ResumableUploadHandler) Handle(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	headers := map[string]string{
+		"Content-Type":                 "application/json",
+		"Access-Control-Allow-Origin":  "*",
+		"Access-Control-Allow-Methods": "*",
+		"Access-Control-Allow-Headers": "*",
+	}
+
+	switch request.HTTPMethod {
+	case "POST":
+		return h.handleStart(ctx, request, headers)
+	case "PATCH":
+		return h.handleAppend(ctx, request, headers)
+	case "PUT":
+		return h.handleFinalize(ctx, request, headers)
+	case "HEAD":
+		return h.handleHead(ctx, request, headers)
+	default:
+		return errorResponse(headers, 405, "Method not allowed")
+	}
+}
+
+func (h *ResumableUploadHandler) handleStart(ctx context.Context, request events.APIGatewayProxyRequest, headers map[string]string) (events.APIGatewayProxyResponse, error) {
+	var body struct {
+		Filename    string `json:"filename"`
+		ContentType string `json:"content_type"`
+		TotalSize   int64  `json:"total_size"`
+	}
+	if err := json.Unmarshal([]byte(request.Body), &body); err != nil {
+		return errorResponse(headers, 400, "invalid request body")
+	}
+	if body.Filename == "" {
+		return errorResponse(headers, 400, "filename is required")
+	}
+
+	key := h.prefix + body.Filename
+	session, err := h.manager.StartUpload(ctx, key, body.ContentType, body.TotalSize)
+	if err != nil {
+		return errorResponse(headers, 500, fmt.Sprintf("failed to start upload: %v", err))
+	}
+
+	headers["Location"] = "/documents/uploads/" + session.UploadID
+	headers["Docker-Upload-UUID"] = session.UploadID
+
+	respBody, _ := json.Marshal(map[string]interface{}{
+		"upload_id":  session.UploadID,
+		"location":   headers["Location"],
+		"chunk_size": session.ChunkSize,
+	})
+	return events.APIGatewayProxyResponse{StatusCode: 202, Headers: headers, Body: string(respBody)}, nil
+}
+
+func (h *ResumableUploadHandler) handleAppend(ctx context.Context, request events.APIGatewayProxyRequest, headers map[string]string) (events.APIGatewayProxyResponse, error) {
+	uploadID, ok := request.PathParameters["upload_id"]
+	if !ok || uploadID == "" {
+		return errorResponse(headers, 400, "upload_id is required")
+	}
+
+	contentRangeHeader := requestHeaderValue(request, "Content-Range")
+	if contentRangeHeader == "" {
+		return errorResponse(headers, 400, "Content-Range header is required")
+	}
+	contentRange, err := upload.ParseContentRange(contentRangeHeader)
+	if err != nil {
+		return errorResponse(headers, 400, err.Error())
+	}
+
+	data := []byte(request.Body)
+	if request.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(request.Body)
+		if err != nil {
+			return errorResponse(headers, 400, "invalid base64 body")
+		}
+		data = decoded
+	}
+
+	session, err := h.manager.AppendChunk(ctx, uploadID, contentRange, data)
+	if err != nil {
+		if err == upload.ErrSessionNotFound {
+			return errorResponse(headers, 404, "upload not found")
+		}
+		return errorResponse(headers, 400, err.Error())
+	}
+
+	headers["Range"] = session.NextRangeHeader()
+	headers["Docker-Upload-UUID"] = session.UploadID
+	return events.APIGatewayProxyResponse{StatusCode: 202, Headers: headers, Body: `{"status":"accepted"}`}, nil
+}
+
+func (h *ResumableUploadHandler) handleFinalize(ctx context.Context, request events.APIGatewayProxyRequest, headers map[string]string) (events.APIGatewayProxyResponse, error) {
+	uploadID, ok := request.PathParameters["upload_id"]
+	if !ok || uploadID == "" {
+		return errorResponse(headers, 400, "upload_id is required")
+	}
+
+	session, err := h.manager.FinalizeUpload(ctx, uploadID)
+	if err != nil {
+		if err == upload.ErrSessionNotFound {
+			return errorResponse(headers, 404, "upload not found")
+		}
+		return errorResponse(headers, 400, err.Error())
+	}
+
+	respBody, _ := json.Marshal(map[string]interface{}{"document_id": session.DocumentID})
+	return events.APIGatewayProxyResponse{StatusCode: 201, Headers: headers, Body: string(respBody)}, nil
+}
+
+func (h *ResumableUploadHandler) handleHead(ctx context.Context, request events.APIGatewayProxyRequest, headers map[string]string) (events.APIGatewayProxyResponse, error) {
+	uploadID, ok := request.PathParameters["upload_id"]
+	if !ok || uploadID == "" {
+		return errorResponse(headers, 400, "upload_id is required")
+	}
+
+	session, err := h.manager.GetSession(ctx, uploadID)
+	if err != nil {
+		if err == upload.ErrSessionNotFound {
+			return errorResponse(headers, 404, "upload not found")
+		}
+		return errorResponse(headers, 500, err.Error())
+	}
+
+	headers["Range"] = session.NextRangeHeader()
+	headers["Docker-Upload-UUID"] = session.UploadID
+	return events.APIGatewayProxyResponse{StatusCode: 204, Headers: headers, Body: ""}, nil
+}
+
+func errorResponse(headers map[string]string, statusCode int, message string) (events.APIGatewayProxyResponse, error) {
+	body, _ := json.Marshal(map[string]string{"error": message})
+	return events.APIGatewayProxyResponse{StatusCode: statusCode, Headers: headers, Body: string(body)}, nil
+}
+
+// requestHeaderValue looks up name in request.Headers, trying both the canonical
+// and lowercased forms since API Gateway doesn't normalize header casing.
+func requestHeaderValue(request events.APIGatewayProxyRequest, name string) string {
+	if v, ok := request.Headers[name]; ok {
+		return v
+	}
+	return request.Headers[strings.ToLower(name)]
+}
+
+func main() {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(os.Getenv("AWS_REGION")),
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to create AWS session: %v", err))
+	}
+
+	bucketName := storage.DocumentBucketFromEnv()
+	tableName := os.Getenv("UPLOAD_SESSIONS_TABLE")
+
+	sessions := upload.NewSessionStore(dynamodb.New(sess), tableName)
+	manager := upload.NewManager(s3.New(sess), sessions, bucketName)
+
+	handler := NewResumableUploadHandler(manager, storage.DocumentPrefixFromEnv())
+	lambda.Start(handler.Handle)
+}
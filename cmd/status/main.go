@@ -47,6 +47,11 @@ func (h *Handler) HandleRequest(ctx context.Context, request events.APIGatewayPr
 		return utils.BadRequestResponse("Method not allowed").ToLambdaResponse()
 	}
 
+	// /documents lists every known document instead of looking up one by ID.
+	if request.Path == "/documents" {
+		return h.ListDocuments(logger)
+	}
+
 	// Extract document ID from path parameters
 	documentID := request.PathParameters["document_id"]
 	if documentID == "" {
@@ -58,8 +63,32 @@ func (h *Handler) HandleRequest(ctx context.Context, request events.APIGatewayPr
 		"document_id": documentID,
 	})
 
-	// TODO: In a real implementation, retrieve document status from DynamoDB
-	// For now, we'll create a mock document status
+	statusResponse := mockStatusResponse(documentID)
+
+	logger.Info("Document status retrieved successfully", map[string]interface{}{
+		"document_id": documentID,
+		"status":      statusResponse.Status,
+		"chunks":      statusResponse.TotalChunks,
+	})
+
+	return utils.SuccessResponse(statusResponse).ToLambdaResponse()
+}
+
+// ListDocuments returns the status of every known document.
+// TODO: In a real implementation, this would query DynamoDB for every
+// tracked document; for now it returns a single mock entry, reusing the same
+// mock status HandleRequest returns for an individual document lookup.
+func (h *Handler) ListDocuments(logger *utils.Logger) (events.APIGatewayProxyResponse, error) {
+	logger.Info("Listing documents")
+
+	documents := []*models.StatusResponse{mockStatusResponse("doc-123")}
+
+	return utils.SuccessResponse(documents).ToLambdaResponse()
+}
+
+// mockStatusResponse builds the placeholder StatusResponse HandleRequest and
+// ListDocuments both return until document status is backed by DynamoDB.
+func mockStatusResponse(documentID string) *models.StatusResponse {
 	document := &models.Document{
 		ID:          documentID,
 		FileName:    "sample.pdf",
@@ -71,7 +100,6 @@ func (h *Handler) HandleRequest(ctx context.Context, request events.APIGatewayPr
 		ErrorMsg:    "",
 	}
 
-	// Create status response
 	uploadedAt := document.UploadedAt.Format(time.RFC3339)
 	var processedAt *string
 	if document.ProcessedAt != nil {
@@ -79,7 +107,7 @@ func (h *Handler) HandleRequest(ctx context.Context, request events.APIGatewayPr
 		processedAt = &processedAtStr
 	}
 
-	statusResponse := &models.StatusResponse{
+	return &models.StatusResponse{
 		DocumentID:           document.ID,
 		Filename:             document.FileName,
 		Status:               document.Status,
@@ -89,14 +117,6 @@ func (h *Handler) HandleRequest(ctx context.Context, request events.APIGatewayPr
 		ChunksWithEmbeddings: 5, // Mock value
 		LastError:            document.ErrorMsg,
 	}
-
-	logger.Info("Document status retrieved successfully", map[string]interface{}{
-		"document_id": documentID,
-		"status":      document.Status,
-		"chunks":      5, // Mock value
-	})
-
-	return utils.SuccessResponse(statusResponse).ToLambdaResponse()
 }
 
 func main() {
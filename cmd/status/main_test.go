@@ -105,6 +105,42 @@ func TestStatusHandler_ValidRequest(t *testing.T) {
 	}
 }
 
+func TestStatusHandler_ListDocuments(t *testing.T) {
+	handler, err := NewHandler()
+	if err != nil {
+		t.Fatalf("Failed to create handler: %v", err)
+	}
+
+	request := events.APIGatewayProxyRequest{
+		HTTPMethod: "GET",
+		Path:       "/documents",
+		RequestContext: events.APIGatewayProxyRequestContext{
+			RequestID: "test-request-id",
+		},
+	}
+
+	response, err := handler.HandleRequest(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Handler returned error: %v", err)
+	}
+
+	if response.StatusCode != 200 {
+		t.Fatalf("Expected status code 200, got %d", response.StatusCode)
+	}
+
+	var documents []models.StatusResponse
+	if err := json.Unmarshal([]byte(response.Body), &documents); err != nil {
+		t.Fatalf("Failed to parse response JSON: %v", err)
+	}
+
+	if len(documents) == 0 {
+		t.Fatal("Expected at least one document")
+	}
+	if documents[0].DocumentID == "" {
+		t.Error("Expected DocumentID to be set")
+	}
+}
+
 func TestStatusResponse_Structure(t *testing.T) {
 	// Test StatusResponse model structure
 	statusResponse := models.StatusResponse{
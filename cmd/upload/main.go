@@ -1,13 +1,28 @@
+// Command upload serves document uploads. Most requests are a single POST with
+// the whole file body, but a large file (e.g. a long PDF over API Gateway's 6MB
+// payload limit) instead goes through a client-driven multipart protocol:
+//
+//	POST /uploads?action=create             start a multipart upload
+//	PUT  /uploads/{upload_id}/parts/{n}      upload one part, returns its ETag
+//	POST /uploads/{upload_id}?action=complete  complete with the part ETags
+//	DELETE /uploads/{upload_id}              abort
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base32"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/url"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,7 +30,14 @@ import (
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+
+	"aws-serverless-rag/internal/filestore"
+	"aws-serverless-rag/internal/models"
+	"aws-serverless-rag/internal/sigv4"
+	"aws-serverless-rag/internal/utils"
 )
 
 type UploadResponse struct {
@@ -23,9 +45,141 @@ type UploadResponse struct {
 	DocumentID string `json:"document_id"`
 	Status     string `json:"status"`
 	FileName   string `json:"file_name"`
+
+	// Metadata is the resolved set of user-supplied metadata (the named fields
+	// below plus any "x-meta-*" field) that was attached to the stored
+	// document, so the caller can confirm what was persisted.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// namedMetadataFields are multipart form fields, besides "file", that are
+// passed through as both document metadata and an S3 object tag. Any other
+// field named "x-meta-<name>" is passed through the same way under <name>.
+var namedMetadataFields = map[string]bool{
+	"title":      true,
+	"source_url": true,
+	"tags":       true,
+	"collection": true,
+}
+
+const metaFieldPrefix = "x-meta-"
+
+// resolveUserMetadata filters the non-file fields collected from a multipart
+// form down to the ones a caller may attach to a stored document, trimming
+// and dropping empty values from the caller-supplied map.
+func resolveUserMetadata(fields map[string]string) map[string]string {
+	resolved := make(map[string]string, len(fields))
+	for key, value := range fields {
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if namedMetadataFields[key] {
+			resolved[key] = value
+			continue
+		}
+		if name := strings.TrimPrefix(key, metaFieldPrefix); name != key && name != "" {
+			resolved[name] = value
+		}
+	}
+	return resolved
+}
+
+// buildTagging renders fields as an S3 Tagging query string
+// ("key1=val1&key2=val2"), URL-encoding each key and value in a stable order.
+func buildTagging(fields map[string]string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, url.QueryEscape(key)+"="+url.QueryEscape(fields[key]))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// largeFileThreshold is the file size above which uploads go through the multipart
+// uploader instead of a single Put, so large PDFs aren't held as one oversized S3
+// request body.
+const largeFileThreshold = 5 * 1024 * 1024
+
+// UploadHandler stores uploaded documents through an injected filestore.Store, so the
+// handler can run against a local directory in tests without real AWS credentials.
+// largeFileUploader is optional: when set (S3 backend only), files at or above
+// largeFileThreshold are streamed through it as a multipart upload instead of store.Put.
+type UploadHandler struct {
+	store             filestore.Store
+	largeFileUploader *filestore.MultipartUploader
+
+	// clientMultipartUploader backs the client-driven multipart endpoints (create
+	// /parts/complete/abort), so a browser can push a file larger than API
+	// Gateway's payload limit without it ever passing through a single POST body.
+	clientMultipartUploader *filestore.ClientMultipartUploader
+
+	// streamingUploader, when set (S3 backend only), handles every single-POST
+	// upload by streaming the multipart part straight to S3 instead of buffering
+	// it through store/largeFileUploader, so it takes priority over both.
+	streamingUploader *filestore.StreamingUploader
+
+	// maxUploadBytes caps how large a single-POST upload may be. Zero means
+	// unlimited. Enforced by both the buffered and streaming upload paths.
+	maxUploadBytes int64
+
+	// sigv4Lookup, when set, requires every non-OPTIONS request to carry a valid
+	// AWS4-HMAC-SHA256 signature (header-signed or a presigned URL) resolved
+	// against it.
+	sigv4Lookup sigv4.SecretLookup
+}
+
+// NewUploadHandler creates a handler backed by the given store.
+func NewUploadHandler(store filestore.Store) *UploadHandler {
+	return &UploadHandler{store: store}
+}
+
+// WithMultipartUploader enables streamed multipart uploads for large files and
+// returns the handler for chaining.
+func (h *UploadHandler) WithMultipartUploader(uploader *filestore.MultipartUploader) *UploadHandler {
+	h.largeFileUploader = uploader
+	return h
+}
+
+// WithClientMultipartUploader enables the client-driven multipart upload
+// endpoints and returns the handler for chaining.
+func (h *UploadHandler) WithClientMultipartUploader(uploader *filestore.ClientMultipartUploader) *UploadHandler {
+	h.clientMultipartUploader = uploader
+	return h
+}
+
+// WithStreamingUploader routes single-POST uploads through uploader instead of
+// buffering them in memory, and returns the handler for chaining.
+func (h *UploadHandler) WithStreamingUploader(uploader *filestore.StreamingUploader) *UploadHandler {
+	h.streamingUploader = uploader
+	return h
 }
 
-func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+// WithMaxUploadBytes rejects single-POST uploads larger than maxBytes with a
+// 413, and returns the handler for chaining. maxBytes <= 0 disables the cap.
+func (h *UploadHandler) WithMaxUploadBytes(maxBytes int64) *UploadHandler {
+	h.maxUploadBytes = maxBytes
+	return h
+}
+
+// WithSigV4 requires every non-OPTIONS request to carry a valid SigV4
+// signature resolved against lookup, and returns the handler for chaining.
+func (h *UploadHandler) WithSigV4(lookup sigv4.SecretLookup) *UploadHandler {
+	h.sigv4Lookup = lookup
+	return h
+}
+
+func (h *UploadHandler) Handle(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	fmt.Println("Upload handler called")
 
 	headers := map[string]string{
@@ -44,6 +198,26 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		}, nil
 	}
 
+	if h.sigv4Lookup != nil {
+		if err := h.verifySigV4(request); err != nil {
+			return jsonErrorResponse(headers, 401, fmt.Sprintf("signature verification failed: %v", err))
+		}
+	}
+
+	uploadID := request.PathParameters["upload_id"]
+	action := request.QueryStringParameters["action"]
+
+	switch {
+	case request.HTTPMethod == "POST" && uploadID == "" && action == "create":
+		return h.handleCreateUpload(ctx, request, headers)
+	case request.HTTPMethod == "PUT" && uploadID != "":
+		return h.handleUploadPart(ctx, request, headers)
+	case request.HTTPMethod == "POST" && uploadID != "" && action == "complete":
+		return h.handleCompleteUpload(ctx, request, headers)
+	case request.HTTPMethod == "DELETE" && uploadID != "":
+		return h.handleAbortUpload(ctx, request, headers)
+	}
+
 	if request.HTTPMethod != "POST" {
 		return events.APIGatewayProxyResponse{
 			StatusCode: 405,
@@ -52,34 +226,75 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		}, nil
 	}
 
-	// Parse multipart form data
-	fileName, fileContent, err := parseMultipartForm(request)
-	if err != nil {
-		fmt.Printf("Parse error: %v\n", err)
+	return h.handleSingleUpload(ctx, request, headers)
+}
+
+// handleSingleUpload serves the primary, whole-body upload path: a single POST
+// with the file as one multipart/form-data part. When h.streamingUploader is
+// set, the part is streamed straight to S3 without ever being buffered in
+// memory; otherwise it's read into memory and stored via h.storeDocument, for
+// the FS-backed dev/test backend.
+func (h *UploadHandler) handleSingleUpload(ctx context.Context, request events.APIGatewayProxyRequest, headers map[string]string) (events.APIGatewayProxyResponse, error) {
+	var fileName, documentID string
+	var duplicate bool
+	var fields, userMetadata map[string]string
+	var parseErr, storeErr error
+
+	if h.streamingUploader != nil {
+		var contentType string
+		var part io.ReadCloser
+		fileName, contentType, part, fields, parseErr = parseMultipartFormPart(request)
+		if parseErr == nil {
+			userMetadata = resolveUserMetadata(fields)
+			documentID, duplicate, storeErr = h.storeDocumentStreaming(ctx, fileName, contentType, part, userMetadata)
+		}
+	} else {
+		var fileContent []byte
+		fileName, fileContent, fields, parseErr = parseMultipartForm(request, h.maxUploadBytes)
+		if parseErr == nil {
+			userMetadata = resolveUserMetadata(fields)
+			documentID, duplicate, storeErr = h.storeDocument(ctx, fileName, fileContent, userMetadata)
+		}
+	}
+
+	if parseErr == filestore.ErrUploadTooLarge || storeErr == filestore.ErrUploadTooLarge {
+		return jsonErrorResponse(headers, 413, "file exceeds maximum upload size")
+	}
+	if parseErr != nil {
+		fmt.Printf("Parse error: %v\n", parseErr)
 		return events.APIGatewayProxyResponse{
 			StatusCode: 400,
 			Headers:    headers,
-			Body:       fmt.Sprintf(`{"error": "Failed to parse file: %s"}`, err.Error()),
+			Body:       fmt.Sprintf(`{"error": "Failed to parse file: %s"}`, parseErr.Error()),
 		}, nil
 	}
-
-	// Upload to S3
-	documentID, err := uploadToS3(fileName, fileContent)
-	if err != nil {
-		fmt.Printf("S3 upload error: %v\n", err)
+	if storeErr != nil {
+		fmt.Printf("Storage error: %v\n", storeErr)
+		mapping := models.MapAWSError(storeErr)
+		if mapping.RetryAfter != "" {
+			headers["Retry-After"] = mapping.RetryAfter
+		}
+		body, _ := json.Marshal(mapping.Response)
 		return events.APIGatewayProxyResponse{
-			StatusCode: 500,
+			StatusCode: mapping.StatusCode,
 			Headers:    headers,
-			Body:       fmt.Sprintf(`{"error": "Failed to upload file: %s"}`, err.Error()),
+			Body:       string(body),
 		}, nil
 	}
 
 	// Return success response
+	status := "completed"
+	message := "File uploaded successfully"
+	if duplicate {
+		status = "duplicate"
+		message = "File already uploaded"
+	}
 	response := UploadResponse{
-		Message:    "File uploaded successfully",
+		Message:    message,
 		DocumentID: documentID,
-		Status:     "completed",
+		Status:     status,
 		FileName:   fileName,
+		Metadata:   userMetadata,
 	}
 
 	responseBody, err := json.Marshal(response)
@@ -99,40 +314,330 @@ func handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 	}, nil
 }
 
-func parseMultipartForm(request events.APIGatewayProxyRequest) (string, []byte, error) {
-	// Get content type header
-	contentType := request.Headers["content-type"]
+// storeDocument writes fileContent to the store under a key derived purely
+// from its content hash and returns the resulting document ID. documentID is
+// the SHA-256 of fileContent, base32-encoded; the S3 key embeds the hex
+// digest instead, so re-uploading identical content lands on the same key
+// and is reported as a duplicate rather than stored (and re-embedded) again,
+// regardless of the filename it's uploaded under. The original filename is
+// preserved only in metadata, not the key. userMetadata, resolved from the
+// request's other form fields, is attached alongside the document's own
+// bookkeeping metadata and, if non-empty, as an S3 object tag.
+func (h *UploadHandler) storeDocument(ctx context.Context, fileName string, fileContent []byte, userMetadata map[string]string) (string, bool, error) {
+	sum := sha256.Sum256(fileContent)
+	sha256Hex := hex.EncodeToString(sum[:])
+	documentID := base32.StdEncoding.EncodeToString(sum[:])
+	key := fmt.Sprintf("documents/prod/%s", sha256Hex)
+
+	exists, err := h.store.Exists(ctx, key)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to check for existing file: %w", err)
+	}
+	if exists {
+		return documentID, true, nil
+	}
+
+	metadata := make(map[string]string, len(userMetadata)+5)
+	for k, v := range userMetadata {
+		metadata[k] = v
+	}
+	metadata["document-id"] = documentID
+	metadata["original-name"] = fileName
+	metadata["uploaded-at"] = time.Now().Format(time.RFC3339)
+	metadata["content-type"] = detectContentType(fileName)
+	metadata["sha256"] = sha256Hex
+	if tagging := buildTagging(userMetadata); tagging != "" {
+		metadata[filestore.TaggingMetadataKey] = tagging
+	}
+
+	if h.largeFileUploader != nil && len(fileContent) >= largeFileThreshold {
+		if err := h.largeFileUploader.Upload(ctx, key, bytes.NewReader(fileContent), metadata); err != nil {
+			return "", false, fmt.Errorf("failed to store large file: %w", err)
+		}
+		return documentID, false, nil
+	}
+
+	if err := h.store.Put(ctx, key, bytes.NewReader(fileContent), metadata); err != nil {
+		return "", false, fmt.Errorf("failed to store file: %w", err)
+	}
+
+	return documentID, false, nil
+}
+
+// storeDocumentStreaming streams part straight to S3 via h.streamingUploader,
+// never buffering the whole file in memory. It applies the same
+// content-addressed deduplication as storeDocument, computing the final key
+// from the uploaded content's hash as the stream is read. userMetadata is
+// attached the same way storeDocument attaches it: as document metadata and,
+// if non-empty, as an S3 object tag.
+func (h *UploadHandler) storeDocumentStreaming(ctx context.Context, fileName, contentType string, part io.ReadCloser, userMetadata map[string]string) (string, bool, error) {
+	defer part.Close()
+
+	metadata := make(map[string]string, len(userMetadata)+2)
+	for k, v := range userMetadata {
+		metadata[k] = v
+	}
+	metadata["original-name"] = fileName
+	metadata["uploaded-at"] = time.Now().Format(time.RFC3339)
+	metadata["content-type"] = contentType
+
+	documentID, duplicate, err := h.streamingUploader.Upload(ctx, fileName, contentType, part, metadata, buildTagging(userMetadata), h.maxUploadBytes)
+	if err != nil {
+		if err == filestore.ErrUploadTooLarge {
+			return "", false, err
+		}
+		return "", false, fmt.Errorf("failed to stream file: %w", err)
+	}
+
+	return documentID, duplicate, nil
+}
+
+// verifySigV4 checks request's AWS4-HMAC-SHA256 signature against h.sigv4Lookup.
+func (h *UploadHandler) verifySigV4(request events.APIGatewayProxyRequest) error {
+	body := []byte(request.Body)
+	if request.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(request.Body)
+		if err != nil {
+			return fmt.Errorf("invalid base64 body")
+		}
+		body = decoded
+	}
+
+	query := url.Values{}
+	for key, value := range request.QueryStringParameters {
+		query.Set(key, value)
+	}
+
+	return sigv4.Verify(sigv4.Request{
+		Method:  request.HTTPMethod,
+		Path:    request.Path,
+		Query:   query,
+		Headers: request.Headers,
+		Body:    body,
+	}, h.sigv4Lookup, time.Now())
+}
+
+// CreateUploadResponse is returned by POST /uploads?action=create.
+type CreateUploadResponse struct {
+	UploadID string `json:"upload_id"`
+	Key      string `json:"key"`
+}
+
+// UploadPartResponse is returned by PUT /uploads/{upload_id}/parts/{n}.
+type UploadPartResponse struct {
+	ETag string `json:"etag"`
+}
+
+// handleCreateUpload starts a client-driven multipart upload and returns the
+// UploadId and target key the client will reference for every subsequent part.
+func (h *UploadHandler) handleCreateUpload(ctx context.Context, request events.APIGatewayProxyRequest, headers map[string]string) (events.APIGatewayProxyResponse, error) {
+	if h.clientMultipartUploader == nil {
+		return jsonErrorResponse(headers, 501, "multipart uploads are not enabled for this backend")
+	}
+
+	var body struct {
+		FileName    string `json:"file_name"`
+		ContentType string `json:"content_type"`
+	}
+	if err := json.Unmarshal([]byte(request.Body), &body); err != nil {
+		return jsonErrorResponse(headers, 400, "invalid request body")
+	}
+	if body.FileName == "" {
+		return jsonErrorResponse(headers, 400, "file_name is required")
+	}
+
+	contentType := body.ContentType
 	if contentType == "" {
-		contentType = request.Headers["Content-Type"]
+		contentType = detectContentType(body.FileName)
 	}
 
-	if !strings.HasPrefix(contentType, "multipart/form-data") {
-		return "", nil, fmt.Errorf("invalid content type: %s", contentType)
+	documentID := fmt.Sprintf("doc-%d", time.Now().UnixNano())
+	key := fmt.Sprintf("documents/prod/%s", body.FileName)
+
+	session, err := h.clientMultipartUploader.CreateUpload(ctx, documentID, key, body.FileName, contentType)
+	if err != nil {
+		return jsonErrorResponse(headers, 500, fmt.Sprintf("failed to create upload: %v", err))
 	}
 
-	// Decode base64 body if needed
-	var bodyReader io.Reader
+	respBody, err := json.Marshal(CreateUploadResponse{UploadID: session.UploadID, Key: session.Key})
+	if err != nil {
+		return jsonErrorResponse(headers, 500, "failed to marshal response")
+	}
+	return events.APIGatewayProxyResponse{StatusCode: 200, Headers: headers, Body: string(respBody)}, nil
+}
+
+// handleUploadPart uploads one raw binary part of an in-progress multipart
+// upload and returns its ETag.
+func (h *UploadHandler) handleUploadPart(ctx context.Context, request events.APIGatewayProxyRequest, headers map[string]string) (events.APIGatewayProxyResponse, error) {
+	if h.clientMultipartUploader == nil {
+		return jsonErrorResponse(headers, 501, "multipart uploads are not enabled for this backend")
+	}
+
+	partNumber, err := strconv.ParseInt(request.PathParameters["part_number"], 10, 64)
+	if err != nil {
+		return jsonErrorResponse(headers, 400, "part number must be an integer")
+	}
+
+	data := []byte(request.Body)
 	if request.IsBase64Encoded {
-		decodedBody, err := base64.StdEncoding.DecodeString(request.Body)
+		decoded, err := base64.StdEncoding.DecodeString(request.Body)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to decode base64 body: %w", err)
+			return jsonErrorResponse(headers, 400, "invalid base64 body")
 		}
-		bodyReader = strings.NewReader(string(decodedBody))
-	} else {
-		bodyReader = strings.NewReader(request.Body)
+		data = decoded
+	}
+
+	etag, err := h.clientMultipartUploader.UploadPart(ctx, request.PathParameters["upload_id"], partNumber, data)
+	if err != nil {
+		if err == filestore.ErrUploadNotFound {
+			return jsonErrorResponse(headers, 404, "upload not found")
+		}
+		return jsonErrorResponse(headers, 400, err.Error())
+	}
+
+	respBody, err := json.Marshal(UploadPartResponse{ETag: etag})
+	if err != nil {
+		return jsonErrorResponse(headers, 500, "failed to marshal response")
+	}
+	return events.APIGatewayProxyResponse{StatusCode: 200, Headers: headers, Body: string(respBody)}, nil
+}
+
+// handleCompleteUpload finishes a multipart upload given the client's list of
+// {part_number, etag}, returning the same UploadResponse shape as a regular
+// single-POST upload.
+func (h *UploadHandler) handleCompleteUpload(ctx context.Context, request events.APIGatewayProxyRequest, headers map[string]string) (events.APIGatewayProxyResponse, error) {
+	if h.clientMultipartUploader == nil {
+		return jsonErrorResponse(headers, 501, "multipart uploads are not enabled for this backend")
+	}
+
+	var parts []filestore.PartInput
+	if err := json.Unmarshal([]byte(request.Body), &parts); err != nil {
+		return jsonErrorResponse(headers, 400, "invalid request body: expected a JSON list of {part_number, etag}")
+	}
+
+	session, err := h.clientMultipartUploader.CompleteUpload(ctx, request.PathParameters["upload_id"], parts)
+	if err != nil {
+		if err == filestore.ErrUploadNotFound {
+			return jsonErrorResponse(headers, 404, "upload not found")
+		}
+		return jsonErrorResponse(headers, 400, err.Error())
+	}
+
+	respBody, err := json.Marshal(UploadResponse{
+		Message:    "File uploaded successfully",
+		DocumentID: session.DocumentID,
+		Status:     "completed",
+		FileName:   session.FileName,
+	})
+	if err != nil {
+		return jsonErrorResponse(headers, 500, "failed to marshal response")
+	}
+	return events.APIGatewayProxyResponse{StatusCode: 200, Headers: headers, Body: string(respBody)}, nil
+}
+
+// handleAbortUpload cancels an in-progress multipart upload.
+func (h *UploadHandler) handleAbortUpload(ctx context.Context, request events.APIGatewayProxyRequest, headers map[string]string) (events.APIGatewayProxyResponse, error) {
+	if h.clientMultipartUploader == nil {
+		return jsonErrorResponse(headers, 501, "multipart uploads are not enabled for this backend")
+	}
+
+	if err := h.clientMultipartUploader.AbortUpload(ctx, request.PathParameters["upload_id"]); err != nil {
+		if err == filestore.ErrUploadNotFound {
+			return jsonErrorResponse(headers, 404, "upload not found")
+		}
+		return jsonErrorResponse(headers, 500, err.Error())
+	}
+
+	return events.APIGatewayProxyResponse{StatusCode: 200, Headers: headers, Body: `{"status":"aborted"}`}, nil
+}
+
+// jsonErrorResponse builds a {"error": message} response at statusCode.
+func jsonErrorResponse(headers map[string]string, statusCode int, message string) (events.APIGatewayProxyResponse, error) {
+	body, _ := json.Marshal(map[string]string{"error": message})
+	return events.APIGatewayProxyResponse{StatusCode: statusCode, Headers: headers, Body: string(body)}, nil
+}
+
+// newMultipartReader builds a multipart.Reader over request's body, decoding a
+// base64-encoded body through base64.NewDecoder rather than fully decoding it
+// into memory first.
+func newMultipartReader(request events.APIGatewayProxyRequest) (*multipart.Reader, error) {
+	contentType := request.Headers["content-type"]
+	if contentType == "" {
+		contentType = request.Headers["Content-Type"]
+	}
+	if !strings.HasPrefix(contentType, "multipart/form-data") {
+		return nil, fmt.Errorf("invalid content type: %s", contentType)
+	}
+
+	var bodyReader io.Reader = strings.NewReader(request.Body)
+	if request.IsBase64Encoded {
+		bodyReader = base64.NewDecoder(base64.StdEncoding, strings.NewReader(request.Body))
 	}
 
-	// Extract boundary from content type
 	boundary := extractBoundary(contentType)
 	if boundary == "" {
-		return "", nil, fmt.Errorf("no boundary found in content type")
+		return nil, fmt.Errorf("no boundary found in content type")
+	}
+
+	return multipart.NewReader(bodyReader, boundary), nil
+}
+
+// parseMultipartFormPart walks request's multipart form and returns the "file"
+// part itself as an unread stream, along with its file name, detected content
+// type, and any other form fields read before it, so the caller can stream the
+// file onward without ever buffering it. Because the file part is returned
+// unread, fields after it in the form are never reached, so metadata fields
+// must precede the file field. The caller must close the returned reader.
+func parseMultipartFormPart(request events.APIGatewayProxyRequest) (string, string, io.ReadCloser, map[string]string, error) {
+	reader, err := newMultipartReader(request)
+	if err != nil {
+		return "", "", nil, nil, err
+	}
+
+	fields := map[string]string{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return "", "", nil, nil, fmt.Errorf("no file found in multipart form")
+		}
+		if err != nil {
+			return "", "", nil, nil, fmt.Errorf("failed to read multipart form: %w", err)
+		}
+
+		if part.FormName() != "file" {
+			value, err := io.ReadAll(part)
+			part.Close()
+			if err != nil {
+				return "", "", nil, nil, fmt.Errorf("failed to read form field %q: %w", part.FormName(), err)
+			}
+			fields[part.FormName()] = string(value)
+			continue
+		}
+
+		fileName := part.FileName()
+		if fileName == "" {
+			part.Close()
+			return "", "", nil, nil, fmt.Errorf("file name is empty")
+		}
+
+		return fileName, detectContentType(fileName), part, fields, nil
 	}
+}
 
-	// Parse multipart form
-	reader := multipart.NewReader(bodyReader, boundary)
+// parseMultipartForm reads request's "file" part fully into memory and returns
+// its name and content, along with every other form field (e.g. title,
+// source_url, tags, collection, x-meta-*), wherever in the form they appear.
+// If maxUploadBytes is positive and the part yields more than that many
+// bytes, it returns filestore.ErrUploadTooLarge instead of the file content.
+func parseMultipartForm(request events.APIGatewayProxyRequest, maxUploadBytes int64) (string, []byte, map[string]string, error) {
+	reader, err := newMultipartReader(request)
+	if err != nil {
+		return "", nil, nil, err
+	}
 
 	var fileName string
 	var fileContent []byte
+	fields := map[string]string{}
 
 	for {
 		part, err := reader.NextPart()
@@ -140,31 +645,46 @@ func parseMultipartForm(request events.APIGatewayProxyRequest) (string, []byte,
 			break
 		}
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to read multipart form: %w", err)
+			return "", nil, nil, fmt.Errorf("failed to read multipart form: %w", err)
 		}
 
 		if part.FormName() == "file" {
 			fileName = part.FileName()
 			if fileName == "" {
 				part.Close()
-				return "", nil, fmt.Errorf("file name is empty")
+				return "", nil, nil, fmt.Errorf("file name is empty")
+			}
+
+			var partReader io.Reader = part
+			if maxUploadBytes > 0 {
+				partReader = io.LimitReader(part, maxUploadBytes+1)
 			}
 
-			// Read file content
-			fileContent, err = io.ReadAll(part)
+			fileContent, err = io.ReadAll(partReader)
+			if err != nil {
+				part.Close()
+				return "", nil, nil, fmt.Errorf("failed to read file content: %w", err)
+			}
+			if maxUploadBytes > 0 && int64(len(fileContent)) > maxUploadBytes {
+				part.Close()
+				return "", nil, nil, filestore.ErrUploadTooLarge
+			}
+		} else {
+			value, err := io.ReadAll(part)
 			if err != nil {
 				part.Close()
-				return "", nil, fmt.Errorf("failed to read file content: %w", err)
+				return "", nil, nil, fmt.Errorf("failed to read form field %q: %w", part.FormName(), err)
 			}
+			fields[part.FormName()] = string(value)
 		}
 		part.Close()
 	}
 
 	if fileName == "" || len(fileContent) == 0 {
-		return "", nil, fmt.Errorf("no file found in multipart form")
+		return "", nil, nil, fmt.Errorf("no file found in multipart form")
 	}
 
-	return fileName, fileContent, nil
+	return fileName, fileContent, fields, nil
 }
 
 func extractBoundary(contentType string) string {
@@ -178,51 +698,6 @@ func extractBoundary(contentType string) string {
 	return ""
 }
 
-func uploadToS3(fileName string, fileContent []byte) (string, error) {
-	// Create AWS session
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String("ap-northeast-1"),
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to create AWS session: %w", err)
-	}
-
-	// Create S3 service client
-	svc := s3.New(sess)
-
-	// Get bucket name from environment variable
-	bucketName := os.Getenv("S3_BUCKET_NAME")
-	if bucketName == "" {
-		bucketName = "aws-serverless-rag-prod-documents-prod" // fallback
-	}
-
-	// Generate unique document ID using nanosecond timestamp (ASCII-safe)
-	now := time.Now()
-	documentID := fmt.Sprintf("doc-%d", now.UnixNano())
-
-	// Create S3 key
-	s3Key := fmt.Sprintf("documents/prod/%s", fileName)
-
-	// Upload to S3
-	_, err = svc.PutObject(&s3.PutObjectInput{
-		Bucket:      aws.String(bucketName),
-		Key:         aws.String(s3Key),
-		Body:        strings.NewReader(string(fileContent)),
-		ContentType: aws.String(detectContentType(fileName)),
-		Metadata: map[string]*string{
-			"document-id":   aws.String(documentID),
-			"original-name": aws.String(fileName),
-			"uploaded-at":   aws.String(time.Now().Format(time.RFC3339)),
-		},
-	})
-
-	if err != nil {
-		return "", fmt.Errorf("failed to upload file to S3: %w", err)
-	}
-
-	return documentID, nil
-}
-
 func detectContentType(fileName string) string {
 	ext := strings.ToLower(fileName[strings.LastIndex(fileName, ".")+1:])
 	switch ext {
@@ -246,5 +721,43 @@ func detectContentType(fileName string) string {
 }
 
 func main() {
-	lambda.Start(handler)
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(os.Getenv("AWS_REGION")),
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to create AWS session: %v", err))
+	}
+
+	bucketName := os.Getenv("S3_BUCKET_NAME")
+	if bucketName == "" {
+		bucketName = "aws-serverless-rag-prod-documents-prod" // fallback
+	}
+
+	store, err := filestore.NewFromEnv(sess, bucketName)
+	if err != nil {
+		panic(fmt.Sprintf("failed to create storage backend: %v", err))
+	}
+
+	handler := NewUploadHandler(store)
+	if os.Getenv("STORAGE_BACKEND") != "fs" {
+		handler = handler.WithMultipartUploader(filestore.NewMultipartUploader(s3.New(sess), bucketName))
+		handler = handler.WithClientMultipartUploader(filestore.NewClientMultipartUploader(s3.New(sess), bucketName))
+		handler = handler.WithStreamingUploader(filestore.NewStreamingUploader(sess, s3.New(sess), bucketName))
+	}
+
+	if maxUploadBytes, err := strconv.ParseInt(os.Getenv("MAX_UPLOAD_BYTES"), 10, 64); err == nil && maxUploadBytes > 0 {
+		handler = handler.WithMaxUploadBytes(maxUploadBytes)
+	}
+
+	if secretPrefix := os.Getenv("SIGV4_SECRET_PREFIX"); secretPrefix != "" {
+		handler = handler.WithSigV4(sigv4.SecretsManagerLookup(secretsmanager.New(sess), secretPrefix))
+	}
+
+	var h utils.APIGatewayHandlerFunc = handler.Handle
+	if tableName := os.Getenv("IDEMPOTENCY_TABLE_NAME"); tableName != "" {
+		idempotency := utils.NewIdempotency(dynamodb.New(sess), tableName, 0)
+		h = idempotency.WithIdempotency(h)
+	}
+
+	lambda.Start(h)
 }
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"aws-serverless-rag/internal/filestore"
+)
+
+func newMultipartRequest(t *testing.T, fileName string, content []byte) events.APIGatewayProxyRequest {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatalf("failed to write form file content: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close multipart writer: %v", err)
+	}
+
+	return events.APIGatewayProxyRequest{
+		HTTPMethod: "POST",
+		Headers:    map[string]string{"Content-Type": writer.FormDataContentType()},
+		Body:       buf.String(),
+	}
+}
+
+// newMultipartRequestWithFields builds a multipart/form-data request carrying
+// extra text fields (in map iteration order, which is unspecified) before the
+// "file" part.
+func newMultipartRequestWithFields(t *testing.T, fields map[string]string, fileName string, content []byte) events.APIGatewayProxyRequest {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			t.Fatalf("failed to write field %q: %v", name, err)
+		}
+	}
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		t.Fatalf("failed to write form file content: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close multipart writer: %v", err)
+	}
+
+	return events.APIGatewayProxyRequest{
+		HTTPMethod: "POST",
+		Headers:    map[string]string{"Content-Type": writer.FormDataContentType()},
+		Body:       buf.String(),
+	}
+}
+
+func TestResolveUserMetadata_FiltersAndCleansFields(t *testing.T) {
+	resolved := resolveUserMetadata(map[string]string{
+		"title":         "  Q3 Report  ",
+		"source_url":    "https://example.com/report.pdf",
+		"x-meta-tenant": " acme ",
+		"x-meta-":       "dropped, empty name after prefix",
+		"unrelated":     "dropped, not a recognized field",
+		"collection":    "   ",
+	})
+
+	want := map[string]string{
+		"title":      "Q3 Report",
+		"source_url": "https://example.com/report.pdf",
+		"tenant":     "acme",
+	}
+	if len(resolved) != len(want) {
+		t.Fatalf("got %d resolved fields, want %d: %v", len(resolved), len(want), resolved)
+	}
+	for k, v := range want {
+		if resolved[k] != v {
+			t.Errorf("got resolved[%q]=%q, want %q", k, resolved[k], v)
+		}
+	}
+}
+
+func TestBuildTagging_URLEncodesKeyValuePairs(t *testing.T) {
+	got := buildTagging(map[string]string{"collection": "docs", "tenant": "acme corp"})
+	want := "collection=docs&tenant=acme+corp"
+	if got != want {
+		t.Errorf("got tagging %q, want %q", got, want)
+	}
+
+	if got := buildTagging(nil); got != "" {
+		t.Errorf("got tagging %q for empty fields, want empty string", got)
+	}
+}
+
+func TestUploadHandler_Handle_AttachesUserMetadata(t *testing.T) {
+	store, err := filestore.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore failed: %v", err)
+	}
+	handler := NewUploadHandler(store)
+
+	request := newMultipartRequestWithFields(t, map[string]string{
+		"title":         "Q3 Report",
+		"x-meta-tenant": "acme",
+	}, "report.pdf", []byte("file content"))
+
+	resp, err := handler.Handle(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("got status %d, want 200: %s", resp.StatusCode, resp.Body)
+	}
+
+	var uploadResp UploadResponse
+	if err := json.Unmarshal([]byte(resp.Body), &uploadResp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if uploadResp.Metadata["title"] != "Q3 Report" {
+		t.Errorf("got response metadata[title]=%q, want Q3 Report", uploadResp.Metadata["title"])
+	}
+	if uploadResp.Metadata["tenant"] != "acme" {
+		t.Errorf("got response metadata[tenant]=%q, want acme", uploadResp.Metadata["tenant"])
+	}
+
+	objects, err := store.List(context.Background(), "documents/prod/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("expected 1 stored object, got %d", len(objects))
+	}
+
+	_, metadata, err := store.Get(context.Background(), objects[0].Key)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if metadata["title"] != "Q3 Report" {
+		t.Errorf("got stored metadata[title]=%q, want Q3 Report", metadata["title"])
+	}
+	if metadata["tenant"] != "acme" {
+		t.Errorf("got stored metadata[tenant]=%q, want acme", metadata["tenant"])
+	}
+}
+
+func TestUploadHandler_Handle_Success(t *testing.T) {
+	store, err := filestore.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore failed: %v", err)
+	}
+	handler := NewUploadHandler(store)
+
+	resp, err := handler.Handle(context.Background(), newMultipartRequest(t, "report.pdf", []byte("file content")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("got status %d, want 200: %s", resp.StatusCode, resp.Body)
+	}
+
+	objects, err := store.List(context.Background(), "documents/prod/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("expected 1 stored object, got %d", len(objects))
+	}
+}
+
+func TestUploadHandler_Handle_DuplicateContentReportedOnce(t *testing.T) {
+	store, err := filestore.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore failed: %v", err)
+	}
+	handler := NewUploadHandler(store)
+	ctx := context.Background()
+
+	first, err := handler.Handle(ctx, newMultipartRequest(t, "report.pdf", []byte("file content")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.StatusCode != 200 {
+		t.Fatalf("got status %d, want 200: %s", first.StatusCode, first.Body)
+	}
+
+	second, err := handler.Handle(ctx, newMultipartRequest(t, "report.pdf", []byte("file content")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.StatusCode != 200 {
+		t.Fatalf("got status %d, want 200: %s", second.StatusCode, second.Body)
+	}
+
+	var firstResp, secondResp UploadResponse
+	if err := json.Unmarshal([]byte(first.Body), &firstResp); err != nil {
+		t.Fatalf("failed to unmarshal first response: %v", err)
+	}
+	if err := json.Unmarshal([]byte(second.Body), &secondResp); err != nil {
+		t.Fatalf("failed to unmarshal second response: %v", err)
+	}
+
+	if firstResp.DocumentID != secondResp.DocumentID {
+		t.Errorf("got document IDs %q and %q, want the same content-addressed ID", firstResp.DocumentID, secondResp.DocumentID)
+	}
+	if secondResp.Status != "duplicate" {
+		t.Errorf("got status %q, want duplicate", secondResp.Status)
+	}
+
+	objects, err := store.List(ctx, "documents/prod/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("expected 1 stored object after a duplicate upload, got %d", len(objects))
+	}
+}
+
+func TestUploadHandler_Handle_DuplicateContentDifferentFilenameReportedOnce(t *testing.T) {
+	store, err := filestore.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore failed: %v", err)
+	}
+	handler := NewUploadHandler(store)
+	ctx := context.Background()
+
+	first, err := handler.Handle(ctx, newMultipartRequest(t, "report.pdf", []byte("file content")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.StatusCode != 200 {
+		t.Fatalf("got status %d, want 200: %s", first.StatusCode, first.Body)
+	}
+
+	second, err := handler.Handle(ctx, newMultipartRequest(t, "copy-of-report.pdf", []byte("file content")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second.StatusCode != 200 {
+		t.Fatalf("got status %d, want 200: %s", second.StatusCode, second.Body)
+	}
+
+	var firstResp, secondResp UploadResponse
+	if err := json.Unmarshal([]byte(first.Body), &firstResp); err != nil {
+		t.Fatalf("failed to unmarshal first response: %v", err)
+	}
+	if err := json.Unmarshal([]byte(second.Body), &secondResp); err != nil {
+		t.Fatalf("failed to unmarshal second response: %v", err)
+	}
+
+	if firstResp.DocumentID != secondResp.DocumentID {
+		t.Errorf("got document IDs %q and %q, want the same content-addressed ID", firstResp.DocumentID, secondResp.DocumentID)
+	}
+	if secondResp.Status != "duplicate" {
+		t.Errorf("got status %q, want duplicate", secondResp.Status)
+	}
+
+	objects, err := store.List(ctx, "documents/prod/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("expected 1 stored object after a duplicate upload under a different filename, got %d", len(objects))
+	}
+}
+
+func TestParseMultipartFormPart_ReturnsUnbufferedPart(t *testing.T) {
+	request := newMultipartRequest(t, "report.pdf", []byte("file content"))
+
+	fileName, contentType, part, _, err := parseMultipartFormPart(request)
+	if err != nil {
+		t.Fatalf("parseMultipartFormPart failed: %v", err)
+	}
+	defer part.Close()
+
+	if fileName != "report.pdf" {
+		t.Errorf("got fileName %q, want report.pdf", fileName)
+	}
+	if contentType != "application/pdf" {
+		t.Errorf("got contentType %q, want application/pdf", contentType)
+	}
+
+	content, err := io.ReadAll(part)
+	if err != nil {
+		t.Fatalf("failed to read part: %v", err)
+	}
+	if string(content) != "file content" {
+		t.Errorf("got content %q, want %q", content, "file content")
+	}
+}
+
+func TestParseMultipartFormPart_Base64EncodedBody(t *testing.T) {
+	plain := newMultipartRequest(t, "report.pdf", []byte("file content"))
+	request := events.APIGatewayProxyRequest{
+		HTTPMethod:      "POST",
+		Headers:         plain.Headers,
+		Body:            base64.StdEncoding.EncodeToString([]byte(plain.Body)),
+		IsBase64Encoded: true,
+	}
+
+	fileName, _, part, _, err := parseMultipartFormPart(request)
+	if err != nil {
+		t.Fatalf("parseMultipartFormPart failed: %v", err)
+	}
+	defer part.Close()
+
+	if fileName != "report.pdf" {
+		t.Errorf("got fileName %q, want report.pdf", fileName)
+	}
+
+	content, err := io.ReadAll(part)
+	if err != nil {
+		t.Fatalf("failed to read part: %v", err)
+	}
+	if string(content) != "file content" {
+		t.Errorf("got content %q, want %q", content, "file content")
+	}
+}
+
+func TestUploadHandler_Handle_RejectsOversizedUpload(t *testing.T) {
+	store, err := filestore.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore failed: %v", err)
+	}
+	handler := NewUploadHandler(store).WithMaxUploadBytes(4)
+
+	resp, err := handler.Handle(context.Background(), newMultipartRequest(t, "report.pdf", []byte("file content")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 413 {
+		t.Fatalf("got status %d, want 413: %s", resp.StatusCode, resp.Body)
+	}
+}
+
+func TestUploadHandler_Handle_SigV4_RejectsUnsignedRequest(t *testing.T) {
+	store, err := filestore.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore failed: %v", err)
+	}
+	handler := NewUploadHandler(store).WithSigV4(func(accessKeyID string) (string, bool, error) {
+		return "secret", true, nil
+	})
+
+	resp, err := handler.Handle(context.Background(), newMultipartRequest(t, "report.pdf", []byte("file content")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 401 {
+		t.Fatalf("got status %d, want 401: %s", resp.StatusCode, resp.Body)
+	}
+}
+
+func TestUploadHandler_Handle_MissingFile(t *testing.T) {
+	store, err := filestore.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore failed: %v", err)
+	}
+	handler := NewUploadHandler(store)
+
+	resp, err := handler.Handle(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod: "POST",
+		Headers:    map[string]string{"Content-Type": "multipart/form-data; boundary=xxx"},
+		Body:       "",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 400 {
+		t.Fatalf("got status %d, want 400", resp.StatusCode)
+	}
+}
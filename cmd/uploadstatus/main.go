@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/filestore"
+)
+
+// UploadStatusResponse reports in-flight multipart uploads for a document so a client
+// that lost its connection can resume with the existing UploadId instead of
+// restarting the upload from scratch.
+type UploadStatusResponse struct {
+	DocumentID string                     `json:"document_id"`
+	Uploads    []filestore.InFlightUpload `json:"in_flight_uploads"`
+}
+
+// UploadStatusHandler lists in-flight multipart uploads for a document's S3 prefix.
+type UploadStatusHandler struct {
+	uploader *filestore.MultipartUploader
+}
+
+// NewUploadStatusHandler creates a handler backed by the given multipart uploader.
+func NewUploadStatusHandler(uploader *filestore.MultipartUploader) *UploadStatusHandler {
+	return &UploadStatusHandler{uploader: uploader}
+}
+
+func (h *UploadStatusHandler) Handle(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	headers := map[string]string{
+		"Content-Type":                 "application/json",
+		"Access-Control-Allow-Origin":  "*",
+		"Access-Control-Allow-Methods": "*",
+		"Access-Control-Allow-Headers": "*",
+	}
+
+	if request.HTTPMethod == "OPTIONS" {
+		return events.APIGatewayProxyResponse{StatusCode: 200, Headers: headers, Body: `{"message": "OK"}`}, nil
+	}
+
+	if request.HTTPMethod != "GET" {
+		return events.APIGatewayProxyResponse{StatusCode: 405, Headers: headers, Body: `{"error": "Method not allowed"}`}, nil
+	}
+
+	documentID, exists := request.PathParameters["document_id"]
+	if !exists || documentID == "" {
+		return events.APIGatewayProxyResponse{StatusCode: 400, Headers: headers, Body: `{"error": "document_id is required"}`}, nil
+	}
+
+	// A resumed client only knows the document ID, not the original file name, so
+	// uploads are discovered by document ID prefix rather than an exact key.
+	prefix := fmt.Sprintf("documents/prod/%s", documentID)
+	uploads, err := h.uploader.ListInFlightUploads(ctx, prefix)
+	if err != nil {
+		log.Printf("Failed to list in-flight uploads for %s: %v", documentID, err)
+		return events.APIGatewayProxyResponse{StatusCode: 500, Headers: headers, Body: `{"error": "Failed to list in-flight uploads"}`}, nil
+	}
+
+	body, err := json.Marshal(UploadStatusResponse{DocumentID: documentID, Uploads: uploads})
+	if err != nil {
+		return events.APIGatewayProxyResponse{StatusCode: 500, Headers: headers, Body: `{"error": "Failed to create response"}`}, nil
+	}
+
+	return events.APIGatewayProxyResponse{StatusCode: 200, Headers: headers, Body: string(body)}, nil
+}
+
+func main() {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(os.Getenv("AWS_REGION")),
+	})
+	if err != nil {
+		panic(fmt.Sprintf("failed to create AWS session: %v", err))
+	}
+
+	bucketName := os.Getenv("S3_BUCKET_NAME")
+	if bucketName == "" {
+		bucketName = "aws-serverless-rag-prod-documents-prod" // fallback
+	}
+
+	uploader := filestore.NewMultipartUploader(s3.New(sess), bucketName)
+	handler := NewUploadStatusHandler(uploader)
+	lambda.Start(handler.Handle)
+}
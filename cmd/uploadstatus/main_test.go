@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/filestore"
+	"aws-serverless-rag/internal/storage/mocks"
+)
+
+func TestUploadStatusHandler_Handle_ListsInFlightUploads(t *testing.T) {
+	client := &mocks.S3Client{
+		ListMultipartUploadsFunc: func(input *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error) {
+			return &s3.ListMultipartUploadsOutput{
+				Uploads: []*s3.MultipartUpload{
+					{Key: aws.String("documents/prod/doc-123-report.pdf"), UploadId: aws.String("upload-1")},
+				},
+			}, nil
+		},
+	}
+	handler := NewUploadStatusHandler(filestore.NewMultipartUploader(client, "test-bucket"))
+
+	resp, err := handler.Handle(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod:     "GET",
+		PathParameters: map[string]string{"document_id": "doc-123"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("got status %d, want 200: %s", resp.StatusCode, resp.Body)
+	}
+	if len(client.ListMultipartUploadsCalls) != 1 {
+		t.Fatalf("expected 1 ListMultipartUploads call, got %d", len(client.ListMultipartUploadsCalls))
+	}
+}
+
+func TestUploadStatusHandler_Handle_MissingDocumentID(t *testing.T) {
+	handler := NewUploadStatusHandler(filestore.NewMultipartUploader(&mocks.S3Client{}, "test-bucket"))
+
+	resp, err := handler.Handle(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: "GET"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 400 {
+		t.Fatalf("got status %d, want 400", resp.StatusCode)
+	}
+}
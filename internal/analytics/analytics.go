@@ -0,0 +1,169 @@
+// Package analytics persists a log entry for every answered query to DynamoDB, so the
+// admin dashboard can report real usage stats instead of the hard-coded placeholders
+// it shipped with.
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// dateIndexName is the GSI used to scan queries by day without a table-wide Scan.
+// It must be configured with partition key "date" (string, "YYYY-MM-DD") and sort
+// key "timestamp".
+const dateIndexName = "date-index"
+
+// QueryLog records one answered query. The table needs a string partition key
+// "user_id" and a string sort key "timestamp" (RFC3339, so lexicographic and
+// chronological order agree), plus dateIndexName.
+type QueryLog struct {
+	UserID      string    `json:"user_id"`
+	Timestamp   time.Time `json:"timestamp"`
+	Date        string    `json:"date"`
+	Question    string    `json:"question"`
+	Answer      string    `json:"answer"`
+	SourceCount int       `json:"source_count"`
+	LatencyMS   int64     `json:"latency_ms"`
+}
+
+// Recorder writes QueryLog entries for the query Lambda and reads them back for the
+// dashboard Lambda.
+type Recorder struct {
+	client    *dynamodb.DynamoDB
+	tableName string
+}
+
+// NewRecorder creates a Recorder backed by tableName.
+func NewRecorder(client *dynamodb.DynamoDB, tableName string) *Recorder {
+	return &Recorder{client: client, tableName: tableName}
+}
+
+// Record persists one answered query. userID may be empty for anonymous callers.
+func (r *Recorder) Record(ctx context.Context, userID, question, answer string, sourceCount int, latency time.Duration) error {
+	now := time.Now()
+	if userID == "" {
+		userID = "anonymous"
+	}
+
+	log := QueryLog{
+		UserID:      userID,
+		Timestamp:   now,
+		Date:        now.Format("2006-01-02"),
+		Question:    question,
+		Answer:      answer,
+		SourceCount: sourceCount,
+		LatencyMS:   latency.Milliseconds(),
+	}
+
+	item, err := dynamodbattribute.MarshalMap(log)
+	if err != nil {
+		return fmt.Errorf("failed to marshal query log: %w", err)
+	}
+
+	_, err = r.client.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record query log: %w", err)
+	}
+	return nil
+}
+
+// CountLast24h returns how many queries were answered in the last 24 hours.
+func (r *Recorder) CountLast24h(ctx context.Context) (int, error) {
+	logs, err := r.last24h(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(logs), nil
+}
+
+// DistinctUsersLast24h returns how many distinct users queried in the last 24 hours.
+func (r *Recorder) DistinctUsersLast24h(ctx context.Context) (int, error) {
+	logs, err := r.last24h(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	users := make(map[string]bool, len(logs))
+	for _, log := range logs {
+		users[log.UserID] = true
+	}
+	return len(users), nil
+}
+
+// RecentN returns the n most recently answered queries across all users from
+// the last 24 hours, newest first; older queries are not returned.
+func (r *Recorder) RecentN(ctx context.Context, n int) ([]QueryLog, error) {
+	logs, err := r.last24h(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sortByTimestampDescending(logs)
+	if n > 0 && len(logs) > n {
+		logs = logs[:n]
+	}
+	return logs, nil
+}
+
+// last24h queries dateIndexName for today and, since a 24h window can span a day
+// boundary, yesterday too, then drops anything older than the cutoff.
+func (r *Recorder) last24h(ctx context.Context) ([]QueryLog, error) {
+	now := time.Now()
+	cutoff := now.Add(-24 * time.Hour)
+
+	var logs []QueryLog
+	for _, date := range []string{now.Format("2006-01-02"), cutoff.Format("2006-01-02")} {
+		dayLogs, err := r.queryByDate(ctx, date)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range dayLogs {
+			if !log.Timestamp.Before(cutoff) {
+				logs = append(logs, log)
+			}
+		}
+	}
+	return logs, nil
+}
+
+func (r *Recorder) queryByDate(ctx context.Context, date string) ([]QueryLog, error) {
+	out, err := r.client.QueryWithContext(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(dateIndexName),
+		KeyConditionExpression: aws.String("#date = :date"),
+		ExpressionAttributeNames: map[string]*string{
+			"#date": aws.String("date"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":date": {S: aws.String(date)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query query log for date %s: %w", date, err)
+	}
+
+	logs := make([]QueryLog, 0, len(out.Items))
+	for _, item := range out.Items {
+		var log QueryLog
+		if err := dynamodbattribute.UnmarshalMap(item, &log); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal query log: %w", err)
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+func sortByTimestampDescending(logs []QueryLog) {
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].Timestamp.After(logs[j].Timestamp)
+	})
+}
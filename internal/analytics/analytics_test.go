@@ -0,0 +1,27 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSortByTimestampDescending(t *testing.T) {
+	t1 := time.Date(2026, 7, 26, 10, 0, 0, 0, time.UTC)
+	t2 := time.Date(2026, 7, 26, 11, 0, 0, 0, time.UTC)
+	t3 := time.Date(2026, 7, 26, 9, 0, 0, 0, time.UTC)
+
+	logs := []QueryLog{
+		{UserID: "a", Timestamp: t1},
+		{UserID: "b", Timestamp: t2},
+		{UserID: "c", Timestamp: t3},
+	}
+
+	sortByTimestampDescending(logs)
+
+	want := []string{"b", "a", "c"}
+	for i, id := range want {
+		if logs[i].UserID != id {
+			t.Errorf("position %d: got user %q, want %q", i, logs[i].UserID, id)
+		}
+	}
+}
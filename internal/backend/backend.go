@@ -0,0 +1,52 @@
+// Package backend defines the pluggable embedding/completion model interfaces the
+// RAG pipeline runs against, so Lambdas and tests can target Bedrock, a local gRPC
+// sidecar, or an in-process mock without the caller knowing which. See registry.go
+// for how a concrete implementation is selected.
+package backend
+
+import "context"
+
+// TokenUsage reports token accounting for a single Embed or Complete call.
+type TokenUsage struct {
+	PromptTokens     int
+	CompletionTokens int
+	EmbeddingTokens  int
+}
+
+// Add returns the element-wise sum of u and other, for accumulating usage across a
+// batch of calls.
+func (u TokenUsage) Add(other TokenUsage) TokenUsage {
+	return TokenUsage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		EmbeddingTokens:  u.EmbeddingTokens + other.EmbeddingTokens,
+	}
+}
+
+// Token is one piece of a streamed completion, sent on the channel returned by
+// CompletionProvider.Complete. Done marks the final Token, at which point Usage is
+// populated; earlier tokens carry only Text.
+type Token struct {
+	Text  string
+	Done  bool
+	Usage TokenUsage
+}
+
+// CompletionOptions configures a CompletionProvider.Complete call.
+type CompletionOptions struct {
+	MaxTokens   int
+	Temperature float64
+}
+
+// EmbeddingProvider generates vector embeddings for a batch of texts.
+type EmbeddingProvider interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, TokenUsage, error)
+}
+
+// CompletionProvider generates a streamed answer for a prompt. The TokenUsage
+// returned alongside the channel is not yet known when Complete returns (the
+// backend hasn't finished generating); it's always the zero value. The real usage
+// arrives on the channel's final Token, where Done is true.
+type CompletionProvider interface {
+	Complete(ctx context.Context, prompt string, opts CompletionOptions) (<-chan Token, TokenUsage, error)
+}
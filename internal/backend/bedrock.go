@@ -0,0 +1,63 @@
+package backend
+
+import (
+	"context"
+
+	"aws-serverless-rag/internal/services"
+)
+
+// BedrockProvider adapts a services.BedrockClient to EmbeddingProvider and
+// CompletionProvider, so it can be registered under the "bedrock" backend name.
+type BedrockProvider struct {
+	client *services.BedrockClient
+}
+
+// NewBedrockProvider wraps client as a Backend's Embedding/Completion providers.
+func NewBedrockProvider(client *services.BedrockClient) *BedrockProvider {
+	return &BedrockProvider{client: client}
+}
+
+// Embed generates embeddings via Titan. Titan's InvokeModel response in this repo
+// doesn't surface a token count, so the returned TokenUsage.EmbeddingTokens is
+// always 0.
+func (p *BedrockProvider) Embed(ctx context.Context, texts []string) ([][]float32, TokenUsage, error) {
+	embeddings, err := p.client.GenerateEmbeddings(ctx, texts)
+	if err != nil {
+		return nil, TokenUsage{}, err
+	}
+	return embeddings, TokenUsage{}, nil
+}
+
+// Complete generates an answer via Claude, relaying text deltas from
+// services.BedrockClient.GenerateAnswerStream as they arrive. The final Token on
+// the channel has Done set and carries the accumulated TokenUsage; as documented
+// on CompletionProvider, TokenUsage is unknown until then.
+func (p *BedrockProvider) Complete(ctx context.Context, prompt string, opts CompletionOptions) (<-chan Token, TokenUsage, error) {
+	deltas := make(chan string)
+	tokens := make(chan Token)
+
+	go func() {
+		defer close(tokens)
+		for delta := range deltas {
+			select {
+			case tokens <- Token{Text: delta}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(deltas)
+		_, claudeUsage, _ := p.client.GenerateAnswerStream(ctx, prompt, nil, deltas)
+		tokens <- Token{
+			Done: true,
+			Usage: TokenUsage{
+				PromptTokens:     claudeUsage.InputTokens,
+				CompletionTokens: claudeUsage.OutputTokens,
+			},
+		}
+	}()
+
+	return tokens, TokenUsage{}, nil
+}
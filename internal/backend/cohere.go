@@ -0,0 +1,108 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/bedrockruntime"
+
+	"aws-serverless-rag/internal/utils"
+)
+
+// cohereEnglishV3ModelID is the Bedrock model ID CohereEnglishV3Provider invokes.
+const cohereEnglishV3ModelID = "cohere.embed-english-v3"
+
+// cohereEnglishV3Dimension is CohereEnglishV3Provider's fixed output dimension.
+const cohereEnglishV3Dimension = 1024
+
+// cohereMaxTextsPerCall is the largest batch Cohere's Bedrock embed endpoint
+// accepts in a single InvokeModel call.
+const cohereMaxTextsPerCall = 96
+
+// cohereEmbeddingRequest is the InvokeModel request body Cohere's embed endpoint
+// accepts. Unlike Titan, it takes a batch of Texts in one call.
+type cohereEmbeddingRequest struct {
+	Texts     []string `json:"texts"`
+	InputType string   `json:"input_type"`
+}
+
+// cohereEmbeddingResponse is the InvokeModel response body Cohere's embed
+// endpoint returns.
+type cohereEmbeddingResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// CohereEnglishV3Provider embeds via cohere.embed-english-v3, a fixed 1024-dim
+// model whose Bedrock endpoint accepts a batch of texts per InvokeModel call
+// (unlike Titan's one-at-a-time inputText), so Embed packs up to
+// cohereMaxTextsPerCall texts into each call.
+type CohereEnglishV3Provider struct {
+	client *bedrockruntime.BedrockRuntime
+	logger *utils.Logger
+
+	// InputType is Cohere's embedding-purpose hint ("search_document",
+	// "search_query", "classification", "clustering"). Defaults to
+	// "search_document" (indexing a chunk) when unset; callers embedding a query
+	// at search time should set "search_query" instead.
+	InputType string
+}
+
+// NewCohereEnglishV3Provider creates a CohereEnglishV3Provider invoking Bedrock
+// through client, defaulting InputType to "search_document".
+func NewCohereEnglishV3Provider(client *bedrockruntime.BedrockRuntime, logger *utils.Logger) *CohereEnglishV3Provider {
+	return &CohereEnglishV3Provider{client: client, logger: logger, InputType: "search_document"}
+}
+
+// Name identifies this provider in chunk Metadata; see DescribedEmbeddingProvider.
+func (p *CohereEnglishV3Provider) Name() string { return "cohere-english-v3" }
+
+// Dimension is CohereEnglishV3Provider's fixed output dimension.
+func (p *CohereEnglishV3Provider) Dimension() int { return cohereEnglishV3Dimension }
+
+// Embed generates a Cohere embedding for each of texts, in order, packing up to
+// cohereMaxTextsPerCall texts (and staying under maxInputTokensPerCall) into each
+// InvokeModel call. Cohere's InvokeModel response doesn't report token usage, so
+// TokenUsage.EmbeddingTokens is len(texts) (one per text), not a real token count.
+func (p *CohereEnglishV3Provider) Embed(ctx context.Context, texts []string) ([][]float32, TokenUsage, error) {
+	if len(texts) == 0 {
+		return nil, TokenUsage{}, fmt.Errorf("no texts provided")
+	}
+
+	inputType := p.InputType
+	if inputType == "" {
+		inputType = "search_document"
+	}
+
+	embeddings := make([][]float32, 0, len(texts))
+	for _, batch := range batchTexts(texts, cohereMaxTextsPerCall) {
+		requestBody, err := json.Marshal(cohereEmbeddingRequest{Texts: batch, InputType: inputType})
+		if err != nil {
+			return nil, TokenUsage{}, fmt.Errorf("failed to marshal embedding request: %w", err)
+		}
+
+		result, err := p.client.InvokeModelWithContext(ctx, &bedrockruntime.InvokeModelInput{
+			ModelId:     aws.String(cohereEnglishV3ModelID),
+			ContentType: aws.String("application/json"),
+			Accept:      aws.String("application/json"),
+			Body:        requestBody,
+		})
+		if err != nil {
+			p.logger.ErrorWithErr("Failed to invoke Cohere embedding model", err)
+			return nil, TokenUsage{}, fmt.Errorf("failed to invoke embedding model %s: %w", cohereEnglishV3ModelID, err)
+		}
+
+		var response cohereEmbeddingResponse
+		if err := json.Unmarshal(result.Body, &response); err != nil {
+			return nil, TokenUsage{}, fmt.Errorf("failed to unmarshal embedding response: %w", err)
+		}
+		if len(response.Embeddings) != len(batch) {
+			return nil, TokenUsage{}, fmt.Errorf("expected %d embeddings, got %d", len(batch), len(response.Embeddings))
+		}
+
+		embeddings = append(embeddings, response.Embeddings...)
+	}
+
+	return embeddings, TokenUsage{EmbeddingTokens: len(texts)}, nil
+}
@@ -0,0 +1,133 @@
+// Package grpcprovider implements backend.EmbeddingProvider and
+// backend.CompletionProvider against a gRPC sidecar speaking the EmbedService and
+// PredictService methods described in grpcprovider.proto, so a Lambda can be
+// pointed at a local model server (a llama.cpp sidecar, an embedding ECS service,
+// ...) without AWS egress.
+//
+// This package doesn't use protoc-generated stubs for grpcprovider.proto -- this
+// repo has no protoc/protoc-gen-go toolchain available in its build environment --
+// so messages are plain Go structs sent over gRPC's "json" content-subtype codec
+// instead of real protobuf wire encoding. A deployment with codegen available can
+// swap this for generated stubs from grpcprovider.proto without changing the
+// backend.EmbeddingProvider/CompletionProvider contract other callers depend on.
+package grpcprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+
+	"aws-serverless-rag/internal/backend"
+)
+
+const codecName = "json"
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return codecName }
+
+// Provider calls a gRPC server implementing EmbedService/PredictService over a
+// single persistent connection.
+type Provider struct {
+	conn *grpc.ClientConn
+}
+
+// Dial connects to a gRPC server at target (e.g. "model-sidecar:50051").
+func Dial(target string) (*Provider, error) {
+	conn, err := grpc.Dial(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("grpcprovider: failed to dial %s: %w", target, err)
+	}
+	return &Provider{conn: conn}, nil
+}
+
+// Close tears down the underlying gRPC connection.
+func (p *Provider) Close() error {
+	return p.conn.Close()
+}
+
+type embedRequest struct {
+	Texts []string `json:"texts"`
+}
+
+type embedResponse struct {
+	Embeddings [][]float32        `json:"embeddings"`
+	Usage      backend.TokenUsage `json:"usage"`
+}
+
+// Embed calls EmbedService.Embed.
+func (p *Provider) Embed(ctx context.Context, texts []string) ([][]float32, backend.TokenUsage, error) {
+	req := embedRequest{Texts: texts}
+	var resp embedResponse
+	if err := p.conn.Invoke(ctx, "/backend.EmbedService/Embed", &req, &resp); err != nil {
+		return nil, backend.TokenUsage{}, fmt.Errorf("grpcprovider: Embed RPC failed: %w", err)
+	}
+	return resp.Embeddings, resp.Usage, nil
+}
+
+type predictRequest struct {
+	Prompt      string  `json:"prompt"`
+	MaxTokens   int     `json:"max_tokens"`
+	Temperature float64 `json:"temperature"`
+}
+
+type predictToken struct {
+	Text  string             `json:"text"`
+	Done  bool               `json:"done"`
+	Usage backend.TokenUsage `json:"usage"`
+}
+
+// Complete calls PredictService.Predict and relays each streamed PredictToken as a
+// backend.Token until the server sends Done or the stream ends.
+func (p *Provider) Complete(ctx context.Context, prompt string, opts backend.CompletionOptions) (<-chan backend.Token, backend.TokenUsage, error) {
+	stream, err := p.conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, "/backend.PredictService/Predict")
+	if err != nil {
+		return nil, backend.TokenUsage{}, fmt.Errorf("grpcprovider: Predict RPC failed: %w", err)
+	}
+
+	req := predictRequest{Prompt: prompt, MaxTokens: opts.MaxTokens, Temperature: opts.Temperature}
+	if err := stream.SendMsg(&req); err != nil {
+		return nil, backend.TokenUsage{}, fmt.Errorf("grpcprovider: failed to send Predict request: %w", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, backend.TokenUsage{}, fmt.Errorf("grpcprovider: failed to close Predict request stream: %w", err)
+	}
+
+	tokens := make(chan backend.Token)
+	go func() {
+		defer close(tokens)
+		for {
+			var tok predictToken
+			if err := stream.RecvMsg(&tok); err != nil {
+				return
+			}
+
+			select {
+			case tokens <- backend.Token{Text: tok.Text, Done: tok.Done, Usage: tok.Usage}:
+			case <-ctx.Done():
+				return
+			}
+			if tok.Done {
+				return
+			}
+		}
+	}()
+
+	return tokens, backend.TokenUsage{}, nil
+}
+
+var _ io.Closer = (*Provider)(nil)
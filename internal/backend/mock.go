@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// DefaultMockDimension is MockProvider's embedding dimension when NewMockProvider
+// isn't given a more specific one.
+const DefaultMockDimension = 8
+
+// MockProvider implements both EmbeddingProvider and CompletionProvider without
+// calling out to any real model, for tests and load-testing the pipeline itself
+// (see cmd/loadtest's "mock" backend). Embeddings are deterministic, derived from a
+// hash of the input text, so the same text always embeds to the same vector across
+// calls; Complete streams a single canned answer.
+type MockProvider struct {
+	Dimension    int
+	CannedAnswer string
+}
+
+// NewMockProvider creates a MockProvider with DefaultMockDimension and a fixed
+// canned answer.
+func NewMockProvider() *MockProvider {
+	return &MockProvider{Dimension: DefaultMockDimension, CannedAnswer: "This is a mock answer."}
+}
+
+// Embed returns one deterministic embedding per text. TokenUsage.EmbeddingTokens is
+// set to len(texts) (one "token" per text) since there's no real tokenizer behind
+// this provider.
+func (p *MockProvider) Embed(ctx context.Context, texts []string) ([][]float32, TokenUsage, error) {
+	dimension := p.Dimension
+	if dimension <= 0 {
+		dimension = DefaultMockDimension
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i] = deterministicEmbedding(text, dimension)
+	}
+	return embeddings, TokenUsage{EmbeddingTokens: len(texts)}, nil
+}
+
+// Complete streams p.CannedAnswer one rune at a time, then a final Done token.
+func (p *MockProvider) Complete(ctx context.Context, prompt string, opts CompletionOptions) (<-chan Token, TokenUsage, error) {
+	answer := p.CannedAnswer
+	if answer == "" {
+		answer = "This is a mock answer."
+	}
+
+	tokens := make(chan Token)
+	go func() {
+		defer close(tokens)
+		for _, r := range answer {
+			select {
+			case tokens <- Token{Text: string(r)}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case tokens <- Token{Done: true, Usage: TokenUsage{CompletionTokens: len([]rune(answer))}}:
+		case <-ctx.Done():
+		}
+	}()
+
+	return tokens, TokenUsage{}, nil
+}
+
+// deterministicEmbedding derives a fixed-dimension vector from sha256(text), so
+// repeated calls for the same text return the same embedding without needing a
+// stored table.
+func deterministicEmbedding(text string, dimension int) []float32 {
+	sum := sha256.Sum256([]byte(text))
+
+	embedding := make([]float32, dimension)
+	for i := range embedding {
+		// Cycle through the 32 hash bytes, 4 at a time, for however many float32s
+		// dimension needs.
+		offset := (i * 4) % (len(sum) - 3)
+		bits := binary.LittleEndian.Uint32(sum[offset : offset+4])
+		// Map to roughly [-1, 1], like a real normalized embedding.
+		embedding[i] = float32(bits)/float32(1<<32)*2 - 1
+	}
+	return embedding
+}
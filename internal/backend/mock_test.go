@@ -0,0 +1,81 @@
+package backend
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMockProvider_EmbedIsDeterministic(t *testing.T) {
+	p := NewMockProvider()
+
+	a, _, err := p.Embed(context.Background(), []string{"hello"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	b, _, err := p.Embed(context.Background(), []string{"hello"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	if len(a[0]) != len(b[0]) {
+		t.Fatalf("expected equal-length embeddings, got %d vs %d", len(a[0]), len(b[0]))
+	}
+	for i := range a[0] {
+		if a[0][i] != b[0][i] {
+			t.Fatalf("expected identical embeddings for the same text, differed at index %d", i)
+		}
+	}
+}
+
+func TestMockProvider_EmbedVariesByText(t *testing.T) {
+	p := NewMockProvider()
+
+	embeddings, _, err := p.Embed(context.Background(), []string{"hello", "goodbye"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	if len(embeddings) != 2 {
+		t.Fatalf("expected 2 embeddings, got %d", len(embeddings))
+	}
+	if embeddings[0][0] == embeddings[1][0] && embeddings[0][len(embeddings[0])-1] == embeddings[1][len(embeddings[1])-1] {
+		t.Error("expected different text to produce different embeddings")
+	}
+}
+
+func TestMockProvider_EmbedUsesDimension(t *testing.T) {
+	p := &MockProvider{Dimension: 16}
+
+	embeddings, _, err := p.Embed(context.Background(), []string{"hello"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if len(embeddings[0]) != 16 {
+		t.Errorf("expected a 16-dimensional embedding, got %d", len(embeddings[0]))
+	}
+}
+
+func TestMockProvider_CompleteStreamsCannedAnswer(t *testing.T) {
+	p := &MockProvider{CannedAnswer: "hi"}
+
+	tokens, _, err := p.Complete(context.Background(), "question", CompletionOptions{})
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	var text string
+	var sawDone bool
+	for tok := range tokens {
+		text += tok.Text
+		if tok.Done {
+			sawDone = true
+		}
+	}
+
+	if text != "hi" {
+		t.Errorf("expected streamed text %q, got %q", "hi", text)
+	}
+	if !sawDone {
+		t.Error("expected a final Done token")
+	}
+}
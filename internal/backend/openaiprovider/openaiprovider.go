@@ -0,0 +1,230 @@
+// Package openaiprovider implements backend.EmbeddingProvider and
+// backend.CompletionProvider against any OpenAI-compatible HTTP API: OpenAI itself,
+// and the many self-hosted runtimes (Ollama, vLLM, LM Studio, ...) that speak the
+// same /embeddings and /chat/completions request/response shapes. Pointing BaseURL
+// at a local Ollama instance's OpenAI-compat endpoint (typically
+// http://localhost:11434/v1) uses this same provider without any code changes.
+package openaiprovider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"aws-serverless-rag/internal/backend"
+)
+
+// DefaultEmbeddingModel and DefaultCompletionModel are used when Provider's
+// corresponding field is left empty.
+const (
+	DefaultEmbeddingModel  = "text-embedding-3-small"
+	DefaultCompletionModel = "gpt-4o-mini"
+)
+
+// Provider calls an OpenAI-compatible HTTP API for embeddings and chat completions.
+type Provider struct {
+	BaseURL         string
+	APIKey          string
+	EmbeddingModel  string
+	CompletionModel string
+	HTTPClient      *http.Client
+}
+
+// NewProvider creates a Provider against baseURL (no trailing slash, e.g.
+// "https://api.openai.com/v1"), authenticating with apiKey via a Bearer token.
+func NewProvider(baseURL, apiKey string) *Provider {
+	return &Provider{
+		BaseURL:         strings.TrimSuffix(baseURL, "/"),
+		APIKey:          apiKey,
+		EmbeddingModel:  DefaultEmbeddingModel,
+		CompletionModel: DefaultCompletionModel,
+		HTTPClient:      http.DefaultClient,
+	}
+}
+
+type embeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+	Usage struct {
+		PromptTokens int `json:"prompt_tokens"`
+		TotalTokens  int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// Embed calls POST {BaseURL}/embeddings with every text in one request.
+func (p *Provider) Embed(ctx context.Context, texts []string) ([][]float32, backend.TokenUsage, error) {
+	model := p.EmbeddingModel
+	if model == "" {
+		model = DefaultEmbeddingModel
+	}
+
+	var resp embeddingResponse
+	if err := p.post(ctx, "/embeddings", embeddingRequest{Model: model, Input: texts}, &resp); err != nil {
+		return nil, backend.TokenUsage{}, fmt.Errorf("openaiprovider: embeddings request failed: %w", err)
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(embeddings) {
+			continue
+		}
+		embeddings[d.Index] = d.Embedding
+	}
+
+	return embeddings, backend.TokenUsage{EmbeddingTokens: resp.Usage.TotalTokens}, nil
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int           `json:"max_tokens,omitempty"`
+	Temperature float64       `json:"temperature,omitempty"`
+	Stream      bool          `json:"stream"`
+}
+
+type chatCompletionChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason *string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// Complete calls POST {BaseURL}/chat/completions with stream:true and relays each
+// delta's content as a backend.Token as it arrives over the response's
+// text/event-stream body. Usage isn't reported by the streaming chat completions
+// API on most OpenAI-compatible runtimes, so the final Token's TokenUsage is
+// always zero-value, same as backend.BedrockProvider's Complete before its final
+// usage arrives.
+func (p *Provider) Complete(ctx context.Context, prompt string, opts backend.CompletionOptions) (<-chan backend.Token, backend.TokenUsage, error) {
+	model := p.CompletionModel
+	if model == "" {
+		model = DefaultCompletionModel
+	}
+
+	body, err := json.Marshal(chatCompletionRequest{
+		Model:       model,
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+		MaxTokens:   opts.MaxTokens,
+		Temperature: opts.Temperature,
+		Stream:      true,
+	})
+	if err != nil {
+		return nil, backend.TokenUsage{}, fmt.Errorf("openaiprovider: failed to marshal chat completion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, backend.TokenUsage{}, fmt.Errorf("openaiprovider: failed to build chat completion request: %w", err)
+	}
+	p.setHeaders(req)
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return nil, backend.TokenUsage{}, fmt.Errorf("openaiprovider: chat completion request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, backend.TokenUsage{}, fmt.Errorf("openaiprovider: chat completion returned status %d", resp.StatusCode)
+	}
+
+	tokens := make(chan backend.Token)
+	go func() {
+		defer close(tokens)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || !strings.HasPrefix(line, "data:") {
+				continue
+			}
+
+			data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if data == "[DONE]" {
+				break
+			}
+
+			var chunk chatCompletionChunk
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				continue
+			}
+			if len(chunk.Choices) == 0 {
+				continue
+			}
+			if delta := chunk.Choices[0].Delta.Content; delta != "" {
+				select {
+				case tokens <- backend.Token{Text: delta}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+
+		select {
+		case tokens <- backend.Token{Done: true}:
+		case <-ctx.Done():
+		}
+	}()
+
+	return tokens, backend.TokenUsage{}, nil
+}
+
+func (p *Provider) post(ctx context.Context, path string, reqBody, respBody interface{}) error {
+	encoded, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.BaseURL+path, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	p.setHeaders(req)
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(respBody); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func (p *Provider) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	if p.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+}
+
+func (p *Provider) client() *http.Client {
+	if p.HTTPClient != nil {
+		return p.HTTPClient
+	}
+	return http.DefaultClient
+}
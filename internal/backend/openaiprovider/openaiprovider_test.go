@@ -0,0 +1,85 @@
+package openaiprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"aws-serverless-rag/internal/backend"
+)
+
+func TestProvider_Embed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			t.Errorf("expected path /embeddings, got %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer test-key" {
+			t.Errorf("expected Authorization header, got %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"data": [
+				{"embedding": [0.1, 0.2], "index": 1},
+				{"embedding": [0.3, 0.4], "index": 0}
+			],
+			"usage": {"prompt_tokens": 4, "total_tokens": 4}
+		}`)
+	}))
+	defer server.Close()
+
+	p := NewProvider(server.URL, "test-key")
+	embeddings, usage, err := p.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+
+	if len(embeddings) != 2 {
+		t.Fatalf("expected 2 embeddings, got %d", len(embeddings))
+	}
+	if embeddings[0][0] != 0.3 || embeddings[1][0] != 0.1 {
+		t.Errorf("expected embeddings reordered by index, got %v", embeddings)
+	}
+	if usage.EmbeddingTokens != 4 {
+		t.Errorf("expected 4 embedding tokens, got %d", usage.EmbeddingTokens)
+	}
+}
+
+func TestProvider_Complete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"hel\"}}]}\n\n")
+		flusher.Flush()
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\n")
+		flusher.Flush()
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		flusher.Flush()
+	}))
+	defer server.Close()
+
+	p := NewProvider(server.URL, "test-key")
+	tokens, _, err := p.Complete(context.Background(), "hi", backend.CompletionOptions{})
+	if err != nil {
+		t.Fatalf("Complete failed: %v", err)
+	}
+
+	var text string
+	var sawDone bool
+	for tok := range tokens {
+		text += tok.Text
+		if tok.Done {
+			sawDone = true
+		}
+	}
+
+	if text != "hello" {
+		t.Errorf("expected streamed text %q, got %q", "hello", text)
+	}
+	if !sawDone {
+		t.Error("expected a final Done token")
+	}
+}
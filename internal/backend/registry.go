@@ -0,0 +1,55 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Backend bundles the embedding and completion providers for one backend
+// implementation, registered under a name and selectable via the BACKEND
+// environment variable.
+type Backend struct {
+	Embedding  EmbeddingProvider
+	Completion CompletionProvider
+}
+
+// DefaultBackendName is used by FromEnv when the BACKEND environment variable isn't
+// set.
+const DefaultBackendName = "bedrock"
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Backend{}
+)
+
+// Register adds (or replaces) the backend available under name, for later retrieval
+// via Get or FromEnv. Callers typically do this from an init func or, in tests,
+// before constructing the code under test.
+func Register(name string, b Backend) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = b
+}
+
+// Get returns the backend registered under name.
+func Get(name string) (Backend, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	b, ok := registry[name]
+	if !ok {
+		return Backend{}, fmt.Errorf("backend: no backend registered under %q", name)
+	}
+	return b, nil
+}
+
+// FromEnv returns the backend selected by the BACKEND environment variable
+// (bedrock, grpc, ...), defaulting to DefaultBackendName when unset.
+func FromEnv() (Backend, error) {
+	name := os.Getenv("BACKEND")
+	if name == "" {
+		name = DefaultBackendName
+	}
+	return Get(name)
+}
@@ -0,0 +1,71 @@
+package backend
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+type stubEmbeddingProvider struct{}
+
+func (stubEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, TokenUsage, error) {
+	return nil, TokenUsage{}, nil
+}
+
+func TestRegisterAndGet(t *testing.T) {
+	Register("test-backend", Backend{Embedding: stubEmbeddingProvider{}})
+
+	b, err := Get("test-backend")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.Embedding == nil {
+		t.Error("expected Embedding provider to be set")
+	}
+}
+
+func TestGet_UnknownBackend(t *testing.T) {
+	if _, err := Get("does-not-exist"); err == nil {
+		t.Error("expected an error for an unregistered backend name")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	Register("bedrock", Backend{Embedding: stubEmbeddingProvider{}})
+	Register("custom", Backend{Embedding: stubEmbeddingProvider{}})
+
+	t.Run("defaults to bedrock when unset", func(t *testing.T) {
+		os.Unsetenv("BACKEND")
+		b, err := FromEnv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b.Embedding == nil {
+			t.Error("expected the default backend's Embedding provider to be set")
+		}
+	})
+
+	t.Run("honors BACKEND env var", func(t *testing.T) {
+		os.Setenv("BACKEND", "custom")
+		defer os.Unsetenv("BACKEND")
+
+		b, err := FromEnv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b.Embedding == nil {
+			t.Error("expected custom backend's Embedding provider to be set")
+		}
+	})
+}
+
+func TestTokenUsage_Add(t *testing.T) {
+	a := TokenUsage{PromptTokens: 1, CompletionTokens: 2, EmbeddingTokens: 3}
+	b := TokenUsage{PromptTokens: 10, CompletionTokens: 20, EmbeddingTokens: 30}
+
+	got := a.Add(b)
+	want := TokenUsage{PromptTokens: 11, CompletionTokens: 22, EmbeddingTokens: 33}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,197 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/bedrockruntime"
+
+	"aws-serverless-rag/internal/services"
+	"aws-serverless-rag/internal/utils"
+)
+
+// DescribedEmbeddingProvider is implemented by EmbeddingProviders that can report
+// which model produced an embedding and at what dimension, so callers (see
+// performance.GenerateEmbeddingsConcurrently) can stamp that provenance onto
+// models.Chunk.Metadata instead of assuming every embedding is Titan v1's 1536
+// dims.
+type DescribedEmbeddingProvider interface {
+	EmbeddingProvider
+	Name() string
+	Dimension() int
+}
+
+// maxInputTokensPerCall is the token budget Bedrock's embedding endpoints accept
+// for a single InvokeModel call's input, shared by Titan and Cohere. batchTexts
+// uses services.EstimateTokens (the same heuristic services.SemanticChunker sizes
+// its output against) to keep each packed batch under it.
+const maxInputTokensPerCall = 8000
+
+// batchTexts groups texts into batches of at most maxPerCall texts, and, within
+// that, as many as fit under maxInputTokensPerCall combined estimated tokens. A
+// maxPerCall of 1 (Titan v1/v2's InvokeModel body only accepts one inputText)
+// degenerates to one text per batch; Cohere's batch endpoint accepts a larger
+// maxPerCall.
+func batchTexts(texts []string, maxPerCall int) [][]string {
+	var batches [][]string
+	var current []string
+	currentTokens := 0
+
+	for _, text := range texts {
+		tokens := services.EstimateTokens(text)
+
+		if len(current) > 0 && (len(current) >= maxPerCall || currentTokens+tokens > maxInputTokensPerCall) {
+			batches = append(batches, current)
+			current = nil
+			currentTokens = 0
+		}
+
+		current = append(current, text)
+		currentTokens += tokens
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+
+	return batches
+}
+
+// titanV1ModelID and titanV2ModelID are the Bedrock model IDs TitanV1Provider and
+// TitanV2Provider invoke.
+const (
+	titanV1ModelID = "amazon.titan-embed-text-v1"
+	titanV2ModelID = "amazon.titan-embed-text-v2:0"
+
+	// titanV1Dimension is TitanV1Provider's fixed output dimension.
+	titanV1Dimension = 1536
+	// defaultTitanV2Dimension is TitanV2Provider's output dimension when
+	// OutputDimension isn't set to one of Titan v2's other supported sizes (256,
+	// 512).
+	defaultTitanV2Dimension = 1024
+)
+
+// titanEmbeddingRequest is the InvokeModel request body Titan v1 and v2 both
+// accept; v1 ignores Dimensions/Normalize (always 1536-dim, unnormalized).
+type titanEmbeddingRequest struct {
+	InputText  string `json:"inputText"`
+	Dimensions int    `json:"dimensions,omitempty"`
+	Normalize  bool   `json:"normalize,omitempty"`
+}
+
+// titanEmbeddingResponse is the InvokeModel response body Titan v1 and v2 both
+// return.
+type titanEmbeddingResponse struct {
+	Embedding []float32 `json:"embedding"`
+}
+
+// TitanV1Provider embeds via amazon.titan-embed-text-v1, Bedrock's original Titan
+// embedding model: a fixed 1536-dim, unnormalized vector. Titan's InvokeModel body
+// only accepts one inputText at a time, so Embed issues one call per text rather
+// than packing several into a single request.
+type TitanV1Provider struct {
+	client *bedrockruntime.BedrockRuntime
+	logger *utils.Logger
+}
+
+// NewTitanV1Provider creates a TitanV1Provider invoking Bedrock through client.
+func NewTitanV1Provider(client *bedrockruntime.BedrockRuntime, logger *utils.Logger) *TitanV1Provider {
+	return &TitanV1Provider{client: client, logger: logger}
+}
+
+// Name identifies this provider in chunk Metadata; see DescribedEmbeddingProvider.
+func (p *TitanV1Provider) Name() string { return "titan-v1" }
+
+// Dimension is TitanV1Provider's fixed output dimension.
+func (p *TitanV1Provider) Dimension() int { return titanV1Dimension }
+
+// Embed generates a Titan v1 embedding for each of texts, in order.
+func (p *TitanV1Provider) Embed(ctx context.Context, texts []string) ([][]float32, TokenUsage, error) {
+	return invokeTitan(ctx, p.client, p.logger, titanV1ModelID, texts, 0, false)
+}
+
+// TitanV2Provider embeds via amazon.titan-embed-text-v2:0, which (unlike v1) can
+// be asked for a 256, 512, or 1024-dim output and, via Normalize, unit-normalized
+// vectors (which cosine-similarity search doesn't strictly require, but some
+// downstream consumers assume).
+type TitanV2Provider struct {
+	client *bedrockruntime.BedrockRuntime
+	logger *utils.Logger
+
+	// OutputDimension is one of 256, 512, or 1024 (Titan v2's supported sizes).
+	// Zero defaults to defaultTitanV2Dimension.
+	OutputDimension int
+	// Normalize requests unit-normalized embeddings from Titan v2.
+	Normalize bool
+}
+
+// NewTitanV2Provider creates a TitanV2Provider invoking Bedrock through client,
+// at defaultTitanV2Dimension with normalization off; set OutputDimension/
+// Normalize afterwards to override.
+func NewTitanV2Provider(client *bedrockruntime.BedrockRuntime, logger *utils.Logger) *TitanV2Provider {
+	return &TitanV2Provider{client: client, logger: logger, OutputDimension: defaultTitanV2Dimension}
+}
+
+// Name identifies this provider in chunk Metadata; see DescribedEmbeddingProvider.
+func (p *TitanV2Provider) Name() string { return "titan-v2" }
+
+// Dimension is p.OutputDimension, defaulting to defaultTitanV2Dimension.
+func (p *TitanV2Provider) Dimension() int {
+	if p.OutputDimension <= 0 {
+		return defaultTitanV2Dimension
+	}
+	return p.OutputDimension
+}
+
+// Embed generates a Titan v2 embedding for each of texts, in order, at
+// p.Dimension() and p.Normalize.
+func (p *TitanV2Provider) Embed(ctx context.Context, texts []string) ([][]float32, TokenUsage, error) {
+	return invokeTitan(ctx, p.client, p.logger, titanV2ModelID, texts, p.Dimension(), p.Normalize)
+}
+
+// invokeTitan is the shared Embed implementation behind TitanV1Provider and
+// TitanV2Provider: one InvokeModel call per text (batchTexts with maxPerCall=1
+// yields one text per batch, since Titan's body only holds a single inputText; a
+// text over maxInputTokensPerCall is still sent whole in its own call).
+// Titan's InvokeModel response doesn't report token usage, so
+// TokenUsage.EmbeddingTokens is len(texts) (one per text), not a real token count.
+func invokeTitan(ctx context.Context, client *bedrockruntime.BedrockRuntime, logger *utils.Logger, modelID string, texts []string, dimensions int, normalize bool) ([][]float32, TokenUsage, error) {
+	if len(texts) == 0 {
+		return nil, TokenUsage{}, fmt.Errorf("no texts provided")
+	}
+
+	embeddings := make([][]float32, 0, len(texts))
+	for _, batch := range batchTexts(texts, 1) {
+		text := batch[0]
+
+		requestBody, err := json.Marshal(titanEmbeddingRequest{
+			InputText:  text,
+			Dimensions: dimensions,
+			Normalize:  normalize,
+		})
+		if err != nil {
+			return nil, TokenUsage{}, fmt.Errorf("failed to marshal embedding request: %w", err)
+		}
+
+		result, err := client.InvokeModelWithContext(ctx, &bedrockruntime.InvokeModelInput{
+			ModelId:     aws.String(modelID),
+			ContentType: aws.String("application/json"),
+			Accept:      aws.String("application/json"),
+			Body:        requestBody,
+		})
+		if err != nil {
+			logger.ErrorWithErr("Failed to invoke Titan embedding model", err)
+			return nil, TokenUsage{}, fmt.Errorf("failed to invoke embedding model %s: %w", modelID, err)
+		}
+
+		var response titanEmbeddingResponse
+		if err := json.Unmarshal(result.Body, &response); err != nil {
+			return nil, TokenUsage{}, fmt.Errorf("failed to unmarshal embedding response: %w", err)
+		}
+
+		embeddings = append(embeddings, response.Embedding)
+	}
+
+	return embeddings, TokenUsage{EmbeddingTokens: len(texts)}, nil
+}
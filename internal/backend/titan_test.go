@@ -0,0 +1,74 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBatchTexts_RespectsMaxPerCall(t *testing.T) {
+	texts := []string{"a", "b", "c", "d", "e"}
+
+	batches := batchTexts(texts, 2)
+
+	if len(batches) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(batches))
+	}
+	for _, b := range batches {
+		if len(b) > 2 {
+			t.Errorf("expected at most 2 texts per batch, got %d", len(b))
+		}
+	}
+}
+
+func TestBatchTexts_RespectsTokenBudget(t *testing.T) {
+	long := strings.Repeat("word ", 10000) // comfortably over maxInputTokensPerCall alone
+	texts := []string{long, "short"}
+
+	batches := batchTexts(texts, 96)
+
+	if len(batches) != 2 {
+		t.Fatalf("expected the oversized text to be split into its own batch, got %d batches", len(batches))
+	}
+}
+
+func TestBatchTexts_MaxPerCallOne(t *testing.T) {
+	texts := []string{"a", "b", "c"}
+
+	batches := batchTexts(texts, 1)
+
+	if len(batches) != 3 {
+		t.Fatalf("expected one text per batch, got %d batches", len(batches))
+	}
+}
+
+func TestTitanV1Provider_NameAndDimension(t *testing.T) {
+	p := NewTitanV1Provider(nil, nil)
+	if p.Name() != "titan-v1" {
+		t.Errorf("unexpected name: %s", p.Name())
+	}
+	if p.Dimension() != titanV1Dimension {
+		t.Errorf("expected dimension %d, got %d", titanV1Dimension, p.Dimension())
+	}
+}
+
+func TestTitanV2Provider_DimensionDefaultsAndOverrides(t *testing.T) {
+	p := NewTitanV2Provider(nil, nil)
+	if p.Dimension() != defaultTitanV2Dimension {
+		t.Errorf("expected default dimension %d, got %d", defaultTitanV2Dimension, p.Dimension())
+	}
+
+	p.OutputDimension = 256
+	if p.Dimension() != 256 {
+		t.Errorf("expected overridden dimension 256, got %d", p.Dimension())
+	}
+}
+
+func TestCohereEnglishV3Provider_NameAndDimension(t *testing.T) {
+	p := NewCohereEnglishV3Provider(nil, nil)
+	if p.Name() != "cohere-english-v3" {
+		t.Errorf("unexpected name: %s", p.Name())
+	}
+	if p.Dimension() != cohereEnglishV3Dimension {
+		t.Errorf("expected dimension %d, got %d", cohereEnglishV3Dimension, p.Dimension())
+	}
+}
@@ -0,0 +1,34 @@
+// Package embedcache caches Bedrock embeddings by a content hash (sha256 of the
+// model ID and normalized text), so re-ingesting near-duplicate documents skips
+// Bedrock entirely. EmbeddingCache has two implementations: LRUCache (in-memory,
+// process-local) and DynamoCache (shared across Lambda invocations).
+package embedcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// EmbeddingCache looks up and stores embeddings by content hash (see Hash).
+// GetBatch/PutBatch let a caller batch many lookups/writes into as few round-trips
+// as the underlying store allows, rather than calling Get/Put once per text.
+type EmbeddingCache interface {
+	Get(hash string) ([]float32, bool, error)
+	Put(hash, modelID string, embedding []float32) error
+	GetBatch(hashes []string) (map[string][]float32, error)
+	PutBatch(modelID string, entries map[string][]float32) error
+}
+
+var whitespacePattern = regexp.MustCompile(`\s+`)
+
+// Hash returns the cache key for modelID and text: the hex-encoded sha256 of
+// modelID and the whitespace-normalized text. Normalizing means near-identical
+// re-ingests of the same content (different trailing spaces, re-wrapped lines)
+// still hit the cache.
+func Hash(modelID, text string) string {
+	normalized := strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " "))
+	sum := sha256.Sum256([]byte(modelID + "|" + normalized))
+	return hex.EncodeToString(sum[:])
+}
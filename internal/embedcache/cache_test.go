@@ -0,0 +1,116 @@
+package embedcache
+
+import "testing"
+
+func TestHash_StableAcrossEquivalentWhitespace(t *testing.T) {
+	a := Hash("titan-v1", "hello   world")
+	b := Hash("titan-v1", "  hello world  ")
+
+	if a != b {
+		t.Fatalf("expected equivalent text to hash the same, got %q vs %q", a, b)
+	}
+}
+
+func TestHash_VariesOnModelOrText(t *testing.T) {
+	base := Hash("titan-v1", "hello world")
+
+	if Hash("titan-v2", "hello world") == base {
+		t.Fatal("expected different model IDs to hash differently")
+	}
+	if Hash("titan-v1", "goodbye world") == base {
+		t.Fatal("expected different text to hash differently")
+	}
+}
+
+func TestLRUCache_GetPut(t *testing.T) {
+	cache := NewLRUCache(10)
+
+	if _, hit, _ := cache.Get("missing"); hit {
+		t.Fatal("expected a miss for an unseen hash")
+	}
+
+	embedding := []float32{0.1, 0.2, 0.3}
+	if err := cache.Put("hash-1", "titan-v1", embedding); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	got, hit, err := cache.Get("hash-1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !hit {
+		t.Fatal("expected a hit after Put")
+	}
+	if len(got) != len(embedding) || got[0] != embedding[0] {
+		t.Errorf("got %v, want %v", got, embedding)
+	}
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewLRUCache(2)
+
+	_ = cache.Put("a", "model", []float32{1})
+	_ = cache.Put("b", "model", []float32{2})
+	// Touch "a" so "b" becomes the least recently used entry.
+	_, _, _ = cache.Get("a")
+	_ = cache.Put("c", "model", []float32{3})
+
+	if _, hit, _ := cache.Get("b"); hit {
+		t.Error("expected \"b\" to have been evicted")
+	}
+	if _, hit, _ := cache.Get("a"); !hit {
+		t.Error("expected \"a\" to survive since it was touched")
+	}
+	if _, hit, _ := cache.Get("c"); !hit {
+		t.Error("expected \"c\" to be present as the most recent entry")
+	}
+}
+
+func TestLRUCache_BatchGetPut(t *testing.T) {
+	cache := NewLRUCache(10)
+
+	entries := map[string][]float32{
+		"a": {1, 2},
+		"b": {3, 4},
+	}
+	if err := cache.PutBatch("model", entries); err != nil {
+		t.Fatalf("PutBatch failed: %v", err)
+	}
+
+	hits, err := cache.GetBatch([]string{"a", "b", "missing"})
+	if err != nil {
+		t.Fatalf("GetBatch failed: %v", err)
+	}
+	if len(hits) != 2 {
+		t.Fatalf("expected 2 hits, got %d", len(hits))
+	}
+}
+
+func TestEncodeDecodeEmbedding_RoundTrips(t *testing.T) {
+	embedding := []float32{0.5, -1.25, 3.0, 0}
+
+	encoded, err := encodeEmbedding(embedding)
+	if err != nil {
+		t.Fatalf("encodeEmbedding failed: %v", err)
+	}
+
+	decoded, err := decodeEmbedding(encoded)
+	if err != nil {
+		t.Fatalf("decodeEmbedding failed: %v", err)
+	}
+
+	if len(decoded) != len(embedding) {
+		t.Fatalf("expected %d values, got %d", len(embedding), len(decoded))
+	}
+	for i, v := range embedding {
+		if decoded[i] != v {
+			t.Errorf("index %d: got %v, want %v", i, decoded[i], v)
+		}
+	}
+}
+
+func TestDecodeEmbedding_RejectsInvalidLength(t *testing.T) {
+	if _, err := decodeEmbedding([]byte{1, 2, 3}); err == nil {
+		t.Fatal("expected an error for a byte length that isn't a multiple of 4")
+	}
+}
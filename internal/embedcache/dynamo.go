@@ -0,0 +1,222 @@
+package embedcache
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// DefaultTTL is how long DynamoCache entries live before DynamoDB TTL expires them,
+// used when NewDynamoCache isn't given a more specific value via WithTTL.
+const DefaultTTL = 30 * 24 * time.Hour
+
+// dynamoBatchLimit is DynamoDB's hard cap on items per BatchGetItem/BatchWriteItem
+// call.
+const dynamoBatchLimit = 100
+
+// DynamoCache is a DynamoDB-backed EmbeddingCache shared across Lambda
+// invocations. The table needs a string partition key "hash" and a DynamoDB TTL
+// configured on "ttl".
+type DynamoCache struct {
+	client    *dynamodb.DynamoDB
+	tableName string
+	ttl       time.Duration
+}
+
+// NewDynamoCache creates a DynamoCache backed by tableName, using DefaultTTL.
+func NewDynamoCache(client *dynamodb.DynamoDB, tableName string) *DynamoCache {
+	return &DynamoCache{client: client, tableName: tableName, ttl: DefaultTTL}
+}
+
+// WithTTL overrides the default cache entry lifetime.
+func (c *DynamoCache) WithTTL(ttl time.Duration) *DynamoCache {
+	c.ttl = ttl
+	return c
+}
+
+// Get returns the cached embedding for hash, if present and unexpired.
+func (c *DynamoCache) Get(hash string) ([]float32, bool, error) {
+	out, err := c.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"hash": {S: aws.String(hash)},
+		},
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read embedding cache entry: %w", err)
+	}
+	if out.Item == nil {
+		return nil, false, nil
+	}
+	return decodeItem(out.Item)
+}
+
+// Put stores embedding under hash, recording modelID and the entry's TTL.
+func (c *DynamoCache) Put(hash, modelID string, embedding []float32) error {
+	item, err := c.toItem(hash, modelID, embedding)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(c.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write embedding cache entry: %w", err)
+	}
+	return nil
+}
+
+// GetBatch returns whichever of hashes are cached and unexpired, batching lookups
+// into BatchGetItem calls of at most dynamoBatchLimit keys each to keep round-trips
+// low for a large document's worth of chunks.
+func (c *DynamoCache) GetBatch(hashes []string) (map[string][]float32, error) {
+	hits := make(map[string][]float32)
+
+	for start := 0; start < len(hashes); start += dynamoBatchLimit {
+		end := start + dynamoBatchLimit
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+
+		keys := make([]map[string]*dynamodb.AttributeValue, 0, end-start)
+		for _, hash := range hashes[start:end] {
+			keys = append(keys, map[string]*dynamodb.AttributeValue{"hash": {S: aws.String(hash)}})
+		}
+
+		out, err := c.client.BatchGetItem(&dynamodb.BatchGetItemInput{
+			RequestItems: map[string]*dynamodb.KeysAndAttributes{
+				c.tableName: {Keys: keys},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to batch-read embedding cache entries: %w", err)
+		}
+
+		for _, item := range out.Responses[c.tableName] {
+			embedding, ok, err := decodeItem(item)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			hashAttr, ok := item["hash"]
+			if !ok || hashAttr.S == nil {
+				continue
+			}
+			hits[*hashAttr.S] = embedding
+		}
+	}
+
+	return hits, nil
+}
+
+// PutBatch stores every entry, batching writes into BatchWriteItem calls of at most
+// dynamoBatchLimit items each.
+func (c *DynamoCache) PutBatch(modelID string, entries map[string][]float32) error {
+	hashes := make([]string, 0, len(entries))
+	for hash := range entries {
+		hashes = append(hashes, hash)
+	}
+
+	for start := 0; start < len(hashes); start += dynamoBatchLimit {
+		end := start + dynamoBatchLimit
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+
+		requests := make([]*dynamodb.WriteRequest, 0, end-start)
+		for _, hash := range hashes[start:end] {
+			item, err := c.toItem(hash, modelID, entries[hash])
+			if err != nil {
+				return err
+			}
+			requests = append(requests, &dynamodb.WriteRequest{
+				PutRequest: &dynamodb.PutRequest{Item: item},
+			})
+		}
+
+		_, err := c.client.BatchWriteItem(&dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]*dynamodb.WriteRequest{
+				c.tableName: requests,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to batch-write embedding cache entries: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *DynamoCache) toItem(hash, modelID string, embedding []float32) (map[string]*dynamodb.AttributeValue, error) {
+	encoded, err := encodeEmbedding(embedding)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return map[string]*dynamodb.AttributeValue{
+		"hash":       {S: aws.String(hash)},
+		"model_id":   {S: aws.String(modelID)},
+		"embedding":  {B: encoded},
+		"created_at": {N: aws.String(strconv.FormatInt(now.Unix(), 10))},
+		"ttl":        {N: aws.String(strconv.FormatInt(now.Add(c.ttl).Unix(), 10))},
+	}, nil
+}
+
+func decodeItem(item map[string]*dynamodb.AttributeValue) ([]float32, bool, error) {
+	ttlAttr, ok := item["ttl"]
+	if ok && ttlAttr.N != nil {
+		ttl, err := strconv.ParseInt(*ttlAttr.N, 10, 64)
+		// DynamoDB's TTL sweeper can lag behind the expiry time by hours, so also
+		// treat an expired-but-not-yet-deleted item as a miss.
+		if err == nil && time.Now().Unix() >= ttl {
+			return nil, false, nil
+		}
+	}
+
+	embeddingAttr, ok := item["embedding"]
+	if !ok || embeddingAttr.B == nil {
+		return nil, false, nil
+	}
+
+	embedding, err := decodeEmbedding(embeddingAttr.B)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decode cached embedding: %w", err)
+	}
+	return embedding, true, nil
+}
+
+// encodeEmbedding packs embedding as little-endian float32s for DynamoDB's binary
+// attribute type, far more compact than JSON-encoding the slice.
+func encodeEmbedding(embedding []float32) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.Grow(len(embedding) * 4)
+	for _, v := range embedding {
+		if err := binary.Write(buf, binary.LittleEndian, v); err != nil {
+			return nil, fmt.Errorf("failed to encode embedding: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeEmbedding(data []byte) ([]float32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("invalid embedding byte length %d", len(data))
+	}
+
+	embedding := make([]float32, len(data)/4)
+	reader := bytes.NewReader(data)
+	if err := binary.Read(reader, binary.LittleEndian, &embedding); err != nil {
+		return nil, fmt.Errorf("failed to decode embedding: %w", err)
+	}
+	return embedding, nil
+}
@@ -0,0 +1,108 @@
+package embedcache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultLRUCapacity bounds LRUCache's size when NewLRUCache is given capacity <= 0.
+const DefaultLRUCapacity = 10000
+
+type lruEntry struct {
+	hash      string
+	embedding []float32
+}
+
+// LRUCache is an in-memory, process-local EmbeddingCache bounded to capacity
+// entries, evicting the least recently used entry once full. Safe for concurrent
+// use.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+// NewLRUCache creates an LRUCache holding at most capacity entries
+// (DefaultLRUCapacity if capacity <= 0).
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		capacity = DefaultLRUCapacity
+	}
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached embedding for hash, if present, moving it to the front of
+// the eviction order.
+func (c *LRUCache) Get(hash string) ([]float32, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[hash]
+	if !ok {
+		return nil, false, nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).embedding, true, nil
+}
+
+// Put stores embedding under hash, evicting the least recently used entry if the
+// cache is at capacity. modelID is accepted for interface parity with DynamoCache
+// but isn't needed to serve in-process lookups.
+func (c *LRUCache) Put(hash, modelID string, embedding []float32) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.putLocked(hash, embedding)
+	return nil
+}
+
+// GetBatch returns whichever of hashes are cached, omitting misses rather than
+// erroring, since a batch embedding call falls back to Bedrock for misses anyway.
+func (c *LRUCache) GetBatch(hashes []string) (map[string][]float32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hits := make(map[string][]float32)
+	for _, hash := range hashes {
+		if elem, ok := c.items[hash]; ok {
+			c.order.MoveToFront(elem)
+			hits[hash] = elem.Value.(*lruEntry).embedding
+		}
+	}
+	return hits, nil
+}
+
+// PutBatch stores every entry, keyed by hash. modelID is accepted for interface
+// parity with DynamoCache.
+func (c *LRUCache) PutBatch(modelID string, entries map[string][]float32) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for hash, embedding := range entries {
+		c.putLocked(hash, embedding)
+	}
+	return nil
+}
+
+func (c *LRUCache) putLocked(hash string, embedding []float32) {
+	if elem, ok := c.items[hash]; ok {
+		elem.Value.(*lruEntry).embedding = embedding
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&lruEntry{hash: hash, embedding: embedding})
+	c.items[hash] = elem
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).hash)
+		}
+	}
+}
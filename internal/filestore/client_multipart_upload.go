@@ -0,0 +1,311 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage"
+)
+
+const (
+	// clientMultipartMinPartSize is the minimum size S3 accepts for every part but
+	// the last in a multipart upload.
+	clientMultipartMinPartSize = 5 * 1024 * 1024
+
+	// clientMultipartMaxParts is S3's own limit on parts per multipart upload.
+	clientMultipartMaxParts = 10000
+
+	// clientMultipartMarkerPrefix is where in-progress upload sessions are marked,
+	// so they're easy to tell apart from the documents they'll become.
+	clientMultipartMarkerPrefix = "uploads/sessions/"
+)
+
+// ErrUploadNotFound is returned when an UploadID has no session marker, either
+// because it never existed or because it was already completed or aborted.
+var ErrUploadNotFound = errors.New("multipart upload not found")
+
+// PartInput is one part's metadata as reported by the client when completing a
+// client-driven multipart upload.
+type PartInput struct {
+	PartNumber int64  `json:"part_number"`
+	ETag       string `json:"etag"`
+}
+
+// partRecord is one part already accepted into the underlying S3 multipart
+// upload, as tracked server-side in the session marker.
+type partRecord struct {
+	PartNumber int64  `json:"part_number"`
+	ETag       string `json:"etag"`
+	Size       int64  `json:"size"`
+}
+
+// MultipartSession tracks one in-progress client-driven multipart upload,
+// persisted as a JSON marker object keyed by UploadID so it survives Lambda
+// cold starts and parts arriving out of order.
+type MultipartSession struct {
+	UploadID    string       `json:"upload_id"`
+	Key         string       `json:"key"`
+	S3UploadID  string       `json:"s3_upload_id"`
+	DocumentID  string       `json:"document_id"`
+	FileName    string       `json:"file_name"`
+	ContentType string       `json:"content_type"`
+	Parts       []partRecord `json:"parts"`
+	CreatedAt   time.Time    `json:"created_at"`
+	Completed   bool         `json:"completed"`
+}
+
+// ClientMultipartUploader drives an S3-style multipart upload protocol where
+// the client, not the Lambda, sends each part: CreateUpload starts it,
+// UploadPart accepts one part (in any order), and CompleteUpload or
+// AbortUpload finishes it. This is distinct from MultipartUploader, which
+// splits and uploads an already-received whole body itself.
+type ClientMultipartUploader struct {
+	client     storage.S3APIClient
+	bucketName string
+}
+
+// NewClientMultipartUploader creates an uploader targeting the given bucket.
+func NewClientMultipartUploader(client storage.S3APIClient, bucketName string) *ClientMultipartUploader {
+	return &ClientMultipartUploader{client: client, bucketName: bucketName}
+}
+
+// CreateUpload starts an S3 multipart upload for key and persists a new
+// session marker for it, keyed by a freshly generated UploadID and recording
+// documentID.
+func (u *ClientMultipartUploader) CreateUpload(ctx context.Context, documentID, key, fileName, contentType string) (*MultipartSession, error) {
+	out, err := u.client.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(u.bucketName),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multipart upload for %s: %w", key, err)
+	}
+
+	uploadID, err := newUploadID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate upload id: %w", err)
+	}
+
+	session := &MultipartSession{
+		UploadID:    uploadID,
+		Key:         key,
+		S3UploadID:  aws.StringValue(out.UploadId),
+		DocumentID:  documentID,
+		FileName:    fileName,
+		ContentType: contentType,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := u.putSession(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// UploadPart uploads data as partNumber of uploadID's multipart upload and
+// records it in the session marker, returning the part's ETag. Parts may
+// arrive out of order or be retried; a retried part simply overwrites its
+// previous record.
+func (u *ClientMultipartUploader) UploadPart(ctx context.Context, uploadID string, partNumber int64, data []byte) (string, error) {
+	if partNumber < 1 || partNumber > clientMultipartMaxParts {
+		return "", fmt.Errorf("part number must be between 1 and %d", clientMultipartMaxParts)
+	}
+
+	session, err := u.getSession(uploadID)
+	if err != nil {
+		return "", err
+	}
+	if session.Completed {
+		return "", fmt.Errorf("upload %s is already completed", uploadID)
+	}
+
+	out, err := u.client.UploadPart(&s3.UploadPartInput{
+		Bucket:     aws.String(u.bucketName),
+		Key:        aws.String(session.Key),
+		UploadId:   aws.String(session.S3UploadID),
+		PartNumber: aws.Int64(partNumber),
+		Body:       aws.ReadSeekCloser(bytes.NewReader(data)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+
+	etag := aws.StringValue(out.ETag)
+	session.Parts = setPart(session.Parts, partRecord{PartNumber: partNumber, ETag: etag, Size: int64(len(data))})
+
+	if err := u.putSession(session); err != nil {
+		return "", err
+	}
+	return etag, nil
+}
+
+// CompleteUpload validates parts against the sizes recorded by UploadPart,
+// completes the S3 multipart upload, and marks the session finished.
+func (u *ClientMultipartUploader) CompleteUpload(ctx context.Context, uploadID string, parts []PartInput) (*MultipartSession, error) {
+	session, err := u.getSession(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Completed {
+		return session, nil
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("at least one part is required to complete upload %s", uploadID)
+	}
+
+	sorted := make([]PartInput, len(parts))
+	copy(sorted, parts)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PartNumber < sorted[j].PartNumber })
+
+	completed := make([]*s3.CompletedPart, len(sorted))
+	for i, part := range sorted {
+		recorded := findPart(session.Parts, part.PartNumber)
+		if recorded == nil {
+			return nil, fmt.Errorf("part %d was never uploaded", part.PartNumber)
+		}
+		if i < len(sorted)-1 && recorded.Size < clientMultipartMinPartSize {
+			return nil, fmt.Errorf("part %d is %d bytes, below the %d byte minimum required for all but the last part", part.PartNumber, recorded.Size, clientMultipartMinPartSize)
+		}
+
+		completed[i] = &s3.CompletedPart{
+			PartNumber: aws.Int64(part.PartNumber),
+			ETag:       aws.String(part.ETag),
+		}
+	}
+
+	_, err = u.client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(u.bucketName),
+		Key:             aws.String(session.Key),
+		UploadId:        aws.String(session.S3UploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete multipart upload %s: %w", uploadID, err)
+	}
+
+	session.Completed = true
+	if err := u.putSession(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// AbortUpload cancels uploadID's S3 multipart upload and removes its session
+// marker.
+func (u *ClientMultipartUploader) AbortUpload(ctx context.Context, uploadID string) error {
+	session, err := u.getSession(uploadID)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(u.bucketName),
+		Key:      aws.String(session.Key),
+		UploadId: aws.String(session.S3UploadID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to abort multipart upload %s: %w", uploadID, err)
+	}
+
+	return u.deleteSession(uploadID)
+}
+
+func (u *ClientMultipartUploader) markerKey(uploadID string) string {
+	return clientMultipartMarkerPrefix + uploadID + ".json"
+}
+
+func (u *ClientMultipartUploader) putSession(session *MultipartSession) error {
+	body, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal upload session: %w", err)
+	}
+
+	_, err = u.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(u.bucketName),
+		Key:    aws.String(u.markerKey(session.UploadID)),
+		Body:   bytes.NewReader(body),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store upload session marker: %w", err)
+	}
+	return nil
+}
+
+func (u *ClientMultipartUploader) getSession(uploadID string) (*MultipartSession, error) {
+	out, err := u.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(u.bucketName),
+		Key:    aws.String(u.markerKey(uploadID)),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && (awsErr.Code() == s3.ErrCodeNoSuchKey || awsErr.Code() == "NotFound") {
+			return nil, ErrUploadNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch upload session marker: %w", err)
+	}
+	defer out.Body.Close()
+
+	body, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload session marker: %w", err)
+	}
+
+	var session MultipartSession
+	if err := json.Unmarshal(body, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal upload session marker: %w", err)
+	}
+	return &session, nil
+}
+
+func (u *ClientMultipartUploader) deleteSession(uploadID string) error {
+	_, err := u.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(u.bucketName),
+		Key:    aws.String(u.markerKey(uploadID)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete upload session marker: %w", err)
+	}
+	return nil
+}
+
+// setPart adds part to parts, replacing any existing record for the same
+// PartNumber so a retried UploadPart call doesn't create a duplicate.
+func setPart(parts []partRecord, part partRecord) []partRecord {
+	for i, existing := range parts {
+		if existing.PartNumber == part.PartNumber {
+			parts[i] = part
+			return parts
+		}
+	}
+	return append(parts, part)
+}
+
+func findPart(parts []partRecord, partNumber int64) *partRecord {
+	for i := range parts {
+		if parts[i].PartNumber == partNumber {
+			return &parts[i]
+		}
+	}
+	return nil
+}
+
+func newUploadID() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
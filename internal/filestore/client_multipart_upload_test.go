@@ -0,0 +1,159 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage/mocks"
+)
+
+// newMarkerBackedClient returns a mock S3Client that stores whatever
+// ClientMultipartUploader PutObjects as session markers in an in-memory map,
+// so GetObject/DeleteObject can round-trip them like a real bucket would.
+func newMarkerBackedClient() *mocks.S3Client {
+	markers := map[string][]byte{}
+	client := &mocks.S3Client{
+		CreateMultipartUploadFunc: func(input *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+			return &s3.CreateMultipartUploadOutput{UploadId: aws.String("s3-upload-1")}, nil
+		},
+		UploadPartFunc: func(input *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+			return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag-%d", aws.Int64Value(input.PartNumber)))}, nil
+		},
+	}
+	client.PutObjectFunc = func(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+		body, err := io.ReadAll(input.Body)
+		if err != nil {
+			return nil, err
+		}
+		markers[aws.StringValue(input.Key)] = body
+		return &s3.PutObjectOutput{}, nil
+	}
+	client.GetObjectFunc = func(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+		body, ok := markers[aws.StringValue(input.Key)]
+		if !ok {
+			return nil, awserr.New(s3.ErrCodeNoSuchKey, "no such key", nil)
+		}
+		return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(body))}, nil
+	}
+	client.DeleteObjectFunc = func(input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+		delete(markers, aws.StringValue(input.Key))
+		return &s3.DeleteObjectOutput{}, nil
+	}
+	return client
+}
+
+func TestClientMultipartUploader_CreateUploadPartComplete(t *testing.T) {
+	client := newMarkerBackedClient()
+	client.UploadPartFunc = func(input *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+		return &s3.UploadPartOutput{ETag: aws.String("etag-1")}, nil
+	}
+	uploader := NewClientMultipartUploader(client, "test-bucket")
+
+	session, err := uploader.CreateUpload(context.Background(), "doc-1", "documents/prod/large.pdf", "large.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("CreateUpload failed: %v", err)
+	}
+	if session.UploadID == "" {
+		t.Fatal("expected a non-empty UploadID")
+	}
+
+	part := bytes.Repeat([]byte("x"), clientMultipartMinPartSize)
+	etag, err := uploader.UploadPart(context.Background(), session.UploadID, 1, part)
+	if err != nil {
+		t.Fatalf("UploadPart failed: %v", err)
+	}
+	if etag != "etag-1" {
+		t.Errorf("got etag %q, want etag-1", etag)
+	}
+
+	completed, err := uploader.CompleteUpload(context.Background(), session.UploadID, []PartInput{{PartNumber: 1, ETag: etag}})
+	if err != nil {
+		t.Fatalf("CompleteUpload failed: %v", err)
+	}
+	if completed.DocumentID != "doc-1" {
+		t.Errorf("got DocumentID %q, want doc-1", completed.DocumentID)
+	}
+	if len(client.CompleteMultipartUploadCalls) != 1 {
+		t.Fatalf("expected 1 CompleteMultipartUpload call, got %d", len(client.CompleteMultipartUploadCalls))
+	}
+}
+
+func TestClientMultipartUploader_UploadPart_RejectsOutOfRangePartNumber(t *testing.T) {
+	client := newMarkerBackedClient()
+	uploader := NewClientMultipartUploader(client, "test-bucket")
+
+	session, err := uploader.CreateUpload(context.Background(), "doc-1", "documents/prod/large.pdf", "large.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("CreateUpload failed: %v", err)
+	}
+
+	if _, err := uploader.UploadPart(context.Background(), session.UploadID, 0, []byte("x")); err == nil {
+		t.Fatal("expected an error for part number 0")
+	}
+	if _, err := uploader.UploadPart(context.Background(), session.UploadID, clientMultipartMaxParts+1, []byte("x")); err == nil {
+		t.Fatal("expected an error for a part number past the max")
+	}
+}
+
+func TestClientMultipartUploader_CompleteUpload_RejectsUndersizedNonFinalPart(t *testing.T) {
+	client := newMarkerBackedClient()
+	uploader := NewClientMultipartUploader(client, "test-bucket")
+
+	session, err := uploader.CreateUpload(context.Background(), "doc-1", "documents/prod/large.pdf", "large.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("CreateUpload failed: %v", err)
+	}
+
+	if _, err := uploader.UploadPart(context.Background(), session.UploadID, 1, []byte("too small")); err != nil {
+		t.Fatalf("UploadPart failed: %v", err)
+	}
+	if _, err := uploader.UploadPart(context.Background(), session.UploadID, 2, []byte("also small")); err != nil {
+		t.Fatalf("UploadPart failed: %v", err)
+	}
+
+	_, err = uploader.CompleteUpload(context.Background(), session.UploadID, []PartInput{
+		{PartNumber: 1, ETag: "etag-1"},
+		{PartNumber: 2, ETag: "etag-2"},
+	})
+	if err == nil {
+		t.Fatal("expected an error completing an upload with an undersized non-final part")
+	}
+}
+
+func TestClientMultipartUploader_UploadPart_UnknownUploadID(t *testing.T) {
+	client := newMarkerBackedClient()
+	uploader := NewClientMultipartUploader(client, "test-bucket")
+
+	if _, err := uploader.UploadPart(context.Background(), "missing", 1, []byte("x")); !errors.Is(err, ErrUploadNotFound) {
+		t.Fatalf("got error %v, want ErrUploadNotFound", err)
+	}
+}
+
+func TestClientMultipartUploader_AbortUpload(t *testing.T) {
+	client := newMarkerBackedClient()
+	uploader := NewClientMultipartUploader(client, "test-bucket")
+
+	session, err := uploader.CreateUpload(context.Background(), "doc-1", "documents/prod/large.pdf", "large.pdf", "application/pdf")
+	if err != nil {
+		t.Fatalf("CreateUpload failed: %v", err)
+	}
+
+	if err := uploader.AbortUpload(context.Background(), session.UploadID); err != nil {
+		t.Fatalf("AbortUpload failed: %v", err)
+	}
+	if len(client.AbortMultipartUploadCalls) != 1 {
+		t.Fatalf("expected 1 AbortMultipartUpload call, got %d", len(client.AbortMultipartUploadCalls))
+	}
+
+	if _, err := uploader.UploadPart(context.Background(), session.UploadID, 1, []byte("x")); !errors.Is(err, ErrUploadNotFound) {
+		t.Fatalf("got error %v, want ErrUploadNotFound after abort", err)
+	}
+}
@@ -0,0 +1,27 @@
+package filestore
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// NewFromEnv selects a Store implementation based on the STORAGE_BACKEND environment
+// variable: "fs" roots an FSStore at STORAGE_FS_ROOT (default "./data"), "s3" or
+// unset uses S3Store against bucketName, and any other value is an error.
+func NewFromEnv(sess *session.Session, bucketName string) (Store, error) {
+	switch os.Getenv("STORAGE_BACKEND") {
+	case "fs":
+		root := os.Getenv("STORAGE_FS_ROOT")
+		if root == "" {
+			root = "./data"
+		}
+		return NewFSStore(root)
+	case "", "s3":
+		return NewS3Store(s3.New(sess), bucketName), nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q: want \"fs\" or \"s3\"", os.Getenv("STORAGE_BACKEND"))
+	}
+}
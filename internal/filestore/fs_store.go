@@ -0,0 +1,135 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// metaSuffix is appended to an object's file name to store its sidecar metadata.
+const metaSuffix = ".meta.json"
+
+// FSStore is a Store rooted at a local directory, for development and tests that
+// shouldn't require real AWS credentials. Keys may contain "/"; they are mapped
+// directly onto subdirectories of root.
+type FSStore struct {
+	root string
+}
+
+// NewFSStore creates a Store rooted at dir, creating it if it does not exist.
+func NewFSStore(dir string) (*FSStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage root %s: %w", dir, err)
+	}
+	return &FSStore{root: dir}, nil
+}
+
+func (f *FSStore) objectPath(key string) string {
+	return filepath.Join(f.root, filepath.FromSlash(key))
+}
+
+func (f *FSStore) Put(ctx context.Context, key string, r io.Reader, metadata map[string]string) error {
+	path := f.objectPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create object %s: %w", key, err)
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write object %s: %w", key, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to close object %s: %w", key, err)
+	}
+
+	metaJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata for %s: %w", key, err)
+	}
+	if err := os.WriteFile(path+metaSuffix, metaJSON, 0o644); err != nil {
+		return fmt.Errorf("failed to write metadata for %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (f *FSStore) Get(ctx context.Context, key string) (io.ReadCloser, map[string]string, error) {
+	path := f.objectPath(key)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open object %s: %w", key, err)
+	}
+
+	metadata := map[string]string{}
+	if metaBytes, err := os.ReadFile(path + metaSuffix); err == nil {
+		if err := json.Unmarshal(metaBytes, &metadata); err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("failed to parse metadata for %s: %w", key, err)
+		}
+	}
+
+	return file, metadata, nil
+}
+
+func (f *FSStore) Delete(ctx context.Context, key string) error {
+	path := f.objectPath(key)
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+	if err := os.Remove(path + metaSuffix); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete metadata for %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (f *FSStore) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := os.Stat(f.objectPath(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to stat object %s: %w", key, err)
+	}
+	return true, nil
+}
+
+func (f *FSStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+
+	err := filepath.Walk(f.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasSuffix(path, metaSuffix) {
+			return nil
+		}
+
+		key := filepath.ToSlash(strings.TrimPrefix(strings.TrimPrefix(path, f.root), string(filepath.Separator)))
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		objects = append(objects, Object{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects under %s: %w", prefix, err)
+	}
+
+	return objects, nil
+}
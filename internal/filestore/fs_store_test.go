@@ -0,0 +1,94 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestFSStore_PutGetDelete(t *testing.T) {
+	store, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore failed: %v", err)
+	}
+
+	ctx := context.Background()
+	key := "documents/prod/report.pdf"
+	metadata := map[string]string{"document-id": "doc-123", "original-name": "report.pdf"}
+
+	if err := store.Put(ctx, key, bytes.NewReader([]byte("content")), metadata); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	r, gotMeta, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("failed to read content: %v", err)
+	}
+	if string(content) != "content" {
+		t.Errorf("got content %q, want %q", content, "content")
+	}
+	if gotMeta["document-id"] != "doc-123" {
+		t.Errorf("got metadata[document-id]=%q, want doc-123", gotMeta["document-id"])
+	}
+
+	exists, err := store.Exists(ctx, key)
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if !exists {
+		t.Error("expected Exists to report true for a stored object")
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, _, err := store.Get(ctx, key); err == nil {
+		t.Error("expected error getting deleted object, got nil")
+	}
+
+	exists, err = store.Exists(ctx, key)
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if exists {
+		t.Error("expected Exists to report false after delete")
+	}
+}
+
+func TestFSStore_List(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFSStore(dir)
+	if err != nil {
+		t.Fatalf("NewFSStore failed: %v", err)
+	}
+
+	ctx := context.Background()
+	keys := []string{"documents/prod/a.pdf", "documents/prod/b.pdf", "documents/dev/c.pdf"}
+	for _, key := range keys {
+		if err := store.Put(ctx, key, bytes.NewReader([]byte("x")), nil); err != nil {
+			t.Fatalf("Put(%s) failed: %v", key, err)
+		}
+	}
+
+	objects, err := store.List(ctx, "documents/prod/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("got %d objects, want 2", len(objects))
+	}
+
+	for _, obj := range objects {
+		if filepath.Dir(obj.Key) != "documents/prod" {
+			t.Errorf("unexpected key in prod listing: %s", obj.Key)
+		}
+	}
+}
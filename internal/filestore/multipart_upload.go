@@ -0,0 +1,232 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage"
+)
+
+const (
+	// multipartPartSize is the size of each part sent via UploadPart. S3 requires
+	// every part but the last to be at least 5MB.
+	multipartPartSize = 5 * 1024 * 1024
+
+	// multipartConcurrency caps how many parts are in flight at once.
+	multipartConcurrency = 4
+)
+
+// MultipartUploader uploads large objects to S3 as a sequence of parts, so a 50-500MB
+// document doesn't need to be held in memory as a single buffer and so an in-flight
+// upload can be resumed (via ListInFlightUploads) or aborted on client disconnect.
+type MultipartUploader struct {
+	client     storage.S3APIClient
+	bucketName string
+}
+
+// NewMultipartUploader creates an uploader targeting the given bucket.
+func NewMultipartUploader(client storage.S3APIClient, bucketName string) *MultipartUploader {
+	return &MultipartUploader{client: client, bucketName: bucketName}
+}
+
+// Upload writes r to key, splitting it into multipartPartSize parts uploaded with
+// multipartConcurrency-way concurrency. If ctx is canceled (e.g. the client
+// disconnects) before completion, the in-progress multipart upload is aborted via
+// AbortMultipartUpload rather than left to expire on its own.
+func (u *MultipartUploader) Upload(ctx context.Context, key string, r io.Reader, metadata map[string]string) error {
+	parts, err := readParts(r, multipartPartSize)
+	if err != nil {
+		return fmt.Errorf("failed to read upload body: %w", err)
+	}
+
+	if len(parts) <= 1 {
+		body := []byte{}
+		if len(parts) == 1 {
+			body = parts[0]
+		}
+		return u.putSingle(key, body, metadata)
+	}
+
+	createOut, err := u.client.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket:   aws.String(u.bucketName),
+		Key:      aws.String(key),
+		Metadata: toAWSMetadata(metadata),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create multipart upload for %s: %w", key, err)
+	}
+	uploadID := createOut.UploadId
+
+	completed, err := u.uploadParts(ctx, key, uploadID, parts)
+	if err != nil {
+		u.abort(key, uploadID)
+		return err
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		return aws.Int64Value(completed[i].PartNumber) < aws.Int64Value(completed[j].PartNumber)
+	})
+
+	_, err = u.client.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(u.bucketName),
+		Key:             aws.String(key),
+		UploadId:        uploadID,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		u.abort(key, uploadID)
+		return fmt.Errorf("failed to complete multipart upload for %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (u *MultipartUploader) putSingle(key string, body []byte, metadata map[string]string) error {
+	store := &S3Store{client: u.client, bucketName: u.bucketName}
+	return store.Put(context.Background(), key, bytes.NewReader(body), metadata)
+}
+
+func (u *MultipartUploader) uploadParts(ctx context.Context, key string, uploadID *string, parts [][]byte) ([]*s3.CompletedPart, error) {
+	type result struct {
+		part *s3.CompletedPart
+		err  error
+	}
+
+	jobs := make(chan int)
+	results := make(chan result, len(parts))
+
+	var wg sync.WaitGroup
+	for i := 0; i < multipartConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partNumber := range jobs {
+				select {
+				case <-ctx.Done():
+					results <- result{err: ctx.Err()}
+					continue
+				default:
+				}
+
+				out, err := u.client.UploadPart(&s3.UploadPartInput{
+					Bucket:     aws.String(u.bucketName),
+					Key:        aws.String(key),
+					UploadId:   uploadID,
+					PartNumber: aws.Int64(int64(partNumber + 1)),
+					Body:       bytes.NewReader(parts[partNumber]),
+				})
+				if err != nil {
+					results <- result{err: fmt.Errorf("failed to upload part %d: %w", partNumber+1, err)}
+					continue
+				}
+
+				results <- result{part: &s3.CompletedPart{
+					ETag:       out.ETag,
+					PartNumber: aws.Int64(int64(partNumber + 1)),
+				}}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range parts {
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- i:
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	completed := make([]*s3.CompletedPart, 0, len(parts))
+	var firstErr error
+	for res := range results {
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			continue
+		}
+		completed = append(completed, res.part)
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if len(completed) != len(parts) {
+		return nil, fmt.Errorf("uploaded %d of %d parts", len(completed), len(parts))
+	}
+
+	return completed, nil
+}
+
+func (u *MultipartUploader) abort(key string, uploadID *string) {
+	_, _ = u.client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(u.bucketName),
+		Key:      aws.String(key),
+		UploadId: uploadID,
+	})
+}
+
+// InFlightUpload describes a multipart upload that was started but never completed
+// or aborted, as discovered via ListMultipartUploads.
+type InFlightUpload struct {
+	Key      string
+	UploadID string
+}
+
+// ListInFlightUploads returns multipart uploads under prefix that have neither
+// completed nor been aborted, so a resumed client can discover an existing UploadId
+// instead of restarting from scratch.
+func (u *MultipartUploader) ListInFlightUploads(ctx context.Context, prefix string) ([]InFlightUpload, error) {
+	out, err := u.client.ListMultipartUploads(&s3.ListMultipartUploadsInput{
+		Bucket: aws.String(u.bucketName),
+		Prefix: aws.String(prefix),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list in-flight uploads under %s: %w", prefix, err)
+	}
+
+	uploads := make([]InFlightUpload, 0, len(out.Uploads))
+	for _, upload := range out.Uploads {
+		uploads = append(uploads, InFlightUpload{
+			Key:      aws.StringValue(upload.Key),
+			UploadID: aws.StringValue(upload.UploadId),
+		})
+	}
+
+	return uploads, nil
+}
+
+// readParts splits r into chunks of at most partSize bytes. A body smaller than
+// partSize yields either zero parts (empty body) or one.
+func readParts(r io.Reader, partSize int) ([][]byte, error) {
+	var parts [][]byte
+	for {
+		buf := make([]byte, partSize)
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			parts = append(parts, buf[:n])
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parts, nil
+}
@@ -0,0 +1,108 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage/mocks"
+)
+
+func TestMultipartUploader_Upload_SmallBodyUsesPutObject(t *testing.T) {
+	client := &mocks.S3Client{}
+	uploader := NewMultipartUploader(client, "test-bucket")
+
+	if err := uploader.Upload(context.Background(), "documents/prod/small.txt", bytes.NewReader([]byte("small file")), nil); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	if len(client.PutObjectCalls) != 1 {
+		t.Fatalf("expected 1 PutObject call for a small body, got %d", len(client.PutObjectCalls))
+	}
+	if len(client.CreateMultipartUploadCalls) != 0 {
+		t.Fatalf("expected no multipart calls for a small body, got %d", len(client.CreateMultipartUploadCalls))
+	}
+}
+
+func TestMultipartUploader_Upload_LargeBodyUsesMultipart(t *testing.T) {
+	client := &mocks.S3Client{
+		CreateMultipartUploadFunc: func(input *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+			return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload-1")}, nil
+		},
+		UploadPartFunc: func(input *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+			return &s3.UploadPartOutput{ETag: aws.String("etag")}, nil
+		},
+	}
+	uploader := NewMultipartUploader(client, "test-bucket")
+
+	body := bytes.Repeat([]byte("x"), multipartPartSize*2+1)
+	if err := uploader.Upload(context.Background(), "documents/prod/large.bin", bytes.NewReader(body), nil); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	if len(client.CreateMultipartUploadCalls) != 1 {
+		t.Fatalf("expected 1 CreateMultipartUpload call, got %d", len(client.CreateMultipartUploadCalls))
+	}
+	if len(client.UploadPartCalls) != 3 {
+		t.Fatalf("expected 3 UploadPart calls, got %d", len(client.UploadPartCalls))
+	}
+	if len(client.CompleteMultipartUploadCalls) != 1 {
+		t.Fatalf("expected 1 CompleteMultipartUpload call, got %d", len(client.CompleteMultipartUploadCalls))
+	}
+	if len(client.AbortMultipartUploadCalls) != 0 {
+		t.Fatalf("expected no abort calls on success, got %d", len(client.AbortMultipartUploadCalls))
+	}
+}
+
+func TestMultipartUploader_Upload_AbortsOnPartFailure(t *testing.T) {
+	client := &mocks.S3Client{
+		CreateMultipartUploadFunc: func(input *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+			return &s3.CreateMultipartUploadOutput{UploadId: aws.String("upload-1")}, nil
+		},
+		UploadPartFunc: func(input *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+			return nil, errors.New("network error")
+		},
+	}
+	uploader := NewMultipartUploader(client, "test-bucket")
+
+	body := bytes.Repeat([]byte("x"), multipartPartSize*2+1)
+	err := uploader.Upload(context.Background(), "documents/prod/large.bin", bytes.NewReader(body), nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if len(client.AbortMultipartUploadCalls) != 1 {
+		t.Fatalf("expected 1 AbortMultipartUpload call, got %d", len(client.AbortMultipartUploadCalls))
+	}
+	if len(client.CompleteMultipartUploadCalls) != 0 {
+		t.Fatalf("expected no complete calls after a part failure, got %d", len(client.CompleteMultipartUploadCalls))
+	}
+}
+
+func TestMultipartUploader_ListInFlightUploads(t *testing.T) {
+	client := &mocks.S3Client{
+		ListMultipartUploadsFunc: func(input *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error) {
+			return &s3.ListMultipartUploadsOutput{
+				Uploads: []*s3.MultipartUpload{
+					{Key: aws.String("documents/prod/doc-1-report.pdf"), UploadId: aws.String("upload-1")},
+				},
+			}, nil
+		},
+	}
+	uploader := NewMultipartUploader(client, "test-bucket")
+
+	uploads, err := uploader.ListInFlightUploads(context.Background(), "documents/prod/doc-1")
+	if err != nil {
+		t.Fatalf("ListInFlightUploads failed: %v", err)
+	}
+	if len(uploads) != 1 {
+		t.Fatalf("got %d uploads, want 1", len(uploads))
+	}
+	if uploads[0].UploadID != "upload-1" {
+		t.Errorf("got UploadID %q, want upload-1", uploads[0].UploadID)
+	}
+}
@@ -0,0 +1,162 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage"
+)
+
+// S3Store is a Store backed by an S3 bucket, reusing the same client interface as the
+// delete-handler's document locator so both can share a mock in tests.
+type S3Store struct {
+	client     storage.S3APIClient
+	bucketName string
+}
+
+// NewS3Store creates a Store backed by the given bucket.
+func NewS3Store(client storage.S3APIClient, bucketName string) *S3Store {
+	return &S3Store{client: client, bucketName: bucketName}
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, metadata map[string]string) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read object body: %w", err)
+	}
+
+	// "content-type" is surfaced as the S3 ContentType header and
+	// TaggingMetadataKey as the object's Tagging, rather than as user-metadata
+	// entries; every other key is passed through as-is.
+	contentType := metadata["content-type"]
+	tagging := metadata[TaggingMetadataKey]
+	userMetadata := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		if k != "content-type" && k != TaggingMetadataKey {
+			userMetadata[k] = v
+		}
+	}
+
+	sum := md5.Sum(body)
+	input := &s3.PutObjectInput{
+		Bucket:     aws.String(s.bucketName),
+		Key:        aws.String(key),
+		Body:       bytes.NewReader(body),
+		Metadata:   toAWSMetadata(userMetadata),
+		ContentMD5: aws.String(base64.StdEncoding.EncodeToString(sum[:])),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if tagging != "" {
+		input.Tagging = aws.String(tagging)
+	}
+
+	_, err = s.client.PutObject(input)
+	if err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, map[string]string, error) {
+	out, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+
+	metadata := fromAWSMetadata(out.Metadata)
+	if contentType := aws.StringValue(out.ContentType); contentType != "" {
+		metadata["content-type"] = contentType
+	}
+
+	return out.Body, metadata, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete object %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3Store) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := s.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && (awsErr.Code() == s3.ErrCodeNoSuchKey || awsErr.Code() == "NotFound") {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to head object %s: %w", key, err)
+	}
+	return true, nil
+}
+
+func (s *S3Store) List(ctx context.Context, prefix string) ([]Object, error) {
+	var objects []Object
+	var continuationToken *string
+
+	for {
+		out, err := s.client.ListObjectsV2(&s3.ListObjectsV2Input{
+			Bucket:            aws.String(s.bucketName),
+			Prefix:            aws.String(prefix),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects under %s: %w", prefix, err)
+		}
+
+		for _, item := range out.Contents {
+			objects = append(objects, Object{
+				Key:          aws.StringValue(item.Key),
+				Size:         aws.Int64Value(item.Size),
+				LastModified: aws.TimeValue(item.LastModified),
+			})
+		}
+
+		if out.IsTruncated == nil || !*out.IsTruncated {
+			break
+		}
+		continuationToken = out.NextContinuationToken
+	}
+
+	return objects, nil
+}
+
+func toAWSMetadata(metadata map[string]string) map[string]*string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	out := make(map[string]*string, len(metadata))
+	for k, v := range metadata {
+		out[k] = aws.String(v)
+	}
+	return out
+}
+
+func fromAWSMetadata(metadata map[string]*string) map[string]string {
+	out := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		out[k] = aws.StringValue(v)
+	}
+	return out
+}
@@ -0,0 +1,114 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage/mocks"
+)
+
+func TestS3Store_Put_SplitsContentTypeFromMetadata(t *testing.T) {
+	client := &mocks.S3Client{}
+	store := NewS3Store(client, "test-bucket")
+
+	metadata := map[string]string{"document-id": "doc-123", "content-type": "application/pdf"}
+	if err := store.Put(context.Background(), "documents/prod/report.pdf", bytes.NewReader([]byte("x")), metadata); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if len(client.PutObjectCalls) != 1 {
+		t.Fatalf("expected 1 PutObject call, got %d", len(client.PutObjectCalls))
+	}
+	input := client.PutObjectCalls[0]
+
+	if got := aws.StringValue(input.ContentType); got != "application/pdf" {
+		t.Errorf("got ContentType %q, want application/pdf", got)
+	}
+	if _, ok := input.Metadata["content-type"]; ok {
+		t.Error("content-type should not also be passed as user metadata")
+	}
+	if got := aws.StringValue(input.Metadata["document-id"]); got != "doc-123" {
+		t.Errorf("got metadata[document-id]=%q, want doc-123", got)
+	}
+	if input.ContentMD5 == nil || *input.ContentMD5 == "" {
+		t.Error("expected ContentMD5 to be set")
+	}
+}
+
+func TestS3Store_Put_AppliesTaggingFromMetadata(t *testing.T) {
+	client := &mocks.S3Client{}
+	store := NewS3Store(client, "test-bucket")
+
+	metadata := map[string]string{"document-id": "doc-123", TaggingMetadataKey: "collection=docs&tenant=acme"}
+	if err := store.Put(context.Background(), "documents/prod/report.pdf", bytes.NewReader([]byte("x")), metadata); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	input := client.PutObjectCalls[0]
+	if got := aws.StringValue(input.Tagging); got != "collection=docs&tenant=acme" {
+		t.Errorf("got Tagging %q, want collection=docs&tenant=acme", got)
+	}
+	if _, ok := input.Metadata[TaggingMetadataKey]; ok {
+		t.Error("the tagging metadata key should not also be passed through as user metadata")
+	}
+}
+
+func TestS3Store_Exists(t *testing.T) {
+	client := &mocks.S3Client{
+		HeadObjectFunc: func(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			if aws.StringValue(input.Key) == "documents/prod/report.pdf" {
+				return &s3.HeadObjectOutput{}, nil
+			}
+			return nil, awserr.New(s3.ErrCodeNoSuchKey, "not found", nil)
+		},
+	}
+	store := NewS3Store(client, "test-bucket")
+
+	exists, err := store.Exists(context.Background(), "documents/prod/report.pdf")
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if !exists {
+		t.Error("expected Exists to report true")
+	}
+
+	exists, err = store.Exists(context.Background(), "documents/prod/missing.pdf")
+	if err != nil {
+		t.Fatalf("Exists failed: %v", err)
+	}
+	if exists {
+		t.Error("expected Exists to report false for a missing key")
+	}
+}
+
+func TestS3Store_Get(t *testing.T) {
+	client := &mocks.S3Client{
+		GetObjectFunc: func(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			return &s3.GetObjectOutput{
+				Body:        io.NopCloser(bytes.NewReader([]byte("content"))),
+				ContentType: aws.String("application/pdf"),
+				Metadata:    map[string]*string{"document-id": aws.String("doc-123")},
+			}, nil
+		},
+	}
+	store := NewS3Store(client, "test-bucket")
+
+	r, metadata, err := store.Get(context.Background(), "documents/prod/report.pdf")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	defer r.Close()
+
+	if metadata["content-type"] != "application/pdf" {
+		t.Errorf("got metadata[content-type]=%q, want application/pdf", metadata["content-type"])
+	}
+	if metadata["document-id"] != "doc-123" {
+		t.Errorf("got metadata[document-id]=%q, want doc-123", metadata["document-id"])
+	}
+}
@@ -0,0 +1,47 @@
+// Package filestore abstracts document object storage behind a single interface so
+// handlers can run against S3 in production or a local directory in development and
+// tests, without threading AWS credentials through every code path.
+package filestore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrUploadTooLarge is returned when an upload exceeds a caller-configured
+// maximum size.
+var ErrUploadTooLarge = errors.New("upload exceeds maximum allowed size")
+
+// TaggingMetadataKey is a reserved metadata key whose value, when present, is
+// applied as an S3 object's Tagging (x-amz-tagging) instead of a regular
+// x-amz-meta-* entry. Callers that build a metadata map for Put or
+// StreamingUploader.Upload use this key to request tagging, the same way
+// "content-type" is used to request the ContentType header.
+const TaggingMetadataKey = "x-s3-tagging"
+
+// Object describes a stored item returned by List, independent of the backend.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Store persists document content alongside a small set of string metadata.
+type Store interface {
+	// Put writes r under key, replacing any existing object, and attaches metadata.
+	Put(ctx context.Context, key string, r io.Reader, metadata map[string]string) error
+
+	// Get returns the object's content and metadata. Callers must close the reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, map[string]string, error)
+
+	// Delete removes the object at key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// Exists reports whether an object is already stored at key.
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// List returns every object whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
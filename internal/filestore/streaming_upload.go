@@ -0,0 +1,179 @@
+package filestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+
+	"aws-serverless-rag/internal/storage"
+)
+
+const (
+	// streamingPartSize matches S3's minimum multipart part size.
+	streamingPartSize = 5 * 1024 * 1024
+
+	// streamingConcurrency caps how many parts s3manager sends in flight at once.
+	streamingConcurrency = 4
+
+	// streamingStagingPrefix holds an upload while its content hash -- and so its
+	// final key -- is still unknown.
+	streamingStagingPrefix = "uploads/staging/"
+)
+
+// StreamingUploader streams an upload straight from its multipart-form part to S3
+// via s3manager, without ever holding the whole file in memory. Its final,
+// content-addressed key depends on the file's SHA-256, which is only known once
+// the stream has been fully read, so the upload first lands at a temporary
+// staging key and is then moved into place with a zero-copy
+// CopyObjectWithContext -- the same self-copy idiom storage.Uploader uses to
+// amend metadata after the fact.
+type StreamingUploader struct {
+	manager    s3manageriface.UploaderAPI
+	client     storage.S3APIClient
+	bucketName string
+}
+
+// NewStreamingUploader creates a StreamingUploader targeting bucketName, using
+// sess for the s3manager transfer and client for the staging rename.
+func NewStreamingUploader(sess *session.Session, client storage.S3APIClient, bucketName string) *StreamingUploader {
+	manager := s3manager.NewUploader(sess, func(u *s3manager.Uploader) {
+		u.PartSize = streamingPartSize
+		u.Concurrency = streamingConcurrency
+		u.LeavePartsOnError = false
+	})
+	return newStreamingUploader(manager, client, bucketName)
+}
+
+func newStreamingUploader(manager s3manageriface.UploaderAPI, client storage.S3APIClient, bucketName string) *StreamingUploader {
+	return &StreamingUploader{manager: manager, client: client, bucketName: bucketName}
+}
+
+// Upload streams r to a staging key while hashing it, then moves it to
+// "documents/prod/<sha256>", keyed purely by content hash so identical
+// content lands on the same key regardless of fileName. If an object already
+// exists at that final key, the staged upload is discarded and duplicate is
+// true. If maxBytes
+// is positive and r yields more than maxBytes, the staged upload is discarded
+// and ErrUploadTooLarge is returned instead of completing the move. tagging,
+// when non-empty, is applied as the object's S3 Tagging on both the staging
+// upload and the final, moved-into-place copy.
+func (u *StreamingUploader) Upload(ctx context.Context, fileName, contentType string, r io.Reader, metadata map[string]string, tagging string, maxBytes int64) (documentID string, duplicate bool, err error) {
+	stagingID, err := newUploadID()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to generate staging key: %w", err)
+	}
+	stagingKey := streamingStagingPrefix + stagingID
+
+	hasher := sha256.New()
+	body := io.TeeReader(r, hasher)
+
+	counter := &countingReader{r: body}
+	if maxBytes > 0 {
+		counter.limit = maxBytes + 1
+	}
+
+	input := &s3manager.UploadInput{
+		Bucket:   aws.String(u.bucketName),
+		Key:      aws.String(stagingKey),
+		Body:     counter,
+		Metadata: toAWSMetadata(metadata),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if tagging != "" {
+		input.Tagging = aws.String(tagging)
+	}
+
+	if _, err := u.manager.UploadWithContext(ctx, input); err != nil {
+		u.discardStaging(stagingKey)
+		return "", false, fmt.Errorf("failed to stream upload: %w", err)
+	}
+
+	if maxBytes > 0 && counter.total > maxBytes {
+		u.discardStaging(stagingKey)
+		return "", false, ErrUploadTooLarge
+	}
+
+	sum := hasher.Sum(nil)
+	sha256Hex := hex.EncodeToString(sum)
+	documentID = base32.StdEncoding.EncodeToString(sum)
+	finalKey := fmt.Sprintf("documents/prod/%s", sha256Hex)
+
+	store := &S3Store{client: u.client, bucketName: u.bucketName}
+	exists, err := store.Exists(ctx, finalKey)
+	if err != nil {
+		u.discardStaging(stagingKey)
+		return "", false, err
+	}
+	if exists {
+		u.discardStaging(stagingKey)
+		return documentID, true, nil
+	}
+
+	finalMetadata := make(map[string]string, len(metadata)+2)
+	for k, v := range metadata {
+		finalMetadata[k] = v
+	}
+	finalMetadata["document-id"] = documentID
+	finalMetadata["sha256"] = sha256Hex
+
+	copyInput := &s3.CopyObjectInput{
+		Bucket:            aws.String(u.bucketName),
+		Key:               aws.String(finalKey),
+		CopySource:        aws.String(u.bucketName + "/" + stagingKey),
+		Metadata:          toAWSMetadata(finalMetadata),
+		MetadataDirective: aws.String(s3.MetadataDirectiveReplace),
+	}
+	if contentType != "" {
+		copyInput.ContentType = aws.String(contentType)
+	}
+	if tagging != "" {
+		copyInput.Tagging = aws.String(tagging)
+		copyInput.TaggingDirective = aws.String(s3.TaggingDirectiveReplace)
+	}
+	if _, err := u.client.CopyObjectWithContext(ctx, copyInput); err != nil {
+		u.discardStaging(stagingKey)
+		return "", false, fmt.Errorf("failed to move staged upload to %s: %w", finalKey, err)
+	}
+
+	u.discardStaging(stagingKey)
+	return documentID, false, nil
+}
+
+func (u *StreamingUploader) discardStaging(key string) {
+	_, _ = u.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(u.bucketName),
+		Key:    aws.String(key),
+	})
+}
+
+// countingReader wraps r, tracking the cumulative number of bytes read and,
+// once limit is positive, refusing to yield more than limit bytes so a caller
+// can detect an oversized stream without buffering it first.
+type countingReader struct {
+	r     io.Reader
+	limit int64
+	total int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	if c.limit > 0 && c.total >= c.limit {
+		return 0, io.EOF
+	}
+	if c.limit > 0 && int64(len(p)) > c.limit-c.total {
+		p = p[:c.limit-c.total]
+	}
+	n, err := c.r.Read(p)
+	c.total += int64(n)
+	return n, err
+}
@@ -0,0 +1,112 @@
+package filestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage/mocks"
+)
+
+func TestStreamingUploader_Upload_MovesStagedObjectToContentAddressedKey(t *testing.T) {
+	content := "streamed file content"
+	sum := sha256.Sum256([]byte(content))
+	wantSHA256 := hex.EncodeToString(sum[:])
+	wantKey := "documents/prod/" + wantSHA256
+
+	manager := &mocks.Uploader{}
+	client := &mocks.S3Client{
+		HeadObjectFunc: func(*s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return nil, awserr.New(s3.ErrCodeNoSuchKey, "not found", nil)
+		},
+		CopyObjectWithContextFunc: func(input *s3.CopyObjectInput) (*s3.CopyObjectOutput, error) {
+			return &s3.CopyObjectOutput{}, nil
+		},
+	}
+
+	uploader := newStreamingUploader(manager, client, "bucket")
+
+	documentID, duplicate, err := uploader.Upload(context.Background(), "report.pdf", "application/pdf", strings.NewReader(content), map[string]string{"original-name": "report.pdf"}, "collection=docs", 0)
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if duplicate {
+		t.Error("expected duplicate to be false for a new file")
+	}
+	if documentID == "" {
+		t.Error("expected a non-empty documentID")
+	}
+
+	if len(client.CopyObjectWithContextCalls) != 1 {
+		t.Fatalf("expected one CopyObjectWithContext call, got %d", len(client.CopyObjectWithContextCalls))
+	}
+	copyInput := client.CopyObjectWithContextCalls[0]
+	if aws.StringValue(copyInput.Key) != wantKey {
+		t.Errorf("got final key %q, want %q", aws.StringValue(copyInput.Key), wantKey)
+	}
+	if got := aws.StringValue(copyInput.Metadata["sha256"]); got != wantSHA256 {
+		t.Errorf("got metadata[sha256]=%q, want %q", got, wantSHA256)
+	}
+	if got := aws.StringValue(copyInput.Tagging); got != "collection=docs" {
+		t.Errorf("got Tagging %q, want collection=docs", got)
+	}
+	if got := aws.StringValue(copyInput.TaggingDirective); got != s3.TaggingDirectiveReplace {
+		t.Errorf("got TaggingDirective %q, want %q", got, s3.TaggingDirectiveReplace)
+	}
+
+	if len(client.DeleteObjectCalls) != 1 {
+		t.Fatalf("expected the staging object to be deleted, got %d DeleteObject calls", len(client.DeleteObjectCalls))
+	}
+}
+
+func TestStreamingUploader_Upload_DuplicateSkipsCopy(t *testing.T) {
+	manager := &mocks.Uploader{}
+	client := &mocks.S3Client{
+		HeadObjectFunc: func(*s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return &s3.HeadObjectOutput{}, nil
+		},
+	}
+
+	uploader := newStreamingUploader(manager, client, "bucket")
+
+	documentID, duplicate, err := uploader.Upload(context.Background(), "report.pdf", "application/pdf", strings.NewReader("content"), nil, "", 0)
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if !duplicate {
+		t.Error("expected duplicate to be true when the final key already exists")
+	}
+	if documentID == "" {
+		t.Error("expected a non-empty documentID even for a duplicate")
+	}
+	if len(client.CopyObjectWithContextCalls) != 0 {
+		t.Error("expected no CopyObjectWithContext call for a duplicate")
+	}
+	if len(client.DeleteObjectCalls) != 1 {
+		t.Fatalf("expected the staging object to be deleted, got %d DeleteObject calls", len(client.DeleteObjectCalls))
+	}
+}
+
+func TestStreamingUploader_Upload_RejectsOversizedStream(t *testing.T) {
+	manager := &mocks.Uploader{}
+	client := &mocks.S3Client{}
+
+	uploader := newStreamingUploader(manager, client, "bucket")
+
+	_, _, err := uploader.Upload(context.Background(), "report.pdf", "application/pdf", strings.NewReader("this content is too long"), nil, "", 4)
+	if err != ErrUploadTooLarge {
+		t.Fatalf("got error %v, want ErrUploadTooLarge", err)
+	}
+	if len(client.CopyObjectWithContextCalls) != 0 {
+		t.Error("expected no CopyObjectWithContext call for an oversized stream")
+	}
+	if len(client.DeleteObjectCalls) != 1 {
+		t.Fatalf("expected the staging object to be discarded, got %d DeleteObject calls", len(client.DeleteObjectCalls))
+	}
+}
@@ -0,0 +1,108 @@
+package loadtest
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyPercentiles holds the percentiles a StageReport summarizes latency with, in
+// milliseconds.
+type LatencyPercentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// StageReport summarizes every Result collected for one stage.
+type StageReport struct {
+	Stage            string             `json:"stage"`
+	TotalRequests    int                `json:"total_requests"`
+	ErrorCount       int                `json:"error_count"`
+	ErrorBreakdown   map[string]int     `json:"error_breakdown,omitempty"`
+	LatencyMS        LatencyPercentiles `json:"latency_ms"`
+	ThroughputPerSec float64            `json:"throughput_per_sec"`
+}
+
+// aggregator collects Results for one stage as they arrive from concurrent workers
+// and computes LatencyPercentiles from the full sorted sample. That's exact rather
+// than an HDR histogram's bucketed approximation, and fine at load-test sample
+// sizes (tens of thousands of requests, not the billions HDR histograms are built
+// for).
+type aggregator struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	errors    map[string]int
+	total     int
+}
+
+func newAggregator() *aggregator {
+	return &aggregator{errors: make(map[string]int)}
+}
+
+// add records one Result. Safe for concurrent use.
+func (a *aggregator) add(r Result) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.total++
+	a.latencies = append(a.latencies, r.Latency)
+	if r.Err != nil {
+		a.errors[r.Err.Error()]++
+	}
+}
+
+// report builds the final StageReport for stageName, given the wall-clock time the
+// stage took to run (used to compute ThroughputPerSec). Not safe to call
+// concurrently with add.
+func (a *aggregator) report(stageName string, elapsed time.Duration) *StageReport {
+	sorted := make([]time.Duration, len(a.latencies))
+	copy(sorted, a.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	errorCount := 0
+	for _, count := range a.errors {
+		errorCount += count
+	}
+
+	var throughput float64
+	if elapsed > 0 {
+		throughput = float64(a.total) / elapsed.Seconds()
+	}
+
+	return &StageReport{
+		Stage:          stageName,
+		TotalRequests:  a.total,
+		ErrorCount:     errorCount,
+		ErrorBreakdown: a.errors,
+		LatencyMS: LatencyPercentiles{
+			P50: percentileMS(sorted, 0.50),
+			P90: percentileMS(sorted, 0.90),
+			P95: percentileMS(sorted, 0.95),
+			P99: percentileMS(sorted, 0.99),
+			Max: percentileMS(sorted, 1.0),
+		},
+		ThroughputPerSec: throughput,
+	}
+}
+
+// percentileMS returns the p-th percentile (0 <= p <= 1) of sorted, a slice already
+// sorted ascending, in milliseconds. Takes the element at index floor(p*(len-1)),
+// without interpolation; returns 0 for an empty sample.
+func percentileMS(sorted []time.Duration, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(p * float64(len(sorted)-1))
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+
+	return float64(sorted[rank]) / float64(time.Millisecond)
+}
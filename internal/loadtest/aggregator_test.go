@@ -0,0 +1,85 @@
+package loadtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAggregator_Report(t *testing.T) {
+	agg := newAggregator()
+	for i := 1; i <= 100; i++ {
+		agg.add(Result{Latency: time.Duration(i) * time.Millisecond})
+	}
+
+	report := agg.report("query-1", time.Second)
+
+	if report.TotalRequests != 100 {
+		t.Errorf("expected 100 total requests, got %d", report.TotalRequests)
+	}
+	if report.ErrorCount != 0 {
+		t.Errorf("expected no errors, got %d", report.ErrorCount)
+	}
+	if report.LatencyMS.P50 != 50 {
+		t.Errorf("expected p50=50, got %v", report.LatencyMS.P50)
+	}
+	if report.LatencyMS.P99 != 99 {
+		t.Errorf("expected p99=99, got %v", report.LatencyMS.P99)
+	}
+	if report.LatencyMS.Max != 100 {
+		t.Errorf("expected max=100, got %v", report.LatencyMS.Max)
+	}
+}
+
+func TestAggregator_ReportTracksErrorBreakdown(t *testing.T) {
+	agg := newAggregator()
+	agg.add(Result{Latency: time.Millisecond, Err: errors.New("timeout")})
+	agg.add(Result{Latency: time.Millisecond, Err: errors.New("timeout")})
+	agg.add(Result{Latency: time.Millisecond, Err: errors.New("500")})
+	agg.add(Result{Latency: time.Millisecond})
+
+	report := agg.report("upload-1", time.Second)
+
+	if report.TotalRequests != 4 {
+		t.Errorf("expected 4 total requests, got %d", report.TotalRequests)
+	}
+	if report.ErrorCount != 3 {
+		t.Errorf("expected 3 errors, got %d", report.ErrorCount)
+	}
+	if report.ErrorBreakdown["timeout"] != 2 {
+		t.Errorf("expected 2 timeout errors, got %d", report.ErrorBreakdown["timeout"])
+	}
+	if report.ErrorBreakdown["500"] != 1 {
+		t.Errorf("expected 1 \"500\" error, got %d", report.ErrorBreakdown["500"])
+	}
+}
+
+func TestAggregator_ReportThroughput(t *testing.T) {
+	agg := newAggregator()
+	for i := 0; i < 10; i++ {
+		agg.add(Result{Latency: time.Millisecond})
+	}
+
+	report := agg.report("query-1", 2*time.Second)
+
+	if report.ThroughputPerSec != 5 {
+		t.Errorf("expected throughput 5/sec, got %v", report.ThroughputPerSec)
+	}
+
+	zeroElapsed := agg.report("query-1", 0)
+	if zeroElapsed.ThroughputPerSec != 0 {
+		t.Errorf("expected 0 throughput for zero elapsed time, got %v", zeroElapsed.ThroughputPerSec)
+	}
+}
+
+func TestAggregator_ReportEmpty(t *testing.T) {
+	agg := newAggregator()
+	report := agg.report("empty", time.Second)
+
+	if report.TotalRequests != 0 {
+		t.Errorf("expected 0 total requests, got %d", report.TotalRequests)
+	}
+	if report.LatencyMS.Max != 0 {
+		t.Errorf("expected 0 max latency for an empty sample, got %v", report.LatencyMS.Max)
+	}
+}
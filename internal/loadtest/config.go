@@ -0,0 +1,130 @@
+// Package loadtest drives mixed upload/query workloads against a deployed RAG API
+// from a JSON scenario file and reports per-stage throughput and latency
+// percentiles, using pkg/ragclient for all HTTP calls.
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Stage kinds accepted by StageConfig.Kind.
+const (
+	StageUpload = "upload"
+	StageQuery  = "query"
+	StageEmbed  = "embed_batch"
+	StageMixed  = "mixed"
+)
+
+// Duration unmarshals a JSON duration from either a Go duration string ("2m") or a
+// plain number of nanoseconds, since scenario files are hand-written.
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch v := raw.(type) {
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", v, err)
+		}
+		*d = Duration(parsed)
+	case float64:
+		*d = Duration(time.Duration(v))
+	default:
+		return fmt.Errorf("invalid duration: %v", raw)
+	}
+	return nil
+}
+
+// Duration returns d as a time.Duration.
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// GlobalConfig holds scenario-wide settings.
+type GlobalConfig struct {
+	Endpoint  string   `json:"endpoint"`
+	Timeout   Duration `json:"timeout,omitempty"`
+	AuthToken string   `json:"auth_token,omitempty"`
+
+	// Backend selects the registered backend.EmbeddingProvider embed_batch and
+	// mixed stages embed against (see internal/backend.Get). Defaults to
+	// backend.DefaultBackendName ("bedrock"); set to "mock" to load-test the
+	// harness itself without calling out to Bedrock.
+	Backend string `json:"backend,omitempty"`
+}
+
+// UploadPayload describes the synthetic file an upload stage generates.
+type UploadPayload struct {
+	Type      string `json:"type"`
+	SizeBytes int    `json:"size_bytes"`
+}
+
+// EmbedPayload describes the synthetic batch an embed_batch stage embeds.
+type EmbedPayload struct {
+	BatchSize int `json:"batch_size"`
+	TextSize  int `json:"text_size"`
+}
+
+// MixedWeight is one of the sub-kinds a "mixed" stage chooses between. Weight is
+// relative, not a percentage: a stage with weights 3 and 1 runs the first kind
+// three times as often as the second.
+type MixedWeight struct {
+	Kind   string `json:"kind"`
+	Weight int    `json:"weight"`
+}
+
+// StageConfig describes one workload stage. Count bounds an upload stage by number
+// of requests; Duration bounds a query stage by wall-clock time. DependsOn, if set,
+// must name an earlier stage whose upload results (document IDs) this stage can
+// reuse once that stage has finished. A "mixed" stage ignores Kind-specific fields
+// below in favor of Mixed, which names the sub-kinds (each drawing on the same
+// Payload/QuestionsFile/MaxResults/Embed fields) it randomly chooses between.
+type StageConfig struct {
+	Kind          string         `json:"kind"`
+	Name          string         `json:"name,omitempty"`
+	DependsOn     string         `json:"depends_on,omitempty"`
+	Concurrency   int            `json:"concurrency"`
+	Count         int            `json:"count,omitempty"`
+	Duration      Duration       `json:"duration,omitempty"`
+	Payload       *UploadPayload `json:"payload,omitempty"`
+	QuestionsFile string         `json:"questions_file,omitempty"`
+	MaxResults    int            `json:"max_results,omitempty"`
+	Embed         *EmbedPayload  `json:"embed,omitempty"`
+	Mixed         []MixedWeight  `json:"mixed,omitempty"`
+}
+
+// ScenarioConfig is the top-level shape of a --config JSON file.
+type ScenarioConfig struct {
+	Global GlobalConfig  `json:"global"`
+	Stages []StageConfig `json:"stages"`
+}
+
+// LoadScenario reads and parses a scenario file from path.
+func LoadScenario(path string) (*ScenarioConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario file: %w", err)
+	}
+
+	var scenario ScenarioConfig
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario file: %w", err)
+	}
+
+	for i, stage := range scenario.Stages {
+		if stage.Name == "" {
+			scenario.Stages[i].Name = fmt.Sprintf("%s-%d", stage.Kind, i)
+		}
+	}
+
+	return &scenario, nil
+}
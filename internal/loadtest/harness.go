@@ -0,0 +1,303 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"aws-serverless-rag/internal/backend"
+	"aws-serverless-rag/pkg/ragclient"
+)
+
+// Report is the top-level JSON document a loadtest run emits.
+type Report struct {
+	Stages []*StageReport `json:"stages"`
+}
+
+// Harness runs a ScenarioConfig's stages in order against one ragclient.Client,
+// passing each upload stage's document IDs to any later stage that names it in
+// DependsOn. embed_batch stages call embedder directly rather than going through
+// client, since embedding generation isn't exposed over the RAG API.
+type Harness struct {
+	client   *ragclient.Client
+	embedder backend.EmbeddingProvider
+}
+
+// NewHarness creates a Harness backed by client. embedder may be nil if the
+// scenario has no embed_batch or mixed stages.
+func NewHarness(client *ragclient.Client, embedder backend.EmbeddingProvider) *Harness {
+	return &Harness{client: client, embedder: embedder}
+}
+
+// Run executes every stage in scenario.Stages in order and returns one Report.
+func (h *Harness) Run(ctx context.Context, scenario *ScenarioConfig) (*Report, error) {
+	documentIDsByStage := make(map[string][]string)
+	report := &Report{}
+
+	for _, stage := range scenario.Stages {
+		var dependencyDocumentIDs []string
+		if stage.DependsOn != "" {
+			ids, ok := documentIDsByStage[stage.DependsOn]
+			if !ok {
+				return nil, fmt.Errorf("stage %q depends_on unknown stage %q", stage.Name, stage.DependsOn)
+			}
+			dependencyDocumentIDs = ids
+		}
+
+		stageReport, documentIDs, err := h.runStage(ctx, stage, dependencyDocumentIDs)
+		if err != nil {
+			return nil, fmt.Errorf("stage %q: %w", stage.Name, err)
+		}
+
+		documentIDsByStage[stage.Name] = documentIDs
+		report.Stages = append(report.Stages, stageReport)
+	}
+
+	return report, nil
+}
+
+// runStage fans stage.Concurrency workers out over stage.Count iterations (or, if
+// Count is 0, until stage.Duration elapses), collecting one Result per iteration.
+func (h *Harness) runStage(ctx context.Context, stage StageConfig, dependencyDocumentIDs []string) (*StageReport, []string, error) {
+	stageStart := time.Now()
+
+	factory, err := h.runnerFactory(stage, dependencyDocumentIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	concurrency := stage.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	stageCtx := ctx
+	if stage.Count <= 0 && stage.Duration.Duration() > 0 {
+		var cancel context.CancelFunc
+		stageCtx, cancel = context.WithTimeout(ctx, stage.Duration.Duration())
+		defer cancel()
+	}
+
+	results := make(chan Result)
+	var nextIteration int
+	var iterationMu sync.Mutex
+	claimIteration := func() (int, bool) {
+		iterationMu.Lock()
+		defer iterationMu.Unlock()
+		if stage.Count > 0 && nextIteration >= stage.Count {
+			return 0, false
+		}
+		i := nextIteration
+		nextIteration++
+		return i, true
+	}
+
+	var workers sync.WaitGroup
+	var documentIDsMu sync.Mutex
+	var documentIDs []string
+
+	for w := 0; w < concurrency; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case <-stageCtx.Done():
+					return
+				default:
+				}
+				if _, ok := claimIteration(); !ok {
+					return
+				}
+
+				result := factory().Run(stageCtx)
+				if result.DocumentID != "" {
+					documentIDsMu.Lock()
+					documentIDs = append(documentIDs, result.DocumentID)
+					documentIDsMu.Unlock()
+				}
+
+				select {
+				case results <- result:
+				case <-stageCtx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	agg := newAggregator()
+	for result := range results {
+		agg.add(result)
+	}
+
+	return agg.report(stage.Name, time.Since(stageStart)), documentIDs, nil
+}
+
+// runnerFactory builds the RunnerFactory for stage's kind.
+func (h *Harness) runnerFactory(stage StageConfig, dependencyDocumentIDs []string) (RunnerFactory, error) {
+	switch stage.Kind {
+	case StageUpload:
+		payload := stage.Payload
+		if payload == nil {
+			payload = &UploadPayload{Type: "text", SizeBytes: 1024}
+		}
+		return func() Runner {
+			return &uploadRunner{
+				client:      h.client,
+				fileName:    fmt.Sprintf("loadtest-%d.txt", rand.Int63()),
+				content:     syntheticContent(payload.SizeBytes),
+				contentType: contentTypeFor(payload.Type),
+				waitTimeout: 0,
+			}
+		}, nil
+
+	case StageQuery:
+		questions, err := loadQuestions(stage.QuestionsFile)
+		if err != nil {
+			return nil, err
+		}
+		maxResults := stage.MaxResults
+		if maxResults <= 0 {
+			maxResults = 5
+		}
+		return func() Runner {
+			return &queryRunner{
+				client:     h.client,
+				question:   questions[rand.Intn(len(questions))],
+				maxResults: maxResults,
+			}
+		}, nil
+
+	case StageEmbed:
+		if h.embedder == nil {
+			return nil, fmt.Errorf("stage %q: no embedding backend configured", stage.Name)
+		}
+		embed := stage.Embed
+		if embed == nil {
+			embed = &EmbedPayload{BatchSize: 1, TextSize: 256}
+		}
+		return func() Runner {
+			return &embedRunner{
+				embedder: h.embedder,
+				texts:    syntheticTexts(embed.BatchSize, embed.TextSize),
+			}
+		}, nil
+
+	case StageMixed:
+		return h.mixedRunnerFactory(stage, dependencyDocumentIDs)
+
+	default:
+		return nil, fmt.Errorf("unknown stage kind %q", stage.Kind)
+	}
+}
+
+// mixedRunnerFactory builds a RunnerFactory that, on each call, picks one of
+// stage.Mixed's sub-kinds at random (weighted by MixedWeight.Weight) and defers to
+// that sub-kind's own factory.
+func (h *Harness) mixedRunnerFactory(stage StageConfig, dependencyDocumentIDs []string) (RunnerFactory, error) {
+	if len(stage.Mixed) == 0 {
+		return nil, fmt.Errorf("stage %q: mixed stage has no sub-kinds", stage.Name)
+	}
+
+	type weightedFactory struct {
+		factory RunnerFactory
+		weight  int
+	}
+
+	var factories []weightedFactory
+	totalWeight := 0
+	for _, mixed := range stage.Mixed {
+		subStage := stage
+		subStage.Kind = mixed.Kind
+		subStage.Mixed = nil
+
+		factory, err := h.runnerFactory(subStage, dependencyDocumentIDs)
+		if err != nil {
+			return nil, fmt.Errorf("stage %q: mixed sub-kind %q: %w", stage.Name, mixed.Kind, err)
+		}
+
+		weight := mixed.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		factories = append(factories, weightedFactory{factory: factory, weight: weight})
+		totalWeight += weight
+	}
+
+	return func() Runner {
+		pick := rand.Intn(totalWeight)
+		for _, wf := range factories {
+			if pick < wf.weight {
+				return wf.factory()
+			}
+			pick -= wf.weight
+		}
+		return factories[len(factories)-1].factory()
+	}, nil
+}
+
+// syntheticTexts generates count synthetic texts of approximately textSize bytes
+// each, for embed_batch stages that don't read real document content.
+func syntheticTexts(count, textSize int) []string {
+	if count <= 0 {
+		count = 1
+	}
+	texts := make([]string, count)
+	for i := range texts {
+		texts[i] = string(syntheticContent(textSize))
+	}
+	return texts
+}
+
+func contentTypeFor(payloadType string) string {
+	switch payloadType {
+	case "pdf":
+		return "application/pdf"
+	default:
+		return "text/plain"
+	}
+}
+
+func syntheticContent(sizeBytes int) []byte {
+	if sizeBytes <= 0 {
+		sizeBytes = 1024
+	}
+	content := make([]byte, sizeBytes)
+	for i := range content {
+		content[i] = byte('a' + i%26)
+	}
+	return content
+}
+
+func loadQuestions(path string) ([]string, error) {
+	if path == "" {
+		return []string{"What is this document about?"}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read questions file: %w", err)
+	}
+
+	var questions []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			questions = append(questions, line)
+		}
+	}
+	if len(questions) == 0 {
+		return nil, fmt.Errorf("questions file %q has no questions", path)
+	}
+	return questions, nil
+}
@@ -0,0 +1,99 @@
+package loadtest
+
+import (
+	"context"
+	"testing"
+
+	"aws-serverless-rag/internal/backend"
+)
+
+type stubEmbeddingProvider struct{}
+
+func (stubEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, backend.TokenUsage, error) {
+	embeddings := make([][]float32, len(texts))
+	for i := range texts {
+		embeddings[i] = []float32{0.1}
+	}
+	return embeddings, backend.TokenUsage{EmbeddingTokens: len(texts)}, nil
+}
+
+func TestRunnerFactory_EmbedWithoutBackendErrors(t *testing.T) {
+	h := NewHarness(nil, nil)
+
+	_, err := h.runnerFactory(StageConfig{Kind: StageEmbed, Name: "embed-1"}, nil)
+	if err == nil {
+		t.Fatal("expected an error when no embedding backend is configured, got none")
+	}
+}
+
+func TestRunnerFactory_Embed(t *testing.T) {
+	h := NewHarness(nil, stubEmbeddingProvider{})
+
+	factory, err := h.runnerFactory(StageConfig{
+		Kind:  StageEmbed,
+		Name:  "embed-1",
+		Embed: &EmbedPayload{BatchSize: 3, TextSize: 10},
+	}, nil)
+	if err != nil {
+		t.Fatalf("runnerFactory failed: %v", err)
+	}
+
+	runner, ok := factory().(*embedRunner)
+	if !ok {
+		t.Fatalf("expected an *embedRunner, got %T", factory())
+	}
+	if len(runner.texts) != 3 {
+		t.Errorf("expected 3 texts, got %d", len(runner.texts))
+	}
+}
+
+func TestMixedRunnerFactory_NoSubKinds(t *testing.T) {
+	h := NewHarness(nil, nil)
+
+	_, err := h.mixedRunnerFactory(StageConfig{Kind: StageMixed, Name: "mixed-1"}, nil)
+	if err == nil {
+		t.Fatal("expected an error for a mixed stage with no sub-kinds, got none")
+	}
+}
+
+func TestMixedRunnerFactory_SingleWeightedSubKind(t *testing.T) {
+	h := NewHarness(nil, stubEmbeddingProvider{})
+
+	factory, err := h.mixedRunnerFactory(StageConfig{
+		Kind: StageMixed,
+		Name: "mixed-1",
+		Embed: &EmbedPayload{
+			BatchSize: 1,
+			TextSize:  10,
+		},
+		Mixed: []MixedWeight{
+			{Kind: StageEmbed, Weight: 3},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("mixedRunnerFactory failed: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, ok := factory().(*embedRunner); !ok {
+			t.Fatalf("expected every pick to be an *embedRunner with a single weighted sub-kind")
+		}
+	}
+}
+
+func TestSyntheticTexts(t *testing.T) {
+	texts := syntheticTexts(5, 20)
+	if len(texts) != 5 {
+		t.Fatalf("expected 5 texts, got %d", len(texts))
+	}
+	for i, text := range texts {
+		if len(text) != 20 {
+			t.Errorf("text %d: expected length 20, got %d", i, len(text))
+		}
+	}
+
+	// Defaults to 1 text when count is invalid.
+	if texts := syntheticTexts(0, 10); len(texts) != 1 {
+		t.Errorf("expected 1 text for count=0, got %d", len(texts))
+	}
+}
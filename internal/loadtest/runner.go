@@ -0,0 +1,94 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"aws-serverless-rag/internal/backend"
+	"aws-serverless-rag/pkg/ragclient"
+)
+
+// Result is the outcome of one Runner invocation.
+type Result struct {
+	Latency    time.Duration
+	StatusCode int
+	Err        error
+
+	// DocumentID is set by upload runners so a dependent stage can reuse it.
+	DocumentID string
+}
+
+// Runner executes a single unit of work for a stage (one upload, one query, ...).
+type Runner interface {
+	Run(ctx context.Context) Result
+}
+
+// RunnerFactory produces a fresh Runner for each iteration of a stage, so every
+// request gets its own payload/question.
+type RunnerFactory func() Runner
+
+// uploadRunner uploads one synthetic document and, when waitTimeout is positive,
+// waits for it to finish processing; with a zero waitTimeout it measures the
+// upload alone.
+type uploadRunner struct {
+	client      *ragclient.Client
+	fileName    string
+	content     []byte
+	contentType string
+	waitTimeout time.Duration
+}
+
+func (r *uploadRunner) Run(ctx context.Context) Result {
+	start := time.Now()
+
+	documentID, err := r.client.UploadDocument(r.fileName, r.content, r.contentType)
+	if err != nil {
+		return Result{Latency: time.Since(start), Err: fmt.Errorf("upload: %w", err)}
+	}
+
+	if r.waitTimeout > 0 {
+		if err := r.client.WaitForProcessingComplete(documentID, r.waitTimeout); err != nil {
+			return Result{Latency: time.Since(start), DocumentID: documentID, Err: fmt.Errorf("wait for processing: %w", err)}
+		}
+	}
+
+	return Result{Latency: time.Since(start), StatusCode: 200, DocumentID: documentID}
+}
+
+// queryRunner asks one question and records how long the answer took.
+type queryRunner struct {
+	client     *ragclient.Client
+	question   string
+	maxResults int
+}
+
+func (r *queryRunner) Run(ctx context.Context) Result {
+	start := time.Now()
+
+	_, err := r.client.QueryDocuments(r.question, r.maxResults)
+	if err != nil {
+		return Result{Latency: time.Since(start), Err: fmt.Errorf("query: %w", err)}
+	}
+
+	return Result{Latency: time.Since(start), StatusCode: 200}
+}
+
+// embedRunner embeds one batch of synthetic texts directly against a
+// backend.EmbeddingProvider, bypassing the HTTP API since embedding generation
+// isn't exposed over it.
+type embedRunner struct {
+	embedder backend.EmbeddingProvider
+	texts    []string
+}
+
+func (r *embedRunner) Run(ctx context.Context) Result {
+	start := time.Now()
+
+	_, _, err := r.embedder.Embed(ctx, r.texts)
+	if err != nil {
+		return Result{Latency: time.Since(start), Err: fmt.Errorf("embed: %w", err)}
+	}
+
+	return Result{Latency: time.Since(start), StatusCode: 200}
+}
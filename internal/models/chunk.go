@@ -15,6 +15,12 @@ type Chunk struct {
 	Metadata   map[string]interface{} `json:"metadata"`
 	ChunkIndex int                    `json:"chunk_index"`
 	CreatedAt  time.Time              `json:"created_at"`
+
+	// StartTimeMs and EndTimeMs are the chunk's position, in milliseconds into the
+	// source media, for chunks produced from an audio/video transcript (see package
+	// transcribe). nil for chunks produced from plain text.
+	StartTimeMs *int64 `json:"start_time_ms,omitempty"`
+	EndTimeMs   *int64 `json:"end_time_ms,omitempty"`
 }
 
 // ChunkMetadata contains additional information about the chunk
@@ -27,6 +33,11 @@ type ChunkMetadata struct {
 	SlideNumber *int   `json:"slide_number,omitempty"`
 	WordCount   int    `json:"word_count"`
 	CharCount   int    `json:"char_count"`
+	// Strategy records which SemanticChunker strategy produced this chunk
+	// (e.g. "fixed", "sentence", "markdown", "semantic"), so a reindex or
+	// retrieval-quality investigation can tell which chunking approach a
+	// given chunk came from.
+	Strategy string `json:"strategy,omitempty"`
 }
 
 // NewChunk creates a new chunk instance
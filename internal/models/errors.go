@@ -3,20 +3,30 @@ package models
 import (
 	"errors"
 	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
 )
 
 // Application errors
 var (
-	ErrInvalidFileType     = errors.New("unsupported file type")
-	ErrFileNotFound        = errors.New("file not found")
-	ErrProcessingFailed    = errors.New("document processing failed")
-	ErrEmbeddingFailed     = errors.New("embedding generation failed")
-	ErrVectorSearchFailed  = errors.New("vector search failed")
-	ErrLLMGenerationFailed = errors.New("LLM response generation failed")
-	ErrInvalidQuestion     = errors.New("question cannot be empty")
-	ErrDocumentNotFound    = errors.New("document not found")
-	ErrInvalidRequest      = errors.New("invalid request")
-	ErrServiceUnavailable  = errors.New("service temporarily unavailable")
+	ErrInvalidFileType      = errors.New("unsupported file type")
+	ErrFileNotFound         = errors.New("file not found")
+	ErrProcessingFailed     = errors.New("document processing failed")
+	ErrEmbeddingFailed      = errors.New("embedding generation failed")
+	ErrVectorSearchFailed   = errors.New("vector search failed")
+	ErrLLMGenerationFailed  = errors.New("LLM response generation failed")
+	ErrInvalidQuestion      = errors.New("question cannot be empty")
+	ErrDocumentNotFound     = errors.New("document not found")
+	ErrInvalidRequest       = errors.New("invalid request")
+	ErrServiceUnavailable   = errors.New("service temporarily unavailable")
+	ErrVectorDeletionFailed = errors.New("failed to delete vectors from index")
+	// ErrPartialResult wraps a context cancellation/deadline error returned
+	// alongside whatever results were collected before ctx was done, so
+	// callers (e.g. a Lambda handler about to time out) can distinguish
+	// "some data, cut short" from a hard failure and flush it to the user
+	// instead of returning a 504.
+	ErrPartialResult = errors.New("partial result returned before context was done")
 )
 
 // ErrorResponse represents an API error response
@@ -33,17 +43,20 @@ type ErrorDetail struct {
 
 // Error codes
 const (
-	ErrCodeInvalidFileType     = "INVALID_FILE_TYPE"
-	ErrCodeFileNotFound        = "FILE_NOT_FOUND"
-	ErrCodeProcessingFailed    = "PROCESSING_FAILED"
-	ErrCodeEmbeddingFailed     = "EMBEDDING_FAILED"
-	ErrCodeVectorSearchFailed  = "VECTOR_SEARCH_FAILED"
-	ErrCodeLLMGenerationFailed = "LLM_GENERATION_FAILED"
-	ErrCodeInvalidQuestion     = "INVALID_QUESTION"
-	ErrCodeDocumentNotFound    = "DOCUMENT_NOT_FOUND"
-	ErrCodeInvalidRequest      = "INVALID_REQUEST"
-	ErrCodeServiceUnavailable  = "SERVICE_UNAVAILABLE"
-	ErrCodeInternalError       = "INTERNAL_ERROR"
+	ErrCodeInvalidFileType      = "INVALID_FILE_TYPE"
+	ErrCodeFileNotFound         = "FILE_NOT_FOUND"
+	ErrCodeProcessingFailed     = "PROCESSING_FAILED"
+	ErrCodeEmbeddingFailed      = "EMBEDDING_FAILED"
+	ErrCodeVectorSearchFailed   = "VECTOR_SEARCH_FAILED"
+	ErrCodeLLMGenerationFailed  = "LLM_GENERATION_FAILED"
+	ErrCodeInvalidQuestion      = "INVALID_QUESTION"
+	ErrCodeDocumentNotFound     = "DOCUMENT_NOT_FOUND"
+	ErrCodeInvalidRequest       = "INVALID_REQUEST"
+	ErrCodeServiceUnavailable   = "SERVICE_UNAVAILABLE"
+	ErrCodeInternalError        = "INTERNAL_ERROR"
+	ErrCodeVectorDeletionFailed = "VECTOR_DELETION_FAILED"
+	ErrCodeForbidden            = "FORBIDDEN"
+	ErrCodeRequestTimeout       = "REQUEST_TIMEOUT"
 )
 
 // NewErrorResponse creates a new error response
@@ -80,11 +93,65 @@ func NewErrorResponseFromError(err error) *ErrorResponse {
 		return NewErrorResponse(ErrCodeInvalidRequest, err.Error(), "")
 	case ErrServiceUnavailable:
 		return NewErrorResponse(ErrCodeServiceUnavailable, err.Error(), "")
+	case ErrVectorDeletionFailed:
+		return NewErrorResponse(ErrCodeVectorDeletionFailed, err.Error(), "")
 	default:
 		return NewErrorResponse(ErrCodeInternalError, "Internal server error", err.Error())
 	}
 }
 
+// AWSErrorMapping bundles the HTTP status code, JSON error body, and (when set) the
+// Retry-After header value that an AWS error should be surfaced as to API clients.
+type AWSErrorMapping struct {
+	StatusCode int
+	Response   *ErrorResponse
+	RetryAfter string
+}
+
+// MapAWSError inspects err for an underlying awserr.Error (unwrapping as needed) and
+// maps its code to the HTTP status an API client should see, following the
+// typed-error-code convention mature S3-compatible servers use instead of collapsing
+// every failure to a blanket 500. Errors that aren't AWS errors fall back to
+// NewErrorResponseFromError's generic internal-error handling.
+func MapAWSError(err error) *AWSErrorMapping {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return &AWSErrorMapping{
+			StatusCode: http.StatusInternalServerError,
+			Response:   NewErrorResponseFromError(err),
+		}
+	}
+
+	switch awsErr.Code() {
+	case "NoSuchKey", "NotFound":
+		return &AWSErrorMapping{
+			StatusCode: http.StatusNotFound,
+			Response:   NewErrorResponse(ErrCodeDocumentNotFound, awsErr.Message(), ""),
+		}
+	case "AccessDenied", "Forbidden":
+		return &AWSErrorMapping{
+			StatusCode: http.StatusForbidden,
+			Response:   NewErrorResponse(ErrCodeForbidden, awsErr.Message(), ""),
+		}
+	case "SlowDown", "RequestLimitExceeded":
+		return &AWSErrorMapping{
+			StatusCode: http.StatusServiceUnavailable,
+			Response:   NewErrorResponse(ErrCodeServiceUnavailable, awsErr.Message(), ""),
+			RetryAfter: "5",
+		}
+	case "RequestTimeout":
+		return &AWSErrorMapping{
+			StatusCode: http.StatusRequestTimeout,
+			Response:   NewErrorResponse(ErrCodeRequestTimeout, awsErr.Message(), ""),
+		}
+	default:
+		return &AWSErrorMapping{
+			StatusCode: http.StatusInternalServerError,
+			Response:   NewErrorResponse(ErrCodeInternalError, "Internal server error", awsErr.Message()),
+		}
+	}
+}
+
 // ProcessingError wraps processing errors with additional context
 type ProcessingError struct {
 	DocumentID string
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func TestMapAWSError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantStatusCode int
+		wantErrorCode  string
+		wantRetryAfter string
+	}{
+		{
+			name:           "no such key maps to document not found",
+			err:            awserr.New("NoSuchKey", "key does not exist", nil),
+			wantStatusCode: http.StatusNotFound,
+			wantErrorCode:  ErrCodeDocumentNotFound,
+		},
+		{
+			name:           "not found maps to document not found",
+			err:            awserr.New("NotFound", "not found", nil),
+			wantStatusCode: http.StatusNotFound,
+			wantErrorCode:  ErrCodeDocumentNotFound,
+		},
+		{
+			name:           "access denied maps to forbidden",
+			err:            awserr.New("AccessDenied", "access denied", nil),
+			wantStatusCode: http.StatusForbidden,
+			wantErrorCode:  ErrCodeForbidden,
+		},
+		{
+			name:           "slow down maps to service unavailable with retry-after",
+			err:            awserr.New("SlowDown", "please slow down", nil),
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantErrorCode:  ErrCodeServiceUnavailable,
+			wantRetryAfter: "5",
+		},
+		{
+			name:           "request limit exceeded maps to service unavailable with retry-after",
+			err:            awserr.New("RequestLimitExceeded", "too many requests", nil),
+			wantStatusCode: http.StatusServiceUnavailable,
+			wantErrorCode:  ErrCodeServiceUnavailable,
+			wantRetryAfter: "5",
+		},
+		{
+			name:           "request timeout maps to 408",
+			err:            awserr.New("RequestTimeout", "timed out", nil),
+			wantStatusCode: http.StatusRequestTimeout,
+			wantErrorCode:  ErrCodeRequestTimeout,
+		},
+		{
+			name:           "unrecognized AWS error code maps to internal error",
+			err:            awserr.New("InternalError", "something broke", nil),
+			wantStatusCode: http.StatusInternalServerError,
+			wantErrorCode:  ErrCodeInternalError,
+		},
+		{
+			name:           "wrapped AWS error is unwrapped",
+			err:            fmt.Errorf("failed to delete object: %w", awserr.New("AccessDenied", "access denied", nil)),
+			wantStatusCode: http.StatusForbidden,
+			wantErrorCode:  ErrCodeForbidden,
+		},
+		{
+			name:           "non-AWS error falls back to generic internal error",
+			err:            errors.New("some other failure"),
+			wantStatusCode: http.StatusInternalServerError,
+			wantErrorCode:  ErrCodeInternalError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping := MapAWSError(tt.err)
+
+			if mapping.StatusCode != tt.wantStatusCode {
+				t.Errorf("got StatusCode %d, want %d", mapping.StatusCode, tt.wantStatusCode)
+			}
+			if mapping.Response.Error.Code != tt.wantErrorCode {
+				t.Errorf("got error code %q, want %q", mapping.Response.Error.Code, tt.wantErrorCode)
+			}
+			if mapping.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("got RetryAfter %q, want %q", mapping.RetryAfter, tt.wantRetryAfter)
+			}
+		})
+	}
+}
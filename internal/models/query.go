@@ -12,6 +12,17 @@ type QueryRequest struct {
 type QueryResponse struct {
 	Answer  string   `json:"answer"`
 	Sources []Source `json:"sources"`
+
+	// Usage is not set by any handler: POST /query answers with its own response
+	// type, and POST /query/stream reports prompt/completion token counts in its
+	// SSE "done" event rather than in a QueryResponse.
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// Usage reports token accounting for a streamed answer.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
 }
 
 // Source represents a source document chunk used in the response
@@ -21,6 +32,16 @@ type Source struct {
 	Content    string                 `json:"content"`
 	Score      float64                `json:"score"`
 	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+
+	// Embedding is the vector VectorSearch scored this source against the
+	// query with. It's never serialized to API callers.
+	Embedding []float32 `json:"-"`
+
+	// StartMS and EndMS locate this source within its source media, in
+	// milliseconds, when it was produced from an audio/video transcript (see
+	// package transcribe). nil for sources produced from plain text.
+	StartMS *int64 `json:"start_ms,omitempty"`
+	EndMS   *int64 `json:"end_ms,omitempty"`
 }
 
 // DefaultMaxResults is the default maximum number of results to return
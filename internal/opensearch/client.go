@@ -0,0 +1,66 @@
+// Package opensearch centralizes OpenSearch client construction — endpoint, index
+// name, and SigV4 request signing — so handlers don't each hand-roll their own
+// v4.Signer call or hardcode the index name.
+package opensearch
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	opensearchgo "github.com/opensearch-project/opensearch-go/v2"
+	ossigner "github.com/opensearch-project/opensearch-go/v2/signer/aws"
+
+	"aws-serverless-rag/internal/utils"
+)
+
+// Config resolves the pieces every OpenSearch-backed handler needs.
+type Config struct {
+	Endpoint  string
+	IndexName string
+	// Service is the SigV4 service name to sign requests for: ossigner.OpenSearchService
+	// ("es") for managed OpenSearch, or ossigner.OpenSearchServerless ("aoss") for
+	// OpenSearch Serverless collections.
+	Service string
+}
+
+// NewConfigFromEnv resolves the endpoint via utils.GetOpenSearchEndpoint, derives the
+// index name from utils.GetEnvironment (matching the "rag-documents-<env>" convention),
+// and reads the signer service from OPENSEARCH_SERVICE, defaulting to managed
+// OpenSearch so existing deployments keep working unchanged.
+func NewConfigFromEnv() (*Config, error) {
+	endpoint := utils.GetOpenSearchEndpoint()
+	if endpoint == "" {
+		return nil, fmt.Errorf("OPENSEARCH_ENDPOINT environment variable not set")
+	}
+
+	service := os.Getenv("OPENSEARCH_SERVICE")
+	if service == "" {
+		service = ossigner.OpenSearchService
+	}
+
+	return &Config{
+		Endpoint:  endpoint,
+		IndexName: fmt.Sprintf("rag-documents-%s", utils.GetEnvironment()),
+		Service:   service,
+	}, nil
+}
+
+// NewClient builds a SigV4-signed OpenSearch client for cfg using sess's region and
+// credentials.
+func NewClient(sess *session.Session, cfg *Config) (*opensearchgo.Client, error) {
+	signer, err := ossigner.NewSignerWithService(session.Options{Config: *sess.Config}, cfg.Service)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenSearch request signer: %w", err)
+	}
+
+	client, err := opensearchgo.NewClient(opensearchgo.Config{
+		Addresses: []string{fmt.Sprintf("https://%s", cfg.Endpoint)},
+		Signer:    signer,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenSearch client: %w", err)
+	}
+
+	return client, nil
+}
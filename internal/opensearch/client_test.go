@@ -0,0 +1,65 @@
+package opensearch
+
+import (
+	"os"
+	"testing"
+
+	ossigner "github.com/opensearch-project/opensearch-go/v2/signer/aws"
+)
+
+func TestNewConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name            string
+		endpoint        string
+		environment     string
+		service         string
+		wantErr         bool
+		wantIndexName   string
+		wantServiceName string
+	}{
+		{
+			name:            "defaults to managed OpenSearch and dev index",
+			endpoint:        "search-domain.us-east-1.es.amazonaws.com",
+			wantIndexName:   "rag-documents-dev",
+			wantServiceName: ossigner.OpenSearchService,
+		},
+		{
+			name:            "honors ENVIRONMENT and OPENSEARCH_SERVICE",
+			endpoint:        "search-domain.us-east-1.es.amazonaws.com",
+			environment:     "prod",
+			service:         ossigner.OpenSearchServerless,
+			wantIndexName:   "rag-documents-prod",
+			wantServiceName: ossigner.OpenSearchServerless,
+		},
+		{
+			name:    "missing endpoint is an error",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OPENSEARCH_ENDPOINT", tt.endpoint)
+			t.Setenv("ENVIRONMENT", tt.environment)
+			t.Setenv("OPENSEARCH_SERVICE", tt.service)
+			defer os.Unsetenv("OPENSEARCH_ENDPOINT")
+
+			cfg, err := NewConfigFromEnv()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cfg.IndexName != tt.wantIndexName {
+				t.Errorf("got IndexName %q, want %q", cfg.IndexName, tt.wantIndexName)
+			}
+			if cfg.Service != tt.wantServiceName {
+				t.Errorf("got Service %q, want %q", cfg.Service, tt.wantServiceName)
+			}
+		})
+	}
+}
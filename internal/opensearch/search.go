@@ -0,0 +1,279 @@
+package opensearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	opensearchgo "github.com/opensearch-project/opensearch-go/v2"
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// rrfK is the rank-constant used by reciprocalRankFusion, matching the value from the
+// original RRF paper (Cormack et al.) and OpenSearch's own hybrid-search default.
+const rrfK = 60
+
+// SearchHit is a single search result, decoupled from any handler's own response type
+// so this package can be shared across handlers with different Source shapes.
+// VectorScore, BM25Score and RRFScore are populated according to which search
+// produced the hit: VectorSearch sets only VectorScore, KeywordSearch sets only
+// BM25Score, and HybridSearch sets whichever components a hit appeared in plus the
+// fused RRFScore.
+type SearchHit struct {
+	ChunkID     string
+	DocumentID  string
+	Content     string
+	Score       float64
+	VectorScore float64
+	BM25Score   float64
+	RRFScore    float64
+}
+
+type knnSearchRequest struct {
+	Size  int                    `json:"size"`
+	Query map[string]interface{} `json:"query"`
+}
+
+type matchSearchRequest struct {
+	Size  int                    `json:"size"`
+	Query map[string]interface{} `json:"query"`
+}
+
+type searchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			ID     string                 `json:"_id"`
+			Score  float64                `json:"_score"`
+			Source map[string]interface{} `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+type msearchResponse struct {
+	Responses []searchResponse `json:"responses"`
+}
+
+// VectorSearch performs a k-NN search against indexName for queryEmbedding and
+// returns the top maxResults hits, with Score and VectorScore set to the raw k-NN
+// similarity score.
+func VectorSearch(ctx context.Context, client *opensearchgo.Client, indexName string, queryEmbedding []float64, maxResults int) ([]SearchHit, error) {
+	if maxResults <= 0 {
+		maxResults = 5
+	}
+
+	request := knnSearchRequest{
+		Size:  maxResults,
+		Query: knnQuery(queryEmbedding, maxResults),
+	}
+
+	result, err := runSearch(ctx, client, indexName, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute search: %w", err)
+	}
+
+	hits := hitsFromResponse(result)
+	for i := range hits {
+		hits[i].VectorScore = hits[i].Score
+	}
+	return hits, nil
+}
+
+// KeywordSearch performs a BM25 match query against the content field for queryText
+// and returns the top maxResults hits, with Score and BM25Score set to the raw BM25
+// relevance score.
+func KeywordSearch(ctx context.Context, client *opensearchgo.Client, indexName string, queryText string, maxResults int) ([]SearchHit, error) {
+	if maxResults <= 0 {
+		maxResults = 5
+	}
+
+	request := matchSearchRequest{
+		Size:  maxResults,
+		Query: matchQuery(queryText),
+	}
+
+	result, err := runSearch(ctx, client, indexName, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute search: %w", err)
+	}
+
+	hits := hitsFromResponse(result)
+	for i := range hits {
+		hits[i].BM25Score = hits[i].Score
+	}
+	return hits, nil
+}
+
+// HybridSearch issues a k-NN search on the vector field and a BM25 match query on the
+// content field as a single _msearch call, then fuses the two ranked lists with
+// Reciprocal Rank Fusion (k=rrfK) and returns the top maxResults hits sorted by
+// RRFScore, descending, with ties broken by ChunkID.
+func HybridSearch(ctx context.Context, client *opensearchgo.Client, indexName string, queryEmbedding []float64, queryText string, maxResults int) ([]SearchHit, error) {
+	if maxResults <= 0 {
+		maxResults = 5
+	}
+
+	var body strings.Builder
+	header, err := json.Marshal(map[string]interface{}{"index": indexName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal msearch header: %w", err)
+	}
+
+	knnBody, err := json.Marshal(knnSearchRequest{Size: maxResults, Query: knnQuery(queryEmbedding, maxResults)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal knn query: %w", err)
+	}
+	matchBody, err := json.Marshal(matchSearchRequest{Size: maxResults, Query: matchQuery(queryText)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal match query: %w", err)
+	}
+
+	body.Write(header)
+	body.WriteByte('\n')
+	body.Write(knnBody)
+	body.WriteByte('\n')
+	body.Write(header)
+	body.WriteByte('\n')
+	body.Write(matchBody)
+	body.WriteByte('\n')
+
+	req := opensearchapi.MsearchRequest{
+		Body: strings.NewReader(body.String()),
+	}
+
+	res, err := req.Do(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute msearch: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("msearch failed: %s", res.Status())
+	}
+
+	var result msearchResponse
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode msearch response: %w", err)
+	}
+	if len(result.Responses) != 2 {
+		return nil, fmt.Errorf("expected 2 msearch responses, got %d", len(result.Responses))
+	}
+
+	vectorHits := hitsFromResponse(result.Responses[0])
+	keywordHits := hitsFromResponse(result.Responses[1])
+
+	return reciprocalRankFusion(vectorHits, keywordHits, maxResults), nil
+}
+
+func knnQuery(queryEmbedding []float64, k int) map[string]interface{} {
+	return map[string]interface{}{
+		"knn": map[string]interface{}{
+			"vector": map[string]interface{}{
+				"vector": queryEmbedding,
+				"k":      k,
+			},
+		},
+	}
+}
+
+func matchQuery(queryText string) map[string]interface{} {
+	return map[string]interface{}{
+		"match": map[string]interface{}{
+			"content": queryText,
+		},
+	}
+}
+
+func runSearch(ctx context.Context, client *opensearchgo.Client, indexName string, request interface{}) (searchResponse, error) {
+	requestJSON, err := json.Marshal(request)
+	if err != nil {
+		return searchResponse{}, fmt.Errorf("failed to marshal search request: %w", err)
+	}
+
+	req := opensearchapi.SearchRequest{
+		Index: []string{indexName},
+		Body:  strings.NewReader(string(requestJSON)),
+	}
+
+	res, err := req.Do(ctx, client)
+	if err != nil {
+		return searchResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return searchResponse{}, fmt.Errorf("search failed: %s", res.Status())
+	}
+
+	var result searchResponse
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return searchResponse{}, fmt.Errorf("failed to decode search response: %w", err)
+	}
+	return result, nil
+}
+
+func hitsFromResponse(result searchResponse) []SearchHit {
+	hits := make([]SearchHit, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		searchHit := SearchHit{
+			ChunkID: hit.ID,
+			Score:   hit.Score,
+		}
+		if docID, ok := hit.Source["document_id"].(string); ok {
+			searchHit.DocumentID = docID
+		}
+		if content, ok := hit.Source["content"].(string); ok {
+			searchHit.Content = content
+		}
+		hits = append(hits, searchHit)
+	}
+	return hits
+}
+
+// reciprocalRankFusion merges vectorHits and keywordHits by _id using RRF: for each
+// document d appearing in either ranked list, score(d) = Σ 1/(rrfK + rank_i(d)), with
+// rank_i(d) the 1-based position of d in list i. Hits are deduped by ChunkID, sorted
+// by RRFScore descending, ties broken by ChunkID ascending, and truncated to
+// maxResults.
+func reciprocalRankFusion(vectorHits, keywordHits []SearchHit, maxResults int) []SearchHit {
+	merged := make(map[string]*SearchHit)
+
+	addRanked := func(hits []SearchHit, setScore func(*SearchHit, float64)) {
+		for rank, hit := range hits {
+			existing, ok := merged[hit.ChunkID]
+			if !ok {
+				hitCopy := hit
+				hitCopy.VectorScore = 0
+				hitCopy.BM25Score = 0
+				existing = &hitCopy
+				merged[hit.ChunkID] = existing
+			}
+			setScore(existing, hit.Score)
+			existing.RRFScore += 1.0 / float64(rrfK+rank+1)
+		}
+	}
+
+	addRanked(vectorHits, func(h *SearchHit, score float64) { h.VectorScore = score })
+	addRanked(keywordHits, func(h *SearchHit, score float64) { h.BM25Score = score })
+
+	fused := make([]SearchHit, 0, len(merged))
+	for _, hit := range merged {
+		fused = append(fused, *hit)
+	}
+
+	sort.Slice(fused, func(i, j int) bool {
+		if fused[i].RRFScore != fused[j].RRFScore {
+			return fused[i].RRFScore > fused[j].RRFScore
+		}
+		return fused[i].ChunkID < fused[j].ChunkID
+	})
+
+	if len(fused) > maxResults {
+		fused = fused[:maxResults]
+	}
+	return fused
+}
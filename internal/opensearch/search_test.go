@@ -0,0 +1,73 @@
+package opensearch
+
+import "testing"
+
+func TestReciprocalRankFusion(t *testing.T) {
+	vectorHits := []SearchHit{
+		{ChunkID: "chunk-a", DocumentID: "doc-1", Content: "vector top", Score: 0.9},
+		{ChunkID: "chunk-b", DocumentID: "doc-1", Content: "vector second", Score: 0.8},
+	}
+	keywordHits := []SearchHit{
+		{ChunkID: "chunk-b", DocumentID: "doc-1", Content: "vector second", Score: 12.0},
+		{ChunkID: "chunk-c", DocumentID: "doc-2", Content: "keyword only", Score: 10.0},
+	}
+
+	fused := reciprocalRankFusion(vectorHits, keywordHits, 10)
+
+	if len(fused) != 3 {
+		t.Fatalf("expected 3 fused hits, got %d", len(fused))
+	}
+
+	// chunk-b appears in both lists (rank 2 in vector, rank 1 in keyword) so it must
+	// out-rank chunk-a and chunk-c, which each appear in only one list.
+	if fused[0].ChunkID != "chunk-b" {
+		t.Fatalf("expected chunk-b to rank first, got %q", fused[0].ChunkID)
+	}
+	if fused[0].VectorScore != 0.8 || fused[0].BM25Score != 12.0 {
+		t.Fatalf("expected chunk-b to carry both component scores, got vector=%v bm25=%v", fused[0].VectorScore, fused[0].BM25Score)
+	}
+
+	for _, hit := range fused {
+		if hit.RRFScore <= 0 {
+			t.Fatalf("expected positive RRFScore for %q, got %v", hit.ChunkID, hit.RRFScore)
+		}
+	}
+}
+
+func TestReciprocalRankFusion_BreaksTiesByChunkID(t *testing.T) {
+	// chunk-z and chunk-a each rank first in their own (disjoint) list, so they tie
+	// on RRFScore and must be ordered by ChunkID ascending.
+	vectorHits := []SearchHit{{ChunkID: "chunk-z", Score: 0.9}}
+	keywordHits := []SearchHit{{ChunkID: "chunk-a", Score: 9.0}}
+
+	fused := reciprocalRankFusion(vectorHits, keywordHits, 10)
+
+	if len(fused) != 2 || fused[0].ChunkID != "chunk-a" || fused[1].ChunkID != "chunk-z" {
+		t.Fatalf("expected tie broken by ChunkID ascending, got %+v", fused)
+	}
+}
+
+func TestReciprocalRankFusion_Truncates(t *testing.T) {
+	vectorHits := []SearchHit{
+		{ChunkID: "chunk-a", Score: 0.9},
+		{ChunkID: "chunk-b", Score: 0.8},
+	}
+
+	fused := reciprocalRankFusion(vectorHits, nil, 1)
+
+	if len(fused) != 1 {
+		t.Fatalf("expected maxResults to truncate to 1 hit, got %d", len(fused))
+	}
+}
+
+func TestReciprocalRankFusion_Dedupes(t *testing.T) {
+	hits := []SearchHit{
+		{ChunkID: "chunk-a", Score: 1.0},
+	}
+
+	fused := reciprocalRankFusion(hits, hits, 10)
+
+	if len(fused) != 1 {
+		t.Fatalf("expected dedupe to a single hit, got %d", len(fused))
+	}
+}
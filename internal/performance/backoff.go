@@ -0,0 +1,53 @@
+package performance
+
+import (
+	"math/rand"
+	"time"
+)
+
+// AdaptiveBackoff computes successive decorrelated-jitter sleep durations:
+// sleep = min(Cap, random_between(Base, prevSleep*3)). Unlike
+// reliability.DecorrelatedJitter (a pure function re-derived from the
+// attempt's RetryConfig each call), AdaptiveBackoff is a small stateful
+// helper so CallWithRetry can simply call Next() each retry without
+// threading the previous delay through itself.
+type AdaptiveBackoff struct {
+	Base time.Duration
+	Cap  time.Duration
+
+	prev time.Duration
+}
+
+// Next returns the next sleep duration and records it as prevSleep for the
+// following call.
+func (b *AdaptiveBackoff) Next() time.Duration {
+	base := b.Base
+	if base <= 0 {
+		base = time.Second
+	}
+	cap := b.Cap
+	if cap <= 0 {
+		cap = 30 * time.Second
+	}
+
+	prev := b.prev
+	if prev < base {
+		prev = base
+	}
+
+	lower := int64(base)
+	upper := int64(prev) * 3
+
+	var delay time.Duration
+	if upper <= lower {
+		delay = base
+	} else {
+		delay = time.Duration(lower + rand.Int63n(upper-lower))
+	}
+	if delay > cap {
+		delay = cap
+	}
+
+	b.prev = delay
+	return delay
+}
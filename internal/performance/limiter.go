@@ -0,0 +1,91 @@
+package performance
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter is a token-bucket rate limiter: tokens refill continuously at Rate
+// per second, up to Burst capacity, rather than in fixed windows. Unlike
+// services.tokenBucketLimiter (which paces Bedrock calls to a fixed,
+// operator-configured rate), Limiter's rate is mutable at runtime via
+// Halve/Restore, so CallWithRetry and GenerateEmbeddingsConcurrently can run
+// an AIMD control loop against it: cut the rate on a Throttle verdict, ease it
+// back up on sustained success.
+type Limiter struct {
+	mu      sync.Mutex
+	rate    float64 // tokens added per second
+	burst   float64
+	minRate float64
+	maxRate float64
+	tokens  float64
+	updated time.Time
+}
+
+// NewLimiter creates a Limiter starting with a full bucket (so the first
+// burst calls don't wait) at rate tokens/sec, never adjusted by Halve/Restore
+// outside [rate/8, rate*4].
+func NewLimiter(rate, burst float64) *Limiter {
+	return &Limiter{
+		rate:    rate,
+		burst:   burst,
+		minRate: rate / 8,
+		maxRate: rate * 4,
+		tokens:  burst,
+		updated: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available, or ctx is done, whichever comes
+// first.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		rate := l.rate
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.updated).Seconds()*rate)
+		l.updated = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+
+		wait := time.Duration((1 - l.tokens) / rate * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Rate returns the limiter's current tokens/sec rate.
+func (l *Limiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// Halve multiplicatively decreases the limiter's rate (AIMD's "multiplicative
+// decrease"), down to minRate, in response to a Throttle verdict.
+func (l *Limiter) Halve() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = math.Max(l.minRate, l.rate/2)
+}
+
+// Restore additively increases the limiter's rate (AIMD's "additive
+// increase") by step, up to maxRate, after a successful call.
+func (l *Limiter) Restore(step float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = math.Min(l.maxRate, l.rate+step)
+}
@@ -9,112 +9,177 @@ import (
 
 	"github.com/google/uuid"
 
+	"aws-serverless-rag/internal/backend"
 	"aws-serverless-rag/internal/models"
+	"aws-serverless-rag/internal/transcribe"
 )
 
-// ProcessTextIntoChunks efficiently processes text into chunks with performance optimization
-func ProcessTextIntoChunks(text string) ([]models.Chunk, error) {
-	if len(text) == 0 {
-		return nil, fmt.Errorf("empty text provided")
+// ProcessTranscriptIntoChunks groups a transcript's segments into chunks the same
+// way ProcessTextIntoChunks groups words, except each chunk is additionally
+// stamped with StartTimeMs/EndTimeMs spanning the segments it was built from, so
+// query results can cite a playable offset into the source media.
+func ProcessTranscriptIntoChunks(documentID string, segments []transcribe.Segment) ([]models.Chunk, error) {
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("no segments provided")
 	}
 
 	const maxChunkSize = 1000
-	const overlap = 100
 
 	var chunks []models.Chunk
-	words := strings.Fields(text)
-
-	if len(words) == 0 {
-		return nil, fmt.Errorf("no words found in text")
+	var currentText strings.Builder
+	var currentWordCount int
+	var startMS, endMS int64
+	haveStart := false
+
+	flush := func() {
+		if currentText.Len() == 0 {
+			return
+		}
+		content := currentText.String()
+		metadata := map[string]interface{}{
+			"word_count": currentWordCount,
+			"char_count": len(content),
+		}
+		start := startMS
+		end := endMS
+		chunks = append(chunks, models.Chunk{
+			ID:          uuid.New().String(),
+			DocumentID:  documentID,
+			Content:     content,
+			Metadata:    metadata,
+			ChunkIndex:  len(chunks),
+			CreatedAt:   time.Now(),
+			StartTimeMs: &start,
+			EndTimeMs:   &end,
+		})
+		currentText.Reset()
+		currentWordCount = 0
+		haveStart = false
 	}
 
-	currentChunk := ""
-	wordCount := 0
+	for _, segment := range segments {
+		if strings.TrimSpace(segment.Text) == "" {
+			continue
+		}
 
-	for i, word := range words {
-		// Add word to current chunk
-		if currentChunk == "" {
-			currentChunk = word
-		} else {
-			currentChunk += " " + word
+		if !haveStart {
+			startMS = segment.StartMS
+			haveStart = true
 		}
-		wordCount++
+		endMS = segment.EndMS
 
-		// Check if we should create a new chunk
-		if len(currentChunk) >= maxChunkSize || i == len(words)-1 {
-			if len(currentChunk) > 0 {
-				metadata := map[string]interface{}{
-					"word_count": wordCount,
-					"char_count": len(currentChunk),
-				}
-				chunk := models.Chunk{
-					ID:         uuid.New().String(),
-					DocumentID: "performance-test",
-					Content:    currentChunk,
-					Metadata:   metadata,
-					ChunkIndex: len(chunks),
-					CreatedAt:  time.Now(),
-				}
-				chunks = append(chunks, chunk)
-			}
+		if currentText.Len() > 0 {
+			currentText.WriteString(" ")
+		}
+		currentText.WriteString(segment.Text)
+		currentWordCount += len(strings.Fields(segment.Text))
 
-			// Prepare for next chunk with overlap
-			if i < len(words)-1 && wordCount > overlap/10 {
-				overlapWords := words[max(0, i-overlap/10) : i+1]
-				currentChunk = strings.Join(overlapWords, " ")
-				wordCount = len(overlapWords)
-			} else {
-				currentChunk = ""
-				wordCount = 0
-			}
+		if currentText.Len() >= maxChunkSize {
+			flush()
 		}
 	}
+	flush()
+
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("no content found in segments")
+	}
 
 	return chunks, nil
 }
 
-// GenerateEmbeddingsConcurrently processes multiple chunks concurrently for better performance
-func GenerateEmbeddingsConcurrently(ctx context.Context, chunks []models.Chunk) ([][]float32, error) {
+// defaultEmbeddingRate/defaultEmbeddingBurst seed the Limiter
+// GenerateEmbeddingsConcurrently paces its workers against. They're
+// deliberately generous starting points (tuned down by AIMD on the first
+// Throttle, not up front) since the caller's actual backend.EmbeddingProvider
+// capacity isn't known here.
+const (
+	defaultEmbeddingRate  = 10.0
+	defaultEmbeddingBurst = 10.0
+	// restoreStep is the per-success additive-increase amount applied to a
+	// Limiter's rate by CallWithRetry and GenerateEmbeddingsConcurrently.
+	restoreStep = 0.5
+	// defaultEmbeddingBatchSize is how many chunks' content GenerateEmbeddingsConcurrently
+	// packs into a single provider.Embed call. Providers that support batched
+	// InvokeModel input (see backend.CohereEnglishV3Provider) turn this into one
+	// Bedrock request per batch instead of one per chunk; providers that don't
+	// (Titan) still benefit from fewer, larger limiter waits.
+	defaultEmbeddingBatchSize = 16
+)
+
+// GenerateEmbeddingsConcurrently embeds multiple chunks concurrently against
+// provider, pacing requests through a Limiter instead of a fixed-size
+// semaphore: chunks are grouped into batches of defaultEmbeddingBatchSize and
+// one worker per batch calls limiter.Wait then provider.Embed with every
+// batch's content in a single call, parallelizing across batches rather than
+// issuing one provider.Embed call per chunk. The limiter's rate is halved on a
+// throttling error or nudged back up after a success, so aggregate
+// concurrency self-tunes to what provider can actually sustain rather than a
+// hard-coded worker count. If provider implements backend.DescribedEmbeddingProvider,
+// every chunk's Metadata is stamped with which model embedded it (see
+// backend.DescribedEmbeddingProvider), so CreateIndex can later pick a
+// knn_vector dimension that matches. Returns the embeddings (in chunk order)
+// and the summed TokenUsage across every call.
+func GenerateEmbeddingsConcurrently(ctx context.Context, chunks []models.Chunk, provider backend.EmbeddingProvider) ([][]float32, backend.TokenUsage, error) {
 	if len(chunks) == 0 {
-		return nil, fmt.Errorf("no chunks provided")
+		return nil, backend.TokenUsage{}, fmt.Errorf("no chunks provided")
 	}
 
-	const maxConcurrency = 5
-	semaphore := make(chan struct{}, maxConcurrency)
+	limiter := NewLimiter(defaultEmbeddingRate, defaultEmbeddingBurst)
+
+	type batch struct {
+		indices []int
+		texts   []string
+	}
+	var batches []batch
+	for start := 0; start < len(chunks); start += defaultEmbeddingBatchSize {
+		end := start + defaultEmbeddingBatchSize
+		if end > len(chunks) {
+			end = len(chunks)
+		}
+		b := batch{}
+		for i := start; i < end; i++ {
+			b.indices = append(b.indices, i)
+			b.texts = append(b.texts, chunks[i].Content)
+		}
+		batches = append(batches, b)
+	}
 
 	type result struct {
-		index     int
-		embedding []float32
-		err       error
+		indices    []int
+		embeddings [][]float32
+		usage      backend.TokenUsage
+		err        error
 	}
 
-	results := make(chan result, len(chunks))
+	results := make(chan result, len(batches))
 	var wg sync.WaitGroup
 
-	// Process chunks concurrently
-	for i, chunk := range chunks {
+	for _, b := range batches {
 		wg.Add(1)
-		go func(index int, c models.Chunk) {
+		go func(b batch) {
 			defer wg.Done()
 
-			// Acquire semaphore
-			select {
-			case semaphore <- struct{}{}:
-				defer func() { <-semaphore }()
-			case <-ctx.Done():
-				results <- result{index: index, err: ctx.Err()}
+			if err := limiter.Wait(ctx); err != nil {
+				results <- result{indices: b.indices, err: err}
 				return
 			}
 
-			// Mock embedding generation (replace with actual Bedrock call in real implementation)
-			embedding, err := mockGenerateEmbedding(c.Content)
+			embeddings, usage, err := provider.Embed(ctx, b.texts)
+			if err != nil {
+				if classifyEmbeddingError(err) == Throttle {
+					limiter.Halve()
+				}
+			} else {
+				limiter.Restore(restoreStep)
+			}
 
 			results <- result{
-				index:     index,
-				embedding: embedding,
-				err:       err,
+				indices:    b.indices,
+				embeddings: embeddings,
+				usage:      usage,
+				err:        err,
 			}
-		}(i, chunk)
+		}(b)
 	}
 
 	// Wait for all goroutines to complete
@@ -125,82 +190,152 @@ func GenerateEmbeddingsConcurrently(ctx context.Context, chunks []models.Chunk)
 
 	// Collect results
 	embeddings := make([][]float32, len(chunks))
+	var totalUsage backend.TokenUsage
 	errorCount := 0
 
+	described, _ := provider.(backend.DescribedEmbeddingProvider)
+
 	for result := range results {
 		if result.err != nil {
-			errorCount++
+			errorCount += len(result.indices)
 			if errorCount > len(chunks)/2 { // Fail if more than half fail
-				return nil, fmt.Errorf("too many embedding failures: %w", result.err)
+				return nil, backend.TokenUsage{}, fmt.Errorf("too many embedding failures: %w", result.err)
 			}
 			continue
 		}
-		embeddings[result.index] = result.embedding
+		for i, index := range result.indices {
+			if i >= len(result.embeddings) {
+				break
+			}
+			embeddings[index] = result.embeddings[i]
+			if described != nil {
+				if chunks[index].Metadata == nil {
+					chunks[index].Metadata = make(map[string]interface{})
+				}
+				chunks[index].Metadata["embedding_provider"] = described.Name()
+				chunks[index].Metadata["embedding_dimension"] = described.Dimension()
+			}
+		}
+		totalUsage = totalUsage.Add(result.usage)
 	}
 
-	return embeddings, nil
+	return embeddings, totalUsage, nil
 }
 
-// CallWithRetry implements retry mechanism with exponential backoff
-func CallWithRetry(ctx context.Context, maxRetries int, operation func() error) error {
+// classifyEmbeddingError gives GenerateEmbeddingsConcurrently a RetryDecision
+// for a backend.EmbeddingProvider error without assuming it's an AWS SDK
+// error (a backend.EmbeddingProvider can be Bedrock, a gRPC sidecar, or the
+// in-process mock): a throttling-shaped message halves the limiter's rate,
+// anything else is left as Retryable (GenerateEmbeddingsConcurrently doesn't
+// itself retry a failed chunk, only paces around it).
+func classifyEmbeddingError(err error) RetryDecision {
+	if strings.Contains(strings.ToLower(err.Error()), "throttl") {
+		return Throttle
+	}
+	return Retryable
+}
+
+// RetryDecision classifies an error a RetryPolicy.Classify function returns
+// for CallWithRetry.
+type RetryDecision int
+
+const (
+	// Retryable means the error is transient and worth another attempt.
+	Retryable RetryDecision = iota
+	// Throttle means the error is a rate-limit signal: worth retrying, and
+	// CallWithRetry additionally halves policy.Limiter's rate before the next
+	// attempt.
+	Throttle
+	// Fatal means the error won't go away on retry; CallWithRetry returns it
+	// immediately.
+	Fatal
+)
+
+// RetryPolicy configures CallWithRetry.
+type RetryPolicy struct {
+	MaxRetries int
+	// BaseDelay and CapDelay bound the AdaptiveBackoff between attempts.
+	BaseDelay time.Duration
+	CapDelay  time.Duration
+	// Limiter, if set, is waited on before every attempt (including the
+	// first) and adjusted (Halve/Restore) based on Classify's verdict.
+	Limiter *Limiter
+	// Classify classifies an operation error. Optional; CallWithRetry treats
+	// a nil Classify as "every error is Retryable".
+	Classify func(error) RetryDecision
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with no Limiter, five retries, and
+// every error classified Retryable.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 5,
+		BaseDelay:  time.Second,
+		CapDelay:   10 * time.Second,
+	}
+}
+
+// CallWithRetry runs operation under policy, backing off between attempts
+// with an AdaptiveBackoff (decorrelated jitter: sleep = min(CapDelay,
+// random_between(BaseDelay, prevSleep*3))) instead of the linear
+// attempt*BaseDelay schedule this function used to use. If policy.Limiter is
+// set, CallWithRetry waits on it before every attempt, halves its rate on a
+// Throttle verdict, and restores it by restoreStep after a success, so a
+// shared Limiter's rate adapts to the error rate the caller is actually
+// seeing (AIMD).
+func CallWithRetry(ctx context.Context, policy RetryPolicy, operation func() error) error {
+	classify := policy.Classify
+	if classify == nil {
+		classify = func(error) RetryDecision { return Retryable }
+	}
+
+	backoff := AdaptiveBackoff{Base: policy.BaseDelay, Cap: policy.CapDelay}
+
 	var lastErr error
 
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		// Check context cancellation
+	for attempt := 0; attempt <= policy.MaxRetries; attempt++ {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
 
-		// Execute operation
+		if policy.Limiter != nil {
+			if err := policy.Limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
 		err := operation()
 		if err == nil {
-			return nil // Success
+			if policy.Limiter != nil {
+				policy.Limiter.Restore(restoreStep)
+			}
+			return nil
 		}
 
 		lastErr = err
+		decision := classify(err)
 
-		// Don't wait after the last attempt
-		if attempt >= maxRetries {
-			break
+		if decision == Fatal {
+			return err
+		}
+		if decision == Throttle && policy.Limiter != nil {
+			policy.Limiter.Halve()
 		}
 
-		// Exponential backoff with jitter
-		backoff := time.Duration(attempt+1) * time.Second
-		if backoff > 10*time.Second {
-			backoff = 10 * time.Second
+		// Don't wait after the last attempt
+		if attempt >= policy.MaxRetries {
+			break
 		}
 
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(backoff):
+		case <-time.After(backoff.Next()):
 			// Continue to next retry
 		}
 	}
 
-	return fmt.Errorf("operation failed after %d retries: %w", maxRetries, lastErr)
-}
-
-// Helper functions
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
-
-// Mock function for testing - replace with actual Bedrock client call
-func mockGenerateEmbedding(text string) ([]float32, error) {
-	// Simulate some processing time
-	time.Sleep(100 * time.Millisecond)
-
-	// Generate mock embedding vector
-	embedding := make([]float32, 1536) // Titan embedding dimension
-	for i := range embedding {
-		embedding[i] = 0.1 // Mock value
-	}
-
-	return embedding, nil
+	return fmt.Errorf("operation failed after %d retries: %w", policy.MaxRetries, lastErr)
 }
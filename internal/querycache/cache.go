@@ -0,0 +1,143 @@
+// Package querycache provides a two-tier DynamoDB-backed cache for the query
+// Lambda's question embeddings and full answers, keyed by a hash of the normalized
+// question so repeat questions skip Bedrock entirely.
+package querycache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// DefaultTTL is how long cached embeddings and answers live before DynamoDB TTL
+// expires them, used when NewCache isn't given a more specific value via WithTTL.
+const DefaultTTL = 1 * time.Hour
+
+const (
+	embeddingKeyPrefix = "emb:"
+	answerKeyPrefix    = "ans:"
+)
+
+// Cache is a DynamoDB-backed cache for query embeddings and answers. The table needs
+// a string partition key "cache_key" and a DynamoDB TTL configured on "expires_at".
+type Cache struct {
+	client    *dynamodb.DynamoDB
+	tableName string
+	ttl       time.Duration
+}
+
+// NewCache creates a Cache backed by tableName, using DefaultTTL.
+func NewCache(client *dynamodb.DynamoDB, tableName string) *Cache {
+	return &Cache{client: client, tableName: tableName, ttl: DefaultTTL}
+}
+
+// WithTTL overrides the default cache entry lifetime.
+func (c *Cache) WithTTL(ttl time.Duration) *Cache {
+	c.ttl = ttl
+	return c
+}
+
+var whitespacePattern = regexp.MustCompile(`\s+`)
+
+// NormalizeQuestion lowercases and collapses whitespace so equivalent questions
+// share a cache key.
+func NormalizeQuestion(question string) string {
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(strings.ToLower(question), " "))
+}
+
+// QuestionHash returns the hex-encoded SHA-256 of the normalized question, used as
+// the embedding-tier cache key.
+func QuestionHash(question string) string {
+	sum := sha256.Sum256([]byte(NormalizeQuestion(question)))
+	return hex.EncodeToString(sum[:])
+}
+
+// AnswerKey returns the answer-tier cache key. It additionally varies on maxResults
+// and searchMode since both change retrieval and therefore the answer.
+func AnswerKey(question string, maxResults int, searchMode string) string {
+	return fmt.Sprintf("%s:%d:%s", QuestionHash(question), maxResults, searchMode)
+}
+
+// GetEmbedding returns the cached embedding for hash, if present and unexpired.
+func (c *Cache) GetEmbedding(hash string) ([]float64, bool, error) {
+	var embedding []float64
+	hit, err := c.get(embeddingKeyPrefix+hash, &embedding)
+	return embedding, hit, err
+}
+
+// PutEmbedding caches embedding under hash.
+func (c *Cache) PutEmbedding(hash string, embedding []float64) error {
+	return c.put(embeddingKeyPrefix+hash, embedding)
+}
+
+// GetAnswer unmarshals the cached value for key into dest, if present and unexpired.
+func (c *Cache) GetAnswer(key string, dest interface{}) (bool, error) {
+	return c.get(answerKeyPrefix+key, dest)
+}
+
+// PutAnswer caches value under key.
+func (c *Cache) PutAnswer(key string, value interface{}) error {
+	return c.put(answerKeyPrefix+key, value)
+}
+
+func (c *Cache) get(cacheKey string, dest interface{}) (bool, error) {
+	out, err := c.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(c.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"cache_key": {S: aws.String(cacheKey)},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to read cache entry: %w", err)
+	}
+	if out.Item == nil {
+		return false, nil
+	}
+
+	expiresAttr, ok := out.Item["expires_at"]
+	if ok && expiresAttr.N != nil {
+		expiresAt, err := strconv.ParseInt(*expiresAttr.N, 10, 64)
+		// DynamoDB's TTL sweeper can lag behind the expiry time by hours, so also
+		// treat an expired-but-not-yet-deleted item as a miss.
+		if err == nil && time.Now().Unix() >= expiresAt {
+			return false, nil
+		}
+	}
+
+	payloadAttr, ok := out.Item["payload"]
+	if !ok || payloadAttr.S == nil {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(*payloadAttr.S), dest); err != nil {
+		return false, fmt.Errorf("failed to unmarshal cache entry: %w", err)
+	}
+	return true, nil
+}
+
+func (c *Cache) put(cacheKey string, value interface{}) error {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	_, err = c.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(c.tableName),
+		Item: map[string]*dynamodb.AttributeValue{
+			"cache_key":  {S: aws.String(cacheKey)},
+			"payload":    {S: aws.String(string(payload))},
+			"expires_at": {N: aws.String(strconv.FormatInt(time.Now().Add(c.ttl).Unix(), 10))},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	return nil
+}
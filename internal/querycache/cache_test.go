@@ -0,0 +1,47 @@
+package querycache
+
+import "testing"
+
+func TestNormalizeQuestion(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "lowercases", input: "What IS AI?", want: "what is ai?"},
+		{name: "collapses whitespace", input: "what   is\tai", want: "what is ai"},
+		{name: "trims ends", input: "  what is ai  ", want: "what is ai"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeQuestion(tt.input); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuestionHash_StableAcrossEquivalentPhrasing(t *testing.T) {
+	a := QuestionHash("What is AI?")
+	b := QuestionHash("  what   is ai?  ")
+
+	if a != b {
+		t.Fatalf("expected equivalent questions to hash the same, got %q vs %q", a, b)
+	}
+
+	if c := QuestionHash("What is ML?"); c == a {
+		t.Fatalf("expected different questions to hash differently")
+	}
+}
+
+func TestAnswerKey_VariesOnRetrievalParams(t *testing.T) {
+	base := AnswerKey("What is AI?", 5, "hybrid")
+
+	if AnswerKey("What is AI?", 10, "hybrid") == base {
+		t.Fatal("expected different maxResults to change the answer key")
+	}
+	if AnswerKey("What is AI?", 5, "vector") == base {
+		t.Fatal("expected different searchMode to change the answer key")
+	}
+}
@@ -3,30 +3,113 @@ package reliability
 import (
 	"context"
 	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
 	"time"
 
-	"aws-serverless-rag/internal/utils"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// Logger is the subset of utils.Logger's API this package needs. Declaring it here
+// instead of depending on internal/utils keeps reliability free to be used by packages
+// utils itself depends on (e.g. AWSConfig's per-service circuit breakers) without an
+// import cycle. *utils.Logger already satisfies this interface.
+type Logger interface {
+	Info(message string, fields ...map[string]interface{})
+	Warn(message string, fields ...map[string]interface{})
+	Error(message string, fields ...map[string]interface{})
+}
+
+// RetryStrategy selects how calculateBackoff spaces out retries.
+type RetryStrategy int
+
+const (
+	// Exponential doubles (BackoffFactor^attempt) the delay each attempt, capped at MaxDelay.
+	Exponential RetryStrategy = iota
+	// Constant always waits BaseDelay between attempts.
+	Constant
+	// DecorrelatedJitter follows AWS's recommended recurrence:
+	// sleep = min(MaxDelay, random_between(BaseDelay, prevDelay*3)). It spreads out
+	// retries from many concurrent callers better than a fixed exponential curve.
+	DecorrelatedJitter
+	// FullJitter follows AWS's other recommended recurrence:
+	// sleep = random_between(0, min(MaxDelay, BaseDelay*BackoffFactor^attempt)). Unlike
+	// DecorrelatedJitter it doesn't depend on the previous delay, so concurrent callers
+	// starting at the same time still spread out from attempt 0.
+	FullJitter
 )
 
+// defaultRetryableCodes are AWS error codes that indicate the caller should back off
+// and retry, collected from the throttling/capacity errors S3, DynamoDB, and Bedrock
+// return under load.
+var defaultRetryableCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"RequestLimitExceeded":                   true,
+	"ProvisionedThroughputExceededException": true,
+	"SlowDown":                               true,
+	"TooManyRequestsException":               true,
+}
+
+// IsRetryableAWSError reports whether err is an AWS SDK error worth retrying, based on
+// its HTTP status code (429/503) or error code. extraCodes lets a caller treat
+// additional service-specific codes as retryable alongside defaultRetryableCodes.
+func IsRetryableAWSError(err error, extraCodes []string) bool {
+	reqErr, ok := err.(awserr.RequestFailure)
+	if !ok {
+		// Not an AWS SDK error we can classify (or not wrapped as one) - retry it,
+		// preserving the previous behavior of retrying plain errors by default.
+		return true
+	}
+
+	if reqErr.StatusCode() == http.StatusTooManyRequests || reqErr.StatusCode() == http.StatusServiceUnavailable {
+		return true
+	}
+
+	if defaultRetryableCodes[reqErr.Code()] {
+		return true
+	}
+
+	for _, code := range extraCodes {
+		if reqErr.Code() == code {
+			return true
+		}
+	}
+
+	return false
+}
+
 // RetryConfig defines configuration for retry operations
 type RetryConfig struct {
-	MaxRetries      int
-	BaseDelay       time.Duration
-	MaxDelay        time.Duration
-	BackoffFactor   float64
+	MaxRetries    int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	BackoffFactor float64
+	Strategy      RetryStrategy
+
+	// IsRetryable classifies an error as retryable. If nil, errors are classified by
+	// RetryableErrors (when set) or by IsRetryableAWSError(err, RetryableCodes) otherwise.
+	IsRetryable func(error) bool
+	// RetryableCodes lists extra AWS error codes (e.g. a service-specific throttling
+	// code) to treat as retryable, on top of IsRetryableAWSError's defaults.
+	RetryableCodes []string
+	// RetryableErrors retries only errors matching one of these by equality. Deprecated
+	// in favor of IsRetryable/RetryableCodes, which understand real AWS SDK errors;
+	// kept for callers that already depend on equality matching against sentinel errors.
 	RetryableErrors []error
 }
 
 // DefaultRetryConfig returns a default retry configuration
 func DefaultRetryConfig() RetryConfig {
 	return RetryConfig{
-		MaxRetries:      3,
-		BaseDelay:       time.Second,
-		MaxDelay:        30 * time.Second,
-		BackoffFactor:   2.0,
-		RetryableErrors: []error{
-			// Add common retryable errors
-		},
+		MaxRetries:    3,
+		BaseDelay:     time.Second,
+		MaxDelay:      30 * time.Second,
+		BackoffFactor: 2.0,
+		Strategy:      Exponential,
 	}
 }
 
@@ -34,8 +117,14 @@ func DefaultRetryConfig() RetryConfig {
 type RetryableOperation func() error
 
 // ExecuteWithRetry executes an operation with retry logic
-func ExecuteWithRetry(ctx context.Context, config RetryConfig, operation RetryableOperation, logger *utils.Logger) error {
+func ExecuteWithRetry(ctx context.Context, config RetryConfig, operation RetryableOperation, logger Logger) error {
 	var lastErr error
+	var prevDelay time.Duration
+
+	isRetryable := config.IsRetryable
+	if isRetryable == nil {
+		isRetryable = defaultIsRetryable(config)
+	}
 
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 		// Check for context cancellation
@@ -59,7 +148,7 @@ func ExecuteWithRetry(ctx context.Context, config RetryConfig, operation Retryab
 		lastErr = err
 
 		// Check if error is retryable
-		if !isRetryableError(err, config.RetryableErrors) {
+		if !isRetryable(err) {
 			if logger != nil {
 				logger.Warn("Non-retryable error encountered", map[string]interface{}{
 					"error":   err.Error(),
@@ -75,7 +164,8 @@ func ExecuteWithRetry(ctx context.Context, config RetryConfig, operation Retryab
 		}
 
 		// Calculate backoff delay
-		delay := calculateBackoff(attempt, config)
+		delay := calculateBackoff(attempt, prevDelay, config)
+		prevDelay = delay
 
 		if logger != nil {
 			logger.Warn("Operation failed, retrying", map[string]interface{}{
@@ -104,31 +194,86 @@ func ExecuteWithRetry(ctx context.Context, config RetryConfig, operation Retryab
 	return fmt.Errorf("operation failed after %d retries: %w", config.MaxRetries, lastErr)
 }
 
-// calculateBackoff calculates the backoff delay for a given attempt
-func calculateBackoff(attempt int, config RetryConfig) time.Duration {
-	delay := float64(config.BaseDelay) * float64(attempt+1) * config.BackoffFactor
+// calculateBackoff calculates the delay before the next attempt, given the delay
+// used for the previous attempt (0 if this is the first retry).
+func calculateBackoff(attempt int, prevDelay time.Duration, config RetryConfig) time.Duration {
+	switch config.Strategy {
+	case Constant:
+		return capDelay(config.BaseDelay, config.MaxDelay)
+	case DecorrelatedJitter:
+		return capDelay(decorrelatedJitterDelay(prevDelay, config), config.MaxDelay)
+	case FullJitter:
+		return fullJitterDelay(attempt, config)
+	default: // Exponential
+		factor := config.BackoffFactor
+		if factor <= 0 {
+			factor = 2.0
+		}
+		delay := float64(config.BaseDelay) * math.Pow(factor, float64(attempt))
+		return capDelay(time.Duration(delay), config.MaxDelay)
+	}
+}
+
+// decorrelatedJitterDelay implements AWS's decorrelated jitter recurrence:
+// sleep = random_between(BaseDelay, prevDelay*3). See
+// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/.
+func decorrelatedJitterDelay(prevDelay time.Duration, config RetryConfig) time.Duration {
+	base := config.BaseDelay
+	if prevDelay < base {
+		prevDelay = base
+	}
 
-	if time.Duration(delay) > config.MaxDelay {
-		delay = float64(config.MaxDelay)
+	upper := int64(prevDelay) * 3
+	lower := int64(base)
+	if upper <= lower {
+		return base
 	}
 
-	return time.Duration(delay)
+	return time.Duration(lower + rand.Int63n(upper-lower))
 }
 
-// isRetryableError checks if an error is retryable based on the configuration
-func isRetryableError(err error, retryableErrors []error) bool {
-	if len(retryableErrors) == 0 {
-		// If no specific retryable errors defined, retry all errors
-		return true
+// fullJitterDelay implements AWS's full jitter recurrence:
+// sleep = random_between(0, min(MaxDelay, BaseDelay*BackoffFactor^attempt)). See
+// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/.
+func fullJitterDelay(attempt int, config RetryConfig) time.Duration {
+	factor := config.BackoffFactor
+	if factor <= 0 {
+		factor = 2.0
 	}
 
-	for _, retryable := range retryableErrors {
-		if err == retryable {
-			return true
+	cap := capDelay(time.Duration(float64(config.BaseDelay)*math.Pow(factor, float64(attempt))), config.MaxDelay)
+	if cap <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(cap)))
+}
+
+func capDelay(delay, max time.Duration) time.Duration {
+	if max > 0 && delay > max {
+		return max
+	}
+	return delay
+}
+
+// defaultIsRetryable returns the error classifier ExecuteWithRetry falls back to when
+// config.IsRetryable is nil: equality matching against RetryableErrors when the caller
+// set it (the old behavior), otherwise AWS-aware classification via IsRetryableAWSError.
+func defaultIsRetryable(config RetryConfig) func(error) bool {
+	if len(config.RetryableErrors) > 0 {
+		return func(err error) bool {
+			for _, retryable := range config.RetryableErrors {
+				if err == retryable {
+					return true
+				}
+			}
+			return false
 		}
 	}
 
-	return false
+	return func(err error) bool {
+		return IsRetryableAWSError(err, config.RetryableCodes)
+	}
 }
 
 // Circuit breaker implementation for preventing cascading failures
@@ -136,6 +281,9 @@ type CircuitBreakerConfig struct {
 	MaxFailures     int
 	ResetTimeout    time.Duration
 	MonitorInterval time.Duration
+	// HalfOpenMaxProbes caps how many trial requests are admitted while the breaker is
+	// half-open. Defaults to 1 (the most conservative choice) if unset.
+	HalfOpenMaxProbes int
 }
 
 type CircuitBreakerState int
@@ -146,16 +294,34 @@ const (
 	StateHalfOpen
 )
 
+// CircuitBreakerStats is a point-in-time snapshot of a CircuitBreaker's counters,
+// returned by Stats() for health checks and dashboards.
+type CircuitBreakerStats struct {
+	State     CircuitBreakerState
+	Failures  int
+	Successes int
+}
+
+// CircuitBreaker trips open after MaxFailures consecutive failures, rejecting calls
+// until ResetTimeout elapses, then admits a bounded number of half-open trial calls
+// before deciding whether to close again or reopen. Safe for concurrent use.
 type CircuitBreaker struct {
-	config       CircuitBreakerConfig
-	state        CircuitBreakerState
-	failures     int
-	lastFailTime time.Time
-	logger       *utils.Logger
+	mu sync.Mutex
+
+	config         CircuitBreakerConfig
+	state          CircuitBreakerState
+	failures       int
+	successes      int
+	lastFailTime   time.Time
+	halfOpenProbes int
+	logger         Logger
 }
 
-// NewCircuitBreaker creates a new circuit breaker
-func NewCircuitBreaker(config CircuitBreakerConfig, logger *utils.Logger) *CircuitBreaker {
+// NewCircuitBreaker creates a new circuit breaker. logger may be nil.
+func NewCircuitBreaker(config CircuitBreakerConfig, logger Logger) *CircuitBreaker {
+	if config.HalfOpenMaxProbes <= 0 {
+		config.HalfOpenMaxProbes = 1
+	}
 	return &CircuitBreaker{
 		config: config,
 		state:  StateClosed,
@@ -163,39 +329,98 @@ func NewCircuitBreaker(config CircuitBreakerConfig, logger *utils.Logger) *Circu
 	}
 }
 
-// Execute executes an operation through the circuit breaker
+// Execute runs operation through the circuit breaker, rejecting it outright if the
+// breaker is open or if it's half-open and already at its probe limit.
 func (cb *CircuitBreaker) Execute(operation RetryableOperation) error {
-	if cb.state == StateOpen {
+	if err := cb.admit(); err != nil {
+		return err
+	}
+
+	err := operation()
+	cb.recordResult(err)
+	return err
+}
+
+// Stats returns a snapshot of the breaker's current state and counters.
+func (cb *CircuitBreaker) Stats() CircuitBreakerStats {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	return CircuitBreakerStats{State: cb.state, Failures: cb.failures, Successes: cb.successes}
+}
+
+// admit decides whether a call may proceed, transitioning Open->HalfOpen once
+// ResetTimeout has elapsed and counting the half-open probe it admits.
+func (cb *CircuitBreaker) admit() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case StateOpen:
 		if time.Since(cb.lastFailTime) < cb.config.ResetTimeout {
 			return fmt.Errorf("circuit breaker is open")
 		}
-		// Transition to half-open
 		cb.state = StateHalfOpen
-		cb.logger.Info("Circuit breaker transitioning to half-open state")
+		cb.halfOpenProbes = 0
+		cb.logInfo("Circuit breaker transitioning to half-open state", nil)
+		fallthrough
+	case StateHalfOpen:
+		if cb.halfOpenProbes >= cb.config.HalfOpenMaxProbes {
+			return fmt.Errorf("circuit breaker is half-open and at its probe limit")
+		}
+		cb.halfOpenProbes++
 	}
 
-	err := operation()
+	return nil
+}
+
+// recordResult applies the outcome of an admitted call to the breaker's state.
+func (cb *CircuitBreaker) recordResult(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	wasHalfOpenProbe := cb.state == StateHalfOpen
+	if wasHalfOpenProbe {
+		cb.halfOpenProbes--
+	}
 
 	if err != nil {
 		cb.failures++
 		cb.lastFailTime = time.Now()
 
-		if cb.failures >= cb.config.MaxFailures && cb.state != StateOpen {
+		switch {
+		case wasHalfOpenProbe:
+			// A single failed probe is enough to decide the dependency isn't healthy yet.
+			cb.state = StateOpen
+			cb.logWarn("Circuit breaker reopened after half-open probe failure", map[string]interface{}{
+				"failures": cb.failures,
+			})
+		case cb.state != StateOpen && cb.failures >= cb.config.MaxFailures:
 			cb.state = StateOpen
-			cb.logger.Warn("Circuit breaker opened due to failures", map[string]interface{}{
+			cb.logWarn("Circuit breaker opened due to failures", map[string]interface{}{
 				"failures": cb.failures,
 			})
 		}
-
-		return err
+		return
 	}
 
-	// Operation succeeded
-	if cb.state == StateHalfOpen {
+	cb.successes++
+	cb.failures = 0
+	if wasHalfOpenProbe {
 		cb.state = StateClosed
 		cb.failures = 0
-		cb.logger.Info("Circuit breaker closed after successful operation")
+		cb.logInfo("Circuit breaker closed after successful operation", nil)
 	}
+}
 
-	return nil
+func (cb *CircuitBreaker) logInfo(message string, fields map[string]interface{}) {
+	if cb.logger != nil {
+		cb.logger.Info(message, fields)
+	}
+}
+
+func (cb *CircuitBreaker) logWarn(message string, fields map[string]interface{}) {
+	if cb.logger != nil {
+		cb.logger.Warn(message, fields)
+	}
 }
@@ -3,10 +3,11 @@ package reliability
 import (
 	"context"
 	"errors"
+	"math"
 	"testing"
 	"time"
 
-	"aws-serverless-rag/internal/utils"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 )
 
 func TestExecuteWithRetry_Success(t *testing.T) {
@@ -14,8 +15,6 @@ func TestExecuteWithRetry_Success(t *testing.T) {
 	config.MaxRetries = 2
 	config.BaseDelay = 10 * time.Millisecond
 
-	logger := utils.NewLogger()
-
 	callCount := 0
 	operation := func() error {
 		callCount++
@@ -25,7 +24,7 @@ func TestExecuteWithRetry_Success(t *testing.T) {
 		return nil // Success on third attempt
 	}
 
-	err := ExecuteWithRetry(context.Background(), config, operation, logger)
+	err := ExecuteWithRetry(context.Background(), config, operation, nil)
 
 	if err != nil {
 		t.Errorf("Expected success but got error: %v", err)
@@ -41,13 +40,11 @@ func TestExecuteWithRetry_Failure(t *testing.T) {
 	config.MaxRetries = 2
 	config.BaseDelay = 10 * time.Millisecond
 
-	logger := utils.NewLogger()
-
 	operation := func() error {
 		return errors.New("persistent failure")
 	}
 
-	err := ExecuteWithRetry(context.Background(), config, operation, logger)
+	err := ExecuteWithRetry(context.Background(), config, operation, nil)
 
 	if err == nil {
 		t.Error("Expected error but got success")
@@ -59,8 +56,6 @@ func TestExecuteWithRetry_ContextCancellation(t *testing.T) {
 	config.MaxRetries = 5
 	config.BaseDelay = 100 * time.Millisecond
 
-	logger := utils.NewLogger()
-
 	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
 	defer cancel()
 
@@ -68,7 +63,7 @@ func TestExecuteWithRetry_ContextCancellation(t *testing.T) {
 		return errors.New("failure")
 	}
 
-	err := ExecuteWithRetry(ctx, config, operation, logger)
+	err := ExecuteWithRetry(ctx, config, operation, nil)
 
 	if err != context.DeadlineExceeded {
 		t.Errorf("Expected context deadline exceeded, got %v", err)
@@ -81,8 +76,7 @@ func TestCircuitBreaker_Open(t *testing.T) {
 		ResetTimeout: 100 * time.Millisecond,
 	}
 
-	logger := utils.NewLogger()
-	cb := NewCircuitBreaker(config, logger)
+	cb := NewCircuitBreaker(config, nil)
 
 	failingOperation := func() error {
 		return errors.New("operation failed")
@@ -109,8 +103,7 @@ func TestCircuitBreaker_Recovery(t *testing.T) {
 		ResetTimeout: 50 * time.Millisecond,
 	}
 
-	logger := utils.NewLogger()
-	cb := NewCircuitBreaker(config, logger)
+	cb := NewCircuitBreaker(config, nil)
 
 	// Trigger failures to open circuit breaker
 	for i := 0; i < 2; i++ {
@@ -138,30 +131,154 @@ func TestCircuitBreaker_Recovery(t *testing.T) {
 	}
 }
 
-func TestCalculateBackoff(t *testing.T) {
+func TestCircuitBreaker_HalfOpenRejectsBeyondProbeLimit(t *testing.T) {
+	config := CircuitBreakerConfig{
+		MaxFailures:       1,
+		ResetTimeout:      10 * time.Millisecond,
+		HalfOpenMaxProbes: 1,
+	}
+
+	cb := NewCircuitBreaker(config, nil)
+
+	cb.Execute(func() error { return errors.New("failure") })
+	time.Sleep(20 * time.Millisecond)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go cb.Execute(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	// A second call arriving while the first half-open probe is still in flight must
+	// be rejected outright rather than admitted as a concurrent probe.
+	if err := cb.Execute(func() error { return nil }); err == nil {
+		t.Error("expected second concurrent half-open call to be rejected")
+	}
+	close(release)
+}
+
+func TestCircuitBreaker_Stats(t *testing.T) {
+	cb := NewCircuitBreaker(CircuitBreakerConfig{MaxFailures: 5, ResetTimeout: time.Second}, nil)
+
+	cb.Execute(func() error { return nil })
+	cb.Execute(func() error { return errors.New("failure") })
+
+	stats := cb.Stats()
+	if stats.Successes != 1 || stats.Failures != 1 || stats.State != StateClosed {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCalculateBackoff_Exponential(t *testing.T) {
 	config := RetryConfig{
 		BaseDelay:     time.Second,
 		MaxDelay:      10 * time.Second,
 		BackoffFactor: 2.0,
+		Strategy:      Exponential,
 	}
 
 	tests := []struct {
-		attempt     int
-		expectedMin time.Duration
-		expectedMax time.Duration
+		attempt  int
+		expected time.Duration
 	}{
-		{0, time.Second, 3 * time.Second},
-		{1, 3 * time.Second, 5 * time.Second},
-		{2, 5 * time.Second, 10 * time.Second},
-		{10, 10 * time.Second, 10 * time.Second}, // Should be capped at MaxDelay
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{10, 10 * time.Second}, // Should be capped at MaxDelay
 	}
 
 	for _, tt := range tests {
-		delay := calculateBackoff(tt.attempt, config)
+		if delay := calculateBackoff(tt.attempt, 0, config); delay != tt.expected {
+			t.Errorf("Attempt %d: expected %v, got %v", tt.attempt, tt.expected, delay)
+		}
+	}
+}
+
+func TestCalculateBackoff_Constant(t *testing.T) {
+	config := RetryConfig{
+		BaseDelay: 2 * time.Second,
+		MaxDelay:  10 * time.Second,
+		Strategy:  Constant,
+	}
 
-		if delay < tt.expectedMin || delay > tt.expectedMax {
-			t.Errorf("Attempt %d: expected delay between %v and %v, got %v",
-				tt.attempt, tt.expectedMin, tt.expectedMax, delay)
+	for attempt := 0; attempt < 4; attempt++ {
+		if delay := calculateBackoff(attempt, 5*time.Second, config); delay != 2*time.Second {
+			t.Errorf("Attempt %d: expected constant 2s delay, got %v", attempt, delay)
 		}
 	}
 }
+
+func TestCalculateBackoff_DecorrelatedJitter(t *testing.T) {
+	config := RetryConfig{
+		BaseDelay: time.Second,
+		MaxDelay:  10 * time.Second,
+		Strategy:  DecorrelatedJitter,
+	}
+
+	prevDelay := time.Duration(0)
+	for i := 0; i < 20; i++ {
+		delay := calculateBackoff(0, prevDelay, config)
+		if delay < config.BaseDelay || delay > config.MaxDelay {
+			t.Fatalf("round %d: delay %v outside [%v, %v]", i, delay, config.BaseDelay, config.MaxDelay)
+		}
+		prevDelay = delay
+	}
+}
+
+func TestCalculateBackoff_FullJitter(t *testing.T) {
+	config := RetryConfig{
+		BaseDelay:     100 * time.Millisecond,
+		MaxDelay:      5 * time.Second,
+		BackoffFactor: 2.0,
+		Strategy:      FullJitter,
+	}
+
+	for attempt := 0; attempt < 6; attempt++ {
+		cap := capDelay(time.Duration(float64(config.BaseDelay)*math.Pow(config.BackoffFactor, float64(attempt))), config.MaxDelay)
+		for i := 0; i < 10; i++ {
+			delay := calculateBackoff(attempt, 0, config)
+			if delay < 0 || delay > cap {
+				t.Fatalf("attempt %d: delay %v outside [0, %v]", attempt, delay, cap)
+			}
+		}
+	}
+}
+
+func TestIsRetryableAWSError(t *testing.T) {
+	throttled := awserr.NewRequestFailure(
+		awserr.New("ProvisionedThroughputExceededException", "too many requests", nil),
+		400, "req-1",
+	)
+	serviceUnavailable := awserr.NewRequestFailure(
+		awserr.New("ServiceUnavailable", "down for maintenance", nil),
+		503, "req-2",
+	)
+	badRequest := awserr.NewRequestFailure(
+		awserr.New("ValidationException", "bad input", nil),
+		400, "req-3",
+	)
+
+	tests := []struct {
+		name       string
+		err        error
+		extraCodes []string
+		want       bool
+	}{
+		{"throttling error code", throttled, nil, true},
+		{"503 status code", serviceUnavailable, nil, true},
+		{"non-retryable AWS error", badRequest, nil, false},
+		{"caller-supplied code", badRequest, []string{"ValidationException"}, true},
+		{"plain non-AWS error", errors.New("boom"), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryableAWSError(tt.err, tt.extraCodes); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
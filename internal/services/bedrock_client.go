@@ -1,21 +1,174 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/bedrockruntime"
 
+	"aws-serverless-rag/internal/embedcache"
 	"aws-serverless-rag/internal/models"
+	"aws-serverless-rag/internal/reliability"
 	"aws-serverless-rag/internal/utils"
 )
 
 // BedrockClient handles interactions with Amazon Bedrock
 type BedrockClient struct {
-	client *bedrockruntime.BedrockRuntime
-	logger *utils.Logger
+	client      *bedrockruntime.BedrockRuntime
+	logger      *utils.Logger
+	retryConfig reliability.RetryConfig
+
+	// cache short-circuits GenerateEmbedding/GenerateEmbeddings on a content-hash
+	// hit, avoiding a redundant Bedrock call for text this client has already
+	// embedded. Nil (the default) disables caching entirely.
+	cache embedcache.EmbeddingCache
+
+	// concurrency bounds how many embedding requests GenerateEmbeddings/
+	// GenerateEmbeddingsPartial fan out at once. Zero (the default) falls back to
+	// defaultEmbeddingConcurrency.
+	concurrency int
+
+	// limiter, if set, paces embedding requests to Bedrock's TPS quota across all
+	// of this client's workers combined. Nil (the default) disables rate limiting.
+	limiter *tokenBucketLimiter
+}
+
+// WithEmbeddingCache enables embedding caching, keyed by embedcache.Hash(modelID,
+// text). Returns bc so it can be chained onto NewBedrockClient.
+func (bc *BedrockClient) WithEmbeddingCache(cache embedcache.EmbeddingCache) *BedrockClient {
+	bc.cache = cache
+	return bc
+}
+
+// WithEmbeddingConcurrency overrides how many embedding requests
+// GenerateEmbeddings/GenerateEmbeddingsPartial fan out at once. Returns bc so it
+// can be chained onto NewBedrockClient. A non-positive n is ignored.
+func (bc *BedrockClient) WithEmbeddingConcurrency(n int) *BedrockClient {
+	if n > 0 {
+		bc.concurrency = n
+	}
+	return bc
+}
+
+// WithEmbeddingRateLimit caps embedding requests to ratePerSecond across all of
+// this client's workers combined, to stay under a Bedrock TPS quota. Returns bc
+// so it can be chained onto NewBedrockClient. A non-positive ratePerSecond
+// disables rate limiting (the default).
+func (bc *BedrockClient) WithEmbeddingRateLimit(ratePerSecond float64) *BedrockClient {
+	if ratePerSecond > 0 {
+		bc.limiter = newTokenBucketLimiter(ratePerSecond)
+	} else {
+		bc.limiter = nil
+	}
+	return bc
+}
+
+// bedrockRetryConfig retries Bedrock throttling (ThrottlingException, etc.) with
+// decorrelated jitter, which AWS recommends for spreading out concurrent callers.
+func bedrockRetryConfig() reliability.RetryConfig {
+	config := reliability.DefaultRetryConfig()
+	config.Strategy = reliability.DecorrelatedJitter
+	return config
+}
+
+// defaultEmbeddingConcurrency bounds how many embedding requests GenerateEmbeddings
+// fans out at once, so a large document doesn't open hundreds of simultaneous
+// Bedrock connections.
+const defaultEmbeddingConcurrency = 8
+
+// embeddingRetryConfig retries embedding calls only on Bedrock throttling (via
+// isBedrockThrottlingError), with full jitter rather than GenerateAnswer/
+// GenerateEmbedding's decorrelated jitter: full jitter doesn't depend on a previous
+// delay, so the many concurrent workers GenerateEmbeddings starts back off
+// independently instead of compounding each other's wait times.
+func embeddingRetryConfig() reliability.RetryConfig {
+	return reliability.RetryConfig{
+		MaxRetries:  5,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Strategy:    reliability.FullJitter,
+		IsRetryable: isBedrockThrottlingError,
+	}
+}
+
+// isBedrockThrottlingError reports whether err indicates Bedrock is throttling the
+// caller (as opposed to a non-retryable error like a malformed request), based on
+// its AWS error code or HTTP status.
+func isBedrockThrottlingError(err error) bool {
+	var reqErr awserr.RequestFailure
+	if !errors.As(err, &reqErr) {
+		return false
+	}
+
+	switch reqErr.Code() {
+	case "ThrottlingException", "ServiceUnavailableException":
+		return true
+	}
+
+	return reqErr.StatusCode() == http.StatusTooManyRequests || reqErr.StatusCode() >= http.StatusInternalServerError
+}
+
+// tokenBucketLimiter paces callers to a configured rate (tokens/sec), refilling
+// continuously rather than in fixed windows, so GenerateEmbeddings' workers spend a
+// Bedrock TPS quota smoothly instead of bursting up to bc.concurrency requests at
+// once. The bucket holds up to one second's worth of tokens, so a caller that has
+// been idle can still burst briefly before being paced down to the steady-state rate.
+type tokenBucketLimiter struct {
+	mu       sync.Mutex
+	rate     float64 // tokens added per second
+	capacity float64
+	tokens   float64
+	updated  time.Time
+}
+
+// newTokenBucketLimiter creates a limiter starting with a full bucket, so the first
+// ratePerSecond calls don't wait at all.
+func newTokenBucketLimiter(ratePerSecond float64) *tokenBucketLimiter {
+	return &tokenBucketLimiter{
+		rate:     ratePerSecond,
+		capacity: ratePerSecond,
+		tokens:   ratePerSecond,
+		updated:  time.Now(),
+	}
+}
+
+// Wait blocks until a token is available, or ctx is done, whichever comes first.
+func (l *tokenBucketLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens = math.Min(l.capacity, l.tokens+now.Sub(l.updated).Seconds()*l.rate)
+		l.updated = now
+
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
 }
 
 // TitanEmbeddingRequest represents the request structure for Titan embedding model
@@ -65,18 +218,67 @@ const (
 	ClaudeModelID         = "anthropic.claude-3-sonnet-20240229-v1:0"
 )
 
-// NewBedrockClient creates a new Bedrock client
+// backgroundCtx is used to drive retries where the surrounding method has no context
+// of its own to thread through (GenerateAnswer's "context" parameter shadows the
+// context package name).
+var backgroundCtx = context.Background()
+
+// NewBedrockClient creates a new Bedrock client. Embedding worker concurrency and
+// rate limit default from the BEDROCK_EMBEDDING_CONCURRENCY (worker count) and
+// BEDROCK_EMBEDDING_TPS (requests/sec) environment variables; either can still be
+// overridden afterwards via WithEmbeddingConcurrency/WithEmbeddingRateLimit.
 func NewBedrockClient(awsConfig *utils.AWSConfig, logger *utils.Logger) *BedrockClient {
-	return &BedrockClient{
-		client: awsConfig.BedrockClient,
-		logger: logger,
+	bc := &BedrockClient{
+		client:      awsConfig.BedrockClient,
+		logger:      logger,
+		retryConfig: bedrockRetryConfig(),
+	}
+
+	if n, err := strconv.Atoi(os.Getenv("BEDROCK_EMBEDDING_CONCURRENCY")); err == nil {
+		bc.WithEmbeddingConcurrency(n)
+	}
+	if tps, err := strconv.ParseFloat(os.Getenv("BEDROCK_EMBEDDING_TPS"), 64); err == nil {
+		bc.WithEmbeddingRateLimit(tps)
+	}
+
+	return bc
+}
+
+// GenerateEmbedding generates an embedding for the given text using Titan, retrying
+// on Bedrock throttling with bc.retryConfig's decorrelated jitter. If bc.cache is
+// set, a cache hit on sha256(modelID || normalizedText) skips Bedrock entirely.
+func (bc *BedrockClient) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	if bc.cache == nil {
+		embedding, _, err := bc.generateEmbedding(ctx, text, bc.retryConfig)
+		return embedding, err
+	}
+
+	hash := embedcache.Hash(TitanEmbeddingModelID, text)
+	if embedding, hit, err := bc.cache.Get(hash); err != nil {
+		bc.logger.Warn("Embedding cache lookup failed", map[string]interface{}{"error": err.Error()})
+	} else if hit {
+		bc.logger.Debug("Embedding cache hit", map[string]interface{}{"hash": hash})
+		return embedding, nil
+	}
+
+	embedding, _, err := bc.generateEmbedding(ctx, text, bc.retryConfig)
+	if err != nil {
+		return nil, err
 	}
+
+	if err := bc.cache.Put(hash, TitanEmbeddingModelID, embedding); err != nil {
+		bc.logger.Warn("Failed to write embedding cache entry", map[string]interface{}{"error": err.Error()})
+	}
+
+	return embedding, nil
 }
 
-// GenerateEmbedding generates embeddings for the given text using Titan
-func (bc *BedrockClient) GenerateEmbedding(text string) ([]float32, error) {
+// generateEmbedding invokes Titan for a single text under config and reports how
+// many attempts (including the first) it took, so GenerateEmbeddings can aggregate
+// retry counts across its worker pool.
+func (bc *BedrockClient) generateEmbedding(ctx context.Context, text string, config reliability.RetryConfig) ([]float32, int, error) {
 	if strings.TrimSpace(text) == "" {
-		return nil, models.ErrInvalidRequest
+		return nil, 0, models.ErrInvalidRequest
 	}
 
 	// Prepare the request
@@ -87,7 +289,7 @@ func (bc *BedrockClient) GenerateEmbedding(text string) ([]float32, error) {
 	requestBody, err := json.Marshal(request)
 	if err != nil {
 		bc.logger.ErrorWithErr("Failed to marshal embedding request", err)
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, 0, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Call Bedrock
@@ -103,10 +305,17 @@ func (bc *BedrockClient) GenerateEmbedding(text string) ([]float32, error) {
 		"text_length": len(text),
 	})
 
-	result, err := bc.client.InvokeModel(input)
+	var result *bedrockruntime.InvokeModelOutput
+	attempts := 0
+	err = reliability.ExecuteWithRetry(ctx, config, func() error {
+		attempts++
+		var invokeErr error
+		result, invokeErr = bc.client.InvokeModelWithContext(ctx, input)
+		return invokeErr
+	}, bc.logger)
 	if err != nil {
 		bc.logger.ErrorWithErr("Failed to invoke Bedrock embedding model", err)
-		return nil, fmt.Errorf("failed to invoke embedding model: %w", err)
+		return nil, attempts, fmt.Errorf("failed to invoke embedding model: %w", err)
 	}
 
 	// Parse the response
@@ -114,18 +323,18 @@ func (bc *BedrockClient) GenerateEmbedding(text string) ([]float32, error) {
 	err = json.Unmarshal(result.Body, &response)
 	if err != nil {
 		bc.logger.ErrorWithErr("Failed to unmarshal embedding response", err)
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, attempts, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	if len(response.Embedding) == 0 {
-		return nil, models.ErrEmbeddingFailed
+		return nil, attempts, models.ErrEmbeddingFailed
 	}
 
 	bc.logger.Debug("Successfully generated embedding", map[string]interface{}{
 		"embedding_dimension": len(response.Embedding),
 	})
 
-	return response.Embedding, nil
+	return response.Embedding, attempts, nil
 }
 
 // GenerateAnswer generates an answer using Claude based on the context and question
@@ -186,7 +395,12 @@ Please provide a helpful answer based on the context above.`, contextText, quest
 		"context_sources": len(context),
 	})
 
-	result, err := bc.client.InvokeModel(input)
+	var result *bedrockruntime.InvokeModelOutput
+	err = reliability.ExecuteWithRetry(backgroundCtx, bc.retryConfig, func() error {
+		var invokeErr error
+		result, invokeErr = bc.client.InvokeModel(input)
+		return invokeErr
+	}, bc.logger)
 	if err != nil {
 		bc.logger.ErrorWithErr("Failed to invoke Bedrock Claude model", err)
 		return "", fmt.Errorf("failed to invoke Claude model: %w", err)
@@ -218,6 +432,145 @@ Please provide a helpful answer based on the context above.`, contextText, quest
 	return answer, nil
 }
 
+// GenerateAnswerStream is the streaming counterpart to GenerateAnswer: it invokes
+// Claude via InvokeModelWithResponseStream and pushes each text delta onto out as
+// it arrives, instead of waiting for the full response. If out is nil, deltas are
+// accumulated but never sent, giving callers a buffered fallback that still
+// returns the full answer and usage once the stream completes. Canceling ctx
+// closes the underlying event stream.
+func (bc *BedrockClient) GenerateAnswerStream(ctx context.Context, question string, sources []models.Source, out chan<- string) (string, ClaudeUsage, error) {
+	if strings.TrimSpace(question) == "" {
+		return "", ClaudeUsage{}, models.ErrInvalidQuestion
+	}
+
+	contextText := bc.buildContextFromSources(sources)
+
+	systemPrompt := `You are a helpful assistant that answers questions based on the provided context.
+Follow these guidelines:
+1. Answer based ONLY on the information provided in the context
+2. If the context doesn't contain enough information to answer the question, say so
+3. Be concise but comprehensive
+4. Cite relevant parts of the context when appropriate
+5. If the question cannot be answered from the context, explain what information is missing`
+
+	userMessage := fmt.Sprintf(`Context:
+%s
+
+Question: %s
+
+Please provide a helpful answer based on the context above.`, contextText, question)
+
+	request := ClaudeRequest{
+		Messages: []ClaudeMessage{
+			{
+				Role:    "user",
+				Content: userMessage,
+			},
+		},
+		MaxTokens: 1000,
+		System:    systemPrompt,
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		bc.logger.ErrorWithErr("Failed to marshal Claude stream request", err)
+		return "", ClaudeUsage{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	input := &bedrockruntime.InvokeModelWithResponseStreamInput{
+		ModelId:     aws.String(ClaudeModelID),
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+		Body:        requestBody,
+	}
+
+	bc.logger.Debug("Calling Bedrock for streaming answer generation", map[string]interface{}{
+		"model_id":        ClaudeModelID,
+		"question_length": len(question),
+		"context_sources": len(sources),
+	})
+
+	output, err := bc.client.InvokeModelWithResponseStreamWithContext(ctx, input)
+	if err != nil {
+		bc.logger.ErrorWithErr("Failed to invoke Bedrock Claude model for streaming", err)
+		return "", ClaudeUsage{}, fmt.Errorf("failed to invoke Claude model: %w", err)
+	}
+	stream := output.GetStream()
+	defer stream.Close()
+
+	var answer strings.Builder
+	var usage ClaudeUsage
+
+	for event := range stream.Events() {
+		part, ok := event.(*bedrockruntime.PayloadPart)
+		if !ok {
+			continue
+		}
+
+		var chunk claudeStreamEvent
+		if err := json.Unmarshal(part.Bytes, &chunk); err != nil {
+			bc.logger.ErrorWithErr("Failed to unmarshal Claude stream chunk", err)
+			return "", ClaudeUsage{}, fmt.Errorf("failed to unmarshal stream chunk: %w", err)
+		}
+
+		switch chunk.Type {
+		case "content_block_delta":
+			if chunk.Delta.Text == "" {
+				continue
+			}
+			answer.WriteString(chunk.Delta.Text)
+			if out != nil {
+				select {
+				case out <- chunk.Delta.Text:
+				case <-ctx.Done():
+					return answer.String(), usage, ctx.Err()
+				}
+			}
+		case "message_delta":
+			if chunk.Usage.OutputTokens > 0 {
+				usage.OutputTokens = chunk.Usage.OutputTokens
+			}
+		case "message_stop":
+			// Claude reports final usage on message_delta, not message_stop, but
+			// some runtimes attach it here too; take whichever arrives.
+			if chunk.Usage.OutputTokens > 0 {
+				usage.OutputTokens = chunk.Usage.OutputTokens
+			}
+			if chunk.Usage.InputTokens > 0 {
+				usage.InputTokens = chunk.Usage.InputTokens
+			}
+		}
+	}
+
+	if err := stream.Err(); err != nil {
+		bc.logger.ErrorWithErr("Error reading Claude response stream", err)
+		return answer.String(), usage, fmt.Errorf("error reading response stream: %w", err)
+	}
+
+	if answer.Len() == 0 {
+		return "", usage, models.ErrLLMGenerationFailed
+	}
+
+	bc.logger.Debug("Successfully generated streamed answer", map[string]interface{}{
+		"answer_length": answer.Len(),
+		"input_tokens":  usage.InputTokens,
+		"output_tokens": usage.OutputTokens,
+	})
+
+	return answer.String(), usage, nil
+}
+
+// claudeStreamEvent is one decoded event from Claude's InvokeModelWithResponseStream
+// output: a content_block_delta carries an incremental text delta, while
+// message_delta/message_stop carry the final token usage.
+type claudeStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+	Usage ClaudeUsage `json:"usage"`
+}
+
 // buildContextFromSources builds a context string from the provided sources
 func (bc *BedrockClient) buildContextFromSources(sources []models.Source) string {
 	if len(sources) == 0 {
@@ -235,29 +588,191 @@ func (bc *BedrockClient) buildContextFromSources(sources []models.Source) string
 	return contextBuilder.String()
 }
 
-// GenerateEmbeddings generates embeddings for multiple texts in batch
-func (bc *BedrockClient) GenerateEmbeddings(texts []string) ([][]float32, error) {
+// GenerateEmbeddings generates embeddings for multiple texts in batch, fanning work
+// out across a bounded worker pool instead of calling Bedrock one text at a time.
+// The returned slice preserves texts' order. The first non-retryable error cancels
+// the batch for every other in-flight worker (so they fail fast instead of burning
+// through their own retries) and is returned once every worker has stopped. Callers
+// that would rather get back whatever succeeded instead of losing the whole batch to
+// one bad text should use GenerateEmbeddingsPartial instead.
+func (bc *BedrockClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings, _, err := bc.generateEmbeddingsBatch(ctx, texts, true)
+	return embeddings, err
+}
+
+// GenerateEmbeddingsPartial is GenerateEmbeddings for callers that can tolerate a
+// partial result: instead of aborting on the first per-text failure, every text is
+// attempted, and failedIndices lists (in ascending order) which entries of the
+// returned slice are nil because they failed. err is non-nil only when every text in
+// the batch failed (there is nothing partial to return), in which case it wraps the
+// first failure.
+func (bc *BedrockClient) GenerateEmbeddingsPartial(ctx context.Context, texts []string) (embeddings [][]float32, failedIndices []int, err error) {
+	return bc.generateEmbeddingsBatch(ctx, texts, false)
+}
+
+// generateEmbeddingsBatch is the shared worker-pool implementation behind
+// GenerateEmbeddings and GenerateEmbeddingsPartial. When failFast is true, the first
+// per-text error cancels every other in-flight worker and is returned with a nil
+// embeddings slice, matching GenerateEmbeddings' historical all-or-nothing contract.
+// When failFast is false, every text is attempted regardless of earlier failures,
+// and failed indices are reported instead of aborting the batch. Either way, work is
+// bounded by bc.concurrency workers and, if bc.limiter is set, paced to no more than
+// its configured Bedrock TPS quota; retry count, throttled count, and wall time are
+// logged for the batch either way.
+func (bc *BedrockClient) generateEmbeddingsBatch(ctx context.Context, texts []string, failFast bool) ([][]float32, []int, error) {
 	if len(texts) == 0 {
-		return nil, models.ErrInvalidRequest
+		return nil, nil, models.ErrInvalidRequest
 	}
 
 	embeddings := make([][]float32, len(texts))
-
+	hashes := make([]string, len(texts))
 	for i, text := range texts {
-		embedding, err := bc.GenerateEmbedding(text)
+		hashes[i] = embedcache.Hash(TitanEmbeddingModelID, text)
+	}
+
+	// Resolve cache hits in one batch round-trip before dispatching any Bedrock
+	// calls, so a fully-cached batch never touches the worker pool at all.
+	var cacheHits map[string][]float32
+	if bc.cache != nil {
+		hits, err := bc.cache.GetBatch(hashes)
 		if err != nil {
-			bc.logger.ErrorWithErr("Failed to generate embedding for text", err, map[string]interface{}{
-				"index":       i,
-				"text_length": len(text),
-			})
-			return nil, fmt.Errorf("failed to generate embedding for text %d: %w", i, err)
+			bc.logger.Warn("Embedding cache batch lookup failed", map[string]interface{}{"error": err.Error()})
+		} else {
+			cacheHits = hits
 		}
-		embeddings[i] = embedding
 	}
 
-	bc.logger.Debug("Successfully generated batch embeddings", map[string]interface{}{
-		"batch_size": len(texts),
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type job struct {
+		index int
+		text  string
+		hash  string
+	}
+	type outcome struct {
+		index     int
+		hash      string
+		embedding []float32
+		attempts  int
+		err       error
+	}
+
+	var jobList []job
+	for i, text := range texts {
+		if embedding, hit := cacheHits[hashes[i]]; hit {
+			embeddings[i] = embedding
+			continue
+		}
+		jobList = append(jobList, job{index: i, text: text, hash: hashes[i]})
+	}
+
+	cacheHitCount := len(texts) - len(jobList)
+
+	jobs := make(chan job, len(jobList))
+	for _, j := range jobList {
+		jobs <- j
+	}
+	close(jobs)
+
+	concurrency := bc.concurrency
+	if concurrency <= 0 {
+		concurrency = defaultEmbeddingConcurrency
+	}
+	if concurrency > len(jobList) {
+		concurrency = len(jobList)
+	}
+
+	outcomes := make(chan outcome, len(jobList))
+	config := embeddingRetryConfig()
+
+	var wg sync.WaitGroup
+	start := time.Now()
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if bc.limiter != nil {
+					if err := bc.limiter.Wait(workerCtx); err != nil {
+						outcomes <- outcome{index: j.index, hash: j.hash, err: err}
+						continue
+					}
+				}
+
+				embedding, attempts, err := bc.generateEmbedding(workerCtx, j.text, config)
+				if err != nil && failFast {
+					cancel()
+				}
+				outcomes <- outcome{index: j.index, hash: j.hash, embedding: embedding, attempts: attempts, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	var firstErr error
+	var failed []int
+	var totalRetries, throttledCount int
+	newEntries := make(map[string][]float32)
+
+	for o := range outcomes {
+		if o.attempts > 1 {
+			totalRetries += o.attempts - 1
+		}
+		if o.err != nil {
+			if isBedrockThrottlingError(o.err) {
+				throttledCount++
+			}
+			wrapped := fmt.Errorf("failed to generate embedding for text %d: %w", o.index, o.err)
+			if firstErr == nil {
+				firstErr = wrapped
+			}
+			if !failFast {
+				failed = append(failed, o.index)
+			}
+			continue
+		}
+		embeddings[o.index] = o.embedding
+		newEntries[o.hash] = o.embedding
+	}
+
+	if bc.cache != nil && len(newEntries) > 0 {
+		if err := bc.cache.PutBatch(TitanEmbeddingModelID, newEntries); err != nil {
+			bc.logger.Warn("Embedding cache batch write failed", map[string]interface{}{"error": err.Error()})
+		}
+	}
+
+	sort.Ints(failed)
+
+	bc.logger.Debug("Completed batch embedding generation", map[string]interface{}{
+		"batch_size":      len(texts),
+		"cache_hits":      cacheHitCount,
+		"cache_misses":    len(jobList),
+		"concurrency":     concurrency,
+		"fail_fast":       failFast,
+		"failed":          len(failed),
+		"retries":         totalRetries,
+		"throttled_count": throttledCount,
+		"wall_time_ms":    time.Since(start).Milliseconds(),
 	})
 
-	return embeddings, nil
+	if failFast {
+		if firstErr != nil {
+			bc.logger.ErrorWithErr("Failed to generate batch embeddings", firstErr)
+			return nil, nil, firstErr
+		}
+		return embeddings, nil, nil
+	}
+
+	if len(failed) == len(texts) {
+		bc.logger.ErrorWithErr("Failed to generate any embeddings in batch", firstErr)
+		return nil, failed, firstErr
+	}
+
+	return embeddings, failed, nil
 }
@@ -61,17 +61,6 @@ type SearchHit struct {
 	Source map[string]interface{} `json:"_source"`
 }
 
-// BulkOperation represents a bulk operation item
-type BulkOperation struct {
-	Index BulkOperationAction `json:"index"`
-}
-
-// BulkOperationAction represents a bulk operation action
-type BulkOperationAction struct {
-	Index string `json:"_index"`
-	ID    string `json:"_id"`
-}
-
 // NewOpenSearchClient creates a new OpenSearch client
 func NewOpenSearchClient(logger *utils.Logger) (*OpenSearchClient, error) {
 	endpoint := utils.GetOpenSearchEndpoint()
@@ -99,8 +88,21 @@ func NewOpenSearchClient(logger *utils.Logger) (*OpenSearchClient, error) {
 	}, nil
 }
 
-// CreateIndex creates the vector index with proper mapping
-func (osc *OpenSearchClient) CreateIndex(ctx context.Context) error {
+// DefaultEmbeddingDimension is the knn_vector dimension CreateIndex falls back to
+// when called without a more specific dimension (Titan v1's output size, the
+// model this client has historically assumed).
+const DefaultEmbeddingDimension = 1536
+
+// CreateIndex creates the vector index with proper mapping. dimension sizes the
+// embedding field's knn_vector mapping; pass 0 to fall back to
+// DefaultEmbeddingDimension. Callers indexing with a different embedding
+// provider (see backend.DescribedEmbeddingProvider) should pass that provider's
+// Dimension() instead of relying on the Titan v1 default.
+func (osc *OpenSearchClient) CreateIndex(ctx context.Context, dimension int) error {
+	if dimension <= 0 {
+		dimension = DefaultEmbeddingDimension
+	}
+
 	// Check if index already exists
 	req := opensearchapi.IndicesExistsRequest{
 		Index: []string{osc.indexName},
@@ -134,7 +136,7 @@ func (osc *OpenSearchClient) CreateIndex(ctx context.Context) error {
 				},
 				"embedding": map[string]interface{}{
 					"type":      "knn_vector",
-					"dimension": 1536, // Titan embedding dimension
+					"dimension": dimension,
 					"method": map[string]interface{}{
 						"name":       "hnsw",
 						"space_type": "cosinesimilarity",
@@ -200,93 +202,7 @@ func (osc *OpenSearchClient) CreateIndex(ctx context.Context) error {
 	return nil
 }
 
-// IndexChunks indexes document chunks with their embeddings
-func (osc *OpenSearchClient) IndexChunks(ctx context.Context, chunks []*models.Chunk) error {
-	if len(chunks) == 0 {
-		return nil
-	}
-
-	osc.logger.Info("Indexing chunks", map[string]interface{}{
-		"chunk_count": len(chunks),
-		"index_name":  osc.indexName,
-	})
-
-	// Prepare bulk operations
-	var bulkBody strings.Builder
-
-	for _, chunk := range chunks {
-		// Create bulk operation header
-		operation := BulkOperation{
-			Index: BulkOperationAction{
-				Index: osc.indexName,
-				ID:    chunk.ID,
-			},
-		}
-
-		operationJSON, err := json.Marshal(operation)
-		if err != nil {
-			return fmt.Errorf("failed to marshal bulk operation: %w", err)
-		}
-
-		bulkBody.WriteString(string(operationJSON))
-		bulkBody.WriteString("\n")
-
-		// Create document
-		doc := map[string]interface{}{
-			"document_id": chunk.DocumentID,
-			"chunk_id":    chunk.ID,
-			"content":     chunk.Content,
-			"embedding":   chunk.Embedding,
-			"metadata":    chunk.Metadata,
-			"created_at":  chunk.CreatedAt.Format(time.RFC3339),
-		}
-
-		docJSON, err := json.Marshal(doc)
-		if err != nil {
-			return fmt.Errorf("failed to marshal chunk document: %w", err)
-		}
-
-		bulkBody.WriteString(string(docJSON))
-		bulkBody.WriteString("\n")
-	}
-
-	// Execute bulk request
-	req := opensearchapi.BulkRequest{
-		Index: osc.indexName,
-		Body:  strings.NewReader(bulkBody.String()),
-	}
-
-	res, err := req.Do(ctx, osc.client)
-	if err != nil {
-		return fmt.Errorf("failed to execute bulk request: %w", err)
-	}
-	defer res.Body.Close()
-
-	if res.IsError() {
-		return fmt.Errorf("bulk request failed: %s", res.Status())
-	}
-
-	// Parse bulk response to check for individual errors
-	var bulkRes map[string]interface{}
-	if err := json.NewDecoder(res.Body).Decode(&bulkRes); err != nil {
-		return fmt.Errorf("failed to decode bulk response: %w", err)
-	}
-
-	if errors, exists := bulkRes["errors"].(bool); exists && errors {
-		osc.logger.Warn("Some bulk operations failed", map[string]interface{}{
-			"bulk_response": bulkRes,
-		})
-	}
-
-	osc.logger.Info("Chunks indexed successfully", map[string]interface{}{
-		"chunk_count": len(chunks),
-		"index_name":  osc.indexName,
-	})
-
-	return nil
-}
-
-// VectorSearch performs a vector similarity search
+// VectorSearch performs a vector similarity search against the index.
 func (osc *OpenSearchClient) VectorSearch(ctx context.Context, queryEmbedding []float32, maxResults int) ([]models.Source, error) {
 	if len(queryEmbedding) == 0 {
 		return nil, models.ErrInvalidRequest
@@ -302,7 +218,6 @@ func (osc *OpenSearchClient) VectorSearch(ctx context.Context, queryEmbedding []
 		"index_name":          osc.indexName,
 	})
 
-	// Prepare KNN search query
 	searchRequest := SearchRequest{
 		Size: maxResults,
 		Query: map[string]interface{}{
@@ -358,7 +273,22 @@ func (osc *OpenSearchClient) VectorSearch(ctx context.Context, queryEmbedding []
 			}
 		}
 
+		if embedding, exists := hit.Source["embedding"]; exists {
+			if embeddingSlice, ok := embedding.([]interface{}); ok {
+				source.Embedding = make([]float32, 0, len(embeddingSlice))
+				for _, v := range embeddingSlice {
+					if f, ok := v.(float64); ok {
+						source.Embedding = append(source.Embedding, float32(f))
+					}
+				}
+			}
+		}
+
 		sources = append(sources, source)
+
+		if len(sources) >= maxResults {
+			break
+		}
 	}
 
 	osc.logger.Debug("Vector search completed", map[string]interface{}{
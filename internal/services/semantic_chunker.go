@@ -0,0 +1,487 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ChunkStrategy selects how SemanticChunker splits a document into chunks.
+type ChunkStrategy string
+
+// Strategies accepted by ChunkerConfig.Strategy. An unrecognized or empty value
+// falls back to ChunkStrategyFixed.
+const (
+	// ChunkStrategyFixed splits into rune-safe, roughly-MaxTokens-sized pieces,
+	// ignoring sentence or document structure. Cheapest and most predictable.
+	ChunkStrategyFixed ChunkStrategy = "fixed"
+	// ChunkStrategySentence groups whole sentences (ASCII and CJK-terminated)
+	// into chunks up to MaxTokens, never splitting a sentence across chunks.
+	ChunkStrategySentence ChunkStrategy = "sentence"
+	// ChunkStrategyMarkdown groups whole Markdown blocks (a heading with its
+	// body, or a fenced code block) into chunks up to MaxTokens, never splitting
+	// a fenced code block.
+	ChunkStrategyMarkdown ChunkStrategy = "markdown"
+	// ChunkStrategySemantic groups sentences the same way ChunkStrategySentence
+	// does, but additionally starts a new chunk wherever the embedding
+	// similarity between two adjacent sentences drops below a percentile
+	// threshold, so a chunk never spans a genuine topic change. Requires
+	// ChunkerConfig.Embed; falls back to ChunkStrategySentence's plain packing
+	// if Embed is nil or returns an error.
+	ChunkStrategySemantic ChunkStrategy = "semantic"
+)
+
+// DefaultMaxTokens is used when ChunkerConfig.MaxTokens is unset or non-positive.
+const DefaultMaxTokens = 250
+
+// DefaultSimilarityPercentile is used when ChunkerConfig.SimilarityPercentile is
+// unset or non-positive.
+const DefaultSimilarityPercentile = 25.0
+
+// EmbedFunc generates an embedding for a single piece of text.
+// BedrockClient.GenerateEmbedding satisfies this signature, so
+// ChunkStrategySemantic can score sentence similarity with a live Bedrock call
+// without this package importing the AWS SDK.
+type EmbedFunc func(text string) ([]float32, error)
+
+// ChunkerConfig configures a SemanticChunker.
+type ChunkerConfig struct {
+	Strategy ChunkStrategy
+	// MaxTokens is the approximate per-chunk token budget (see EstimateTokens).
+	MaxTokens int
+	// OverlapTokens is how many trailing tokens of a chunk are repeated at the
+	// start of the next one, as whole sentences/blocks for the
+	// sentence/markdown/semantic strategies; ChunkStrategyFixed applies it as
+	// an estimated number of runes instead. Zero disables overlap.
+	OverlapTokens int
+	// Embed is required by ChunkStrategySemantic; ignored by every other
+	// strategy.
+	Embed EmbedFunc
+	// SimilarityPercentile is the adjacent-sentence cosine-similarity
+	// percentile below which ChunkStrategySemantic starts a new chunk. Ignored
+	// by every other strategy.
+	SimilarityPercentile float64
+}
+
+// SemanticChunker splits extracted document text into chunks sized for
+// embedding. Unlike TextExtractor's original byte-slicing chunkText, every
+// strategy here operates on runes, so it never splits a multi-byte character
+// (important for Japanese and other non-ASCII text).
+type SemanticChunker struct {
+	config ChunkerConfig
+}
+
+// NewSemanticChunker creates a SemanticChunker, filling in DefaultMaxTokens and
+// ChunkStrategyFixed for any zero-value fields in config.
+func NewSemanticChunker(config ChunkerConfig) *SemanticChunker {
+	if config.Strategy == "" {
+		config.Strategy = ChunkStrategyFixed
+	}
+	if config.MaxTokens <= 0 {
+		config.MaxTokens = DefaultMaxTokens
+	}
+	if config.OverlapTokens < 0 {
+		config.OverlapTokens = 0
+	}
+	if config.SimilarityPercentile <= 0 {
+		config.SimilarityPercentile = DefaultSimilarityPercentile
+	}
+	return &SemanticChunker{config: config}
+}
+
+// Chunk splits text according to the configured strategy. It returns nil for
+// blank text.
+func (sc *SemanticChunker) Chunk(text string) []string {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	switch sc.config.Strategy {
+	case ChunkStrategySentence:
+		return sc.packSegments(splitIntoSentences(text), " ")
+	case ChunkStrategyMarkdown:
+		return sc.packSegments(splitIntoMarkdownBlocks(text), "\n\n")
+	case ChunkStrategySemantic:
+		return sc.chunkSemantic(text)
+	default:
+		return sc.chunkFixed(text)
+	}
+}
+
+// chunkSemantic groups sentences like packSegments, but additionally breaks
+// before any sentence whose embedding similarity to the previous one falls
+// below the SimilarityPercentile of all adjacent similarities in text, so a
+// chunk never spans a genuine topic change. Falls back to plain sentence
+// packing if Embed is unset or a sentence fails to embed.
+func (sc *SemanticChunker) chunkSemantic(text string) []string {
+	sentences := splitIntoSentences(text)
+	if sc.config.Embed == nil {
+		return sc.packSegments(sentences, " ")
+	}
+
+	embeddings := make([][]float32, len(sentences))
+	for i, sentence := range sentences {
+		embedding, err := sc.config.Embed(sentence)
+		if err != nil {
+			return sc.packSegments(sentences, " ")
+		}
+		embeddings[i] = embedding
+	}
+
+	similarities := make([]float64, 0, len(sentences)-1)
+	for i := 1; i < len(embeddings); i++ {
+		similarities = append(similarities, cosineSimilarity(embeddings[i-1], embeddings[i]))
+	}
+	threshold := percentile(similarities, sc.config.SimilarityPercentile)
+
+	var chunks []string
+	var current []string
+	currentTokens := 0
+
+	for i, sentence := range sentences {
+		segmentTokens := EstimateTokens(sentence)
+		topicBreak := i > 0 && similarities[i-1] <= threshold
+		overBudget := currentTokens > 0 && currentTokens+segmentTokens > sc.config.MaxTokens
+
+		if currentTokens > 0 && (topicBreak || overBudget) {
+			chunks = append(chunks, strings.Join(current, " "))
+			current, currentTokens = overlapTail(current, sc.config.OverlapTokens)
+		}
+
+		current = append(current, sentence)
+		currentTokens += segmentTokens
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, strings.Join(current, " "))
+	}
+
+	return chunks
+}
+
+// percentile returns the value at the given percentile (0-100) of values: the
+// element at index floor(p/100*(len-1)) of the sorted values, without
+// interpolation. Returns 0 for an empty input.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	idx := int(p / 100 * float64(len(sorted)-1))
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+// cosineSimilarity returns the cosine similarity of two embedding vectors, or 0
+// if either is empty, mismatched in length, or zero-length in magnitude.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(b) == 0 || len(a) != len(b) {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// chunkFixed splits text into rune-safe pieces of approximately MaxTokens each,
+// backing off by OverlapTokens worth of runes (estimated from the chunk's own
+// observed chars-per-token rate) between pieces.
+func (sc *SemanticChunker) chunkFixed(text string) []string {
+	runes := []rune(text)
+	var chunks []string
+
+	start := 0
+	for start < len(runes) {
+		var counter runeTokenCounter
+		end := start
+		for end < len(runes) {
+			tokens := counter.add(runes[end])
+			end++
+			if tokens >= sc.config.MaxTokens {
+				break
+			}
+		}
+
+		if chunk := strings.TrimSpace(string(runes[start:end])); chunk != "" {
+			chunks = append(chunks, chunk)
+		}
+
+		if end >= len(runes) {
+			break
+		}
+
+		next := end
+		if sc.config.OverlapTokens > 0 && counter.tokens > 0 {
+			charsPerToken := float64(end-start) / float64(counter.tokens)
+			overlapRunes := int(float64(sc.config.OverlapTokens) * charsPerToken)
+			if next = end - overlapRunes; next <= start {
+				next = end
+			}
+		}
+		start = next
+	}
+
+	return chunks
+}
+
+// packSegments greedily accumulates segments (sentences or Markdown blocks) into
+// chunks of up to MaxTokens, joining a chunk's segments with joinSep. A segment
+// is never split across chunks, even one larger than MaxTokens on its own.
+func (sc *SemanticChunker) packSegments(segments []string, joinSep string) []string {
+	var chunks []string
+	var current []string
+	currentTokens := 0
+
+	for _, segment := range segments {
+		segmentTokens := EstimateTokens(segment)
+
+		if currentTokens > 0 && currentTokens+segmentTokens > sc.config.MaxTokens {
+			chunks = append(chunks, strings.Join(current, joinSep))
+			current, currentTokens = overlapTail(current, sc.config.OverlapTokens)
+		}
+
+		current = append(current, segment)
+		currentTokens += segmentTokens
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, strings.Join(current, joinSep))
+	}
+
+	return chunks
+}
+
+// overlapTail returns the shortest trailing run of segments whose combined
+// EstimateTokens reaches at least overlapTokens (or all of segments, if they
+// never reach it), for seeding the next chunk, so the tail may overshoot
+// overlapTokens by up to one segment. It returns (nil, 0) when overlapTokens is
+// zero.
+func overlapTail(segments []string, overlapTokens int) ([]string, int) {
+	if overlapTokens <= 0 || len(segments) == 0 {
+		return nil, 0
+	}
+
+	var tail []string
+	tokens := 0
+	for i := len(segments) - 1; i >= 0; i-- {
+		if tokens >= overlapTokens {
+			break
+		}
+		tail = append([]string{segments[i]}, tail...)
+		tokens += EstimateTokens(segments[i])
+	}
+	return tail, tokens
+}
+
+// EstimateTokens approximates how many LLM tokens text would consume, using a
+// cl100k-style heuristic rather than a real tokenizer (the repo doesn't vendor
+// one): roughly 4 ASCII "word" characters per token, and one token per CJK
+// character or punctuation mark, since those are typically single tokens each
+// in real tokenizers.
+func EstimateTokens(text string) int {
+	var counter runeTokenCounter
+	for _, r := range text {
+		counter.add(r)
+	}
+	return counter.tokens
+}
+
+// runeTokenCounter accumulates EstimateTokens' heuristic one rune at a time, so
+// it can also be used to find a token-budget cut point inside a rune slice.
+type runeTokenCounter struct {
+	tokens    int
+	wordChars int
+}
+
+// add folds r into the running token count and returns the updated total.
+func (c *runeTokenCounter) add(r rune) int {
+	switch {
+	case isCJK(r):
+		c.wordChars = 0
+		c.tokens++
+	case unicode.IsSpace(r):
+		c.wordChars = 0
+	case unicode.IsPunct(r) || unicode.IsSymbol(r):
+		c.wordChars = 0
+		c.tokens++
+	default:
+		c.wordChars++
+		// Count a token every 4th word character (1st, 5th, 9th, ...) to
+		// approximate ~4 chars/token for ASCII-like text.
+		if c.wordChars%4 == 1 {
+			c.tokens++
+		}
+	}
+	return c.tokens
+}
+
+// isCJK reports whether r falls in the Hiragana, Katakana, CJK Unified
+// Ideographs, or CJK/fullwidth punctuation ranges, where real tokenizers
+// typically spend one token per character rather than one per ~4 characters.
+func isCJK(r rune) bool {
+	switch {
+	case r >= 0x3040 && r <= 0x30FF: // Hiragana, Katakana
+		return true
+	case r >= 0x4E00 && r <= 0x9FFF: // CJK Unified Ideographs
+		return true
+	case r >= 0x3000 && r <= 0x303F: // CJK punctuation/symbols
+		return true
+	case r >= 0xFF00 && r <= 0xFFEF: // Halfwidth and fullwidth forms
+		return true
+	}
+	return false
+}
+
+// isSentenceTerminator reports whether r ends a sentence, in either ASCII or
+// Japanese/CJK punctuation.
+func isSentenceTerminator(r rune) bool {
+	switch r {
+	case '.', '!', '?', '。', '！', '？':
+		return true
+	}
+	return false
+}
+
+// isCJKSentenceTerminator reports whether r is a CJK sentence terminator, which
+// (unlike ASCII terminators) ends a sentence immediately without needing a
+// following space, since Japanese prose doesn't put a space after 。.
+func isCJKSentenceTerminator(r rune) bool {
+	switch r {
+	case '。', '！', '？':
+		return true
+	}
+	return false
+}
+
+// splitIntoSentences splits text into sentences, rune-safe and aware of both
+// ASCII (". ", "! ", "? ") and CJK ("。", "！", "？") sentence endings.
+func splitIntoSentences(text string) []string {
+	normalized := strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(text)
+	runes := []rune(normalized)
+
+	var sentences []string
+	var current strings.Builder
+
+	for i, r := range runes {
+		current.WriteRune(r)
+
+		if !isSentenceTerminator(r) {
+			continue
+		}
+
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		if isCJKSentenceTerminator(r) || next == 0 || next == ' ' || next == '\t' {
+			if sentence := strings.TrimSpace(current.String()); sentence != "" {
+				sentences = append(sentences, sentence)
+			}
+			current.Reset()
+		}
+	}
+
+	if sentence := strings.TrimSpace(current.String()); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+
+	return sentences
+}
+
+// isFenceDelimiter reports whether line opens or closes a fenced code block.
+func isFenceDelimiter(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// splitIntoMarkdownBlocks splits text into atomic blocks: each heading line
+// starts a new block that absorbs its body until the next heading or fenced
+// code block, and each fenced code block (identified by matching ``` or ~~~
+// delimiters) is always its own block, so neither headings nor code fences are
+// ever split across chunks.
+func splitIntoMarkdownBlocks(text string) []string {
+	lines := strings.Split(text, "\n")
+
+	var blocks []string
+	var current []string
+	inFence := false
+
+	flush := func() {
+		if block := strings.TrimSpace(strings.Join(current, "\n")); block != "" {
+			blocks = append(blocks, block)
+		}
+		current = nil
+	}
+
+	for _, line := range lines {
+		switch {
+		case isFenceDelimiter(line) && !inFence:
+			flush()
+			inFence = true
+			current = append(current, line)
+		case isFenceDelimiter(line) && inFence:
+			current = append(current, line)
+			inFence = false
+			flush()
+		case inFence:
+			current = append(current, line)
+		case strings.HasPrefix(strings.TrimSpace(line), "#"):
+			flush()
+			current = append(current, line)
+		default:
+			current = append(current, line)
+		}
+	}
+	flush()
+
+	return blocks
+}
+
+// ParseChunkStrategy parses the CHUNK_STRATEGY env var / request field value s
+// into a ChunkStrategy, falling back to ChunkStrategyFixed for an empty or
+// unrecognized value.
+func ParseChunkStrategy(s string) ChunkStrategy {
+	switch ChunkStrategy(strings.ToLower(strings.TrimSpace(s))) {
+	case ChunkStrategySentence:
+		return ChunkStrategySentence
+	case ChunkStrategyMarkdown:
+		return ChunkStrategyMarkdown
+	case ChunkStrategySemantic:
+		return ChunkStrategySemantic
+	default:
+		return ChunkStrategyFixed
+	}
+}
+
+// ParseChunkTokens parses an env var / request field value s as a positive
+// token count, falling back to def when s is empty or invalid.
+func ParseChunkTokens(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
@@ -0,0 +1,260 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"aws-serverless-rag/internal/services"
+)
+
+func TestSemanticChunker_Fixed_IsRuneSafe(t *testing.T) {
+	chunker := services.NewSemanticChunker(services.ChunkerConfig{
+		Strategy:  services.ChunkStrategyFixed,
+		MaxTokens: 5,
+	})
+
+	text := strings.Repeat("吾輩は猫である。名前はまだ無い。", 5)
+	chunks := chunker.Chunk(text)
+
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if !utf8.ValidString(chunk) {
+			t.Errorf("chunk %d is not valid UTF-8: %q", i, chunk)
+		}
+	}
+	if strings.Join(chunks, "") != text {
+		t.Error("expected chunks to reconstruct the original text when joined (no overlap configured)")
+	}
+}
+
+func TestSemanticChunker_Fixed_Overlap(t *testing.T) {
+	chunker := services.NewSemanticChunker(services.ChunkerConfig{
+		Strategy:      services.ChunkStrategyFixed,
+		MaxTokens:     10,
+		OverlapTokens: 4,
+	})
+
+	text := strings.Repeat("the quick brown fox jumps over the lazy dog ", 10)
+	chunks := chunker.Chunk(text)
+
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(chunks))
+	}
+	// With overlap configured, consecutive chunks should share a trailing/leading
+	// substring rather than picking up exactly where the previous one left off.
+	if chunks[0] == chunks[1] {
+		t.Error("expected distinct (if overlapping) chunks")
+	}
+}
+
+func TestSemanticChunker_Sentence_NeverSplitsASentence(t *testing.T) {
+	chunker := services.NewSemanticChunker(services.ChunkerConfig{
+		Strategy:  services.ChunkStrategySentence,
+		MaxTokens: 1, // Force one sentence per chunk wherever possible.
+	})
+
+	text := "This is sentence one. This is sentence two. This is sentence three."
+	chunks := chunker.Chunk(text)
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks (one per sentence), got %d: %v", len(chunks), chunks)
+	}
+	for i, want := range []string{"This is sentence one.", "This is sentence two.", "This is sentence three."} {
+		if chunks[i] != want {
+			t.Errorf("chunk %d = %q, want %q", i, chunks[i], want)
+		}
+	}
+}
+
+func TestSemanticChunker_Sentence_RespectsJapanesePunctuation(t *testing.T) {
+	chunker := services.NewSemanticChunker(services.ChunkerConfig{
+		Strategy:  services.ChunkStrategySentence,
+		MaxTokens: 1,
+	})
+
+	text := "今日は晴れです。明日は雨ですか？来週は忙しいです！"
+	chunks := chunker.Chunk(text)
+
+	want := []string{"今日は晴れです。", "明日は雨ですか？", "来週は忙しいです！"}
+	if len(chunks) != len(want) {
+		t.Fatalf("expected %d chunks, got %d: %v", len(want), len(chunks), chunks)
+	}
+	for i := range want {
+		if chunks[i] != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, chunks[i], want[i])
+		}
+	}
+}
+
+func TestSemanticChunker_Markdown_KeepsHeadingWithBody(t *testing.T) {
+	chunker := services.NewSemanticChunker(services.ChunkerConfig{
+		Strategy:  services.ChunkStrategyMarkdown,
+		MaxTokens: 1000,
+	})
+
+	text := "# Title\n\nSome intro text.\n\n## Section\n\nSection body."
+	chunks := chunker.Chunk(text)
+
+	if len(chunks) != 1 {
+		t.Fatalf("expected everything to fit in one chunk given a large budget, got %d: %v", len(chunks), chunks)
+	}
+	if !strings.Contains(chunks[0], "# Title") || !strings.Contains(chunks[0], "Some intro text.") {
+		t.Errorf("expected heading and body together, got %q", chunks[0])
+	}
+}
+
+func TestSemanticChunker_Markdown_NeverSplitsCodeFence(t *testing.T) {
+	chunker := services.NewSemanticChunker(services.ChunkerConfig{
+		Strategy:  services.ChunkStrategyMarkdown,
+		MaxTokens: 1, // Force a new chunk per block wherever possible.
+	})
+
+	text := "# Title\n\n```go\nfunc main() {\n\tprintln(\"hi\")\n}\n```\n\nAfter the code."
+	chunks := chunker.Chunk(text)
+
+	var codeChunk string
+	for _, chunk := range chunks {
+		if strings.Contains(chunk, "```") {
+			codeChunk = chunk
+		}
+	}
+	if codeChunk == "" {
+		t.Fatal("expected one chunk to contain the fenced code block")
+	}
+	if strings.Count(codeChunk, "```") != 2 {
+		t.Errorf("expected the fenced code block to stay intact in a single chunk, got %q", codeChunk)
+	}
+}
+
+func TestSemanticChunker_Semantic_FallsBackToSentencePackingWithoutEmbed(t *testing.T) {
+	chunker := services.NewSemanticChunker(services.ChunkerConfig{
+		Strategy:  services.ChunkStrategySemantic,
+		MaxTokens: 1,
+	})
+
+	text := "This is sentence one. This is sentence two. This is sentence three."
+	chunks := chunker.Chunk(text)
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected one sentence per chunk (sentence-packing fallback), got %d: %v", len(chunks), chunks)
+	}
+}
+
+func TestSemanticChunker_Semantic_FallsBackOnEmbedError(t *testing.T) {
+	chunker := services.NewSemanticChunker(services.ChunkerConfig{
+		Strategy:  services.ChunkStrategySemantic,
+		MaxTokens: 1,
+		Embed: func(text string) ([]float32, error) {
+			return nil, errors.New("bedrock unavailable")
+		},
+	})
+
+	text := "This is sentence one. This is sentence two."
+	chunks := chunker.Chunk(text)
+
+	if len(chunks) != 2 {
+		t.Fatalf("expected fallback to one sentence per chunk, got %d: %v", len(chunks), chunks)
+	}
+}
+
+func TestSemanticChunker_Semantic_BreaksAtLowSimilarity(t *testing.T) {
+	// Two clusters of near-identical sentences about unrelated topics: the
+	// embeddings within a cluster are nearly parallel, while the embedding
+	// between clusters is orthogonal, so the adjacent similarity drops sharply
+	// at the boundary regardless of the 25th-percentile threshold.
+	embeddings := map[string][]float32{
+		"Cats are great pets.":        {1, 0.01, 0},
+		"Cats like to nap a lot.":     {1, 0.02, 0},
+		"Rockets reach orbit fast.":   {0, 0, 1},
+		"Rockets need a lot of fuel.": {0.01, 0, 1},
+	}
+
+	chunker := services.NewSemanticChunker(services.ChunkerConfig{
+		Strategy:  services.ChunkStrategySemantic,
+		MaxTokens: 1000, // Large enough that only similarity drives the break.
+		Embed: func(text string) ([]float32, error) {
+			return embeddings[text], nil
+		},
+	})
+
+	text := "Cats are great pets. Cats like to nap a lot. Rockets reach orbit fast. Rockets need a lot of fuel."
+	chunks := chunker.Chunk(text)
+
+	if len(chunks) != 2 {
+		t.Fatalf("expected a break between the two topic clusters, got %d chunks: %v", len(chunks), chunks)
+	}
+	if !strings.Contains(chunks[0], "Cats") || strings.Contains(chunks[0], "Rockets") {
+		t.Errorf("expected the first chunk to contain only the cats sentences, got %q", chunks[0])
+	}
+	if !strings.Contains(chunks[1], "Rockets") || strings.Contains(chunks[1], "Cats") {
+		t.Errorf("expected the second chunk to contain only the rockets sentences, got %q", chunks[1])
+	}
+}
+
+func TestSemanticChunker_Semantic_Overlap(t *testing.T) {
+	// All sentences are equally similar, so every adjacent similarity equals
+	// the threshold and each sentence (which also fills MaxTokens) starts a new
+	// chunk; OverlapTokens should then repeat the tail of one chunk at the
+	// start of the next.
+	embedding := []float32{1, 0, 0}
+	chunker := services.NewSemanticChunker(services.ChunkerConfig{
+		Strategy:      services.ChunkStrategySemantic,
+		MaxTokens:     6, // Roughly one sentence per chunk.
+		OverlapTokens: 6,
+		Embed: func(text string) ([]float32, error) {
+			return embedding, nil
+		},
+	})
+
+	text := "This is sentence one. This is sentence two. This is sentence three."
+	chunks := chunker.Chunk(text)
+
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %d: %v", len(chunks), chunks)
+	}
+	if !strings.Contains(chunks[1], "This is sentence one.") {
+		t.Errorf("expected the second chunk to start with the first chunk's overlap tail, got %q", chunks[1])
+	}
+}
+
+func TestSemanticChunker_EmptyText(t *testing.T) {
+	chunker := services.NewSemanticChunker(services.ChunkerConfig{})
+	if chunks := chunker.Chunk("   "); chunks != nil {
+		t.Errorf("expected nil chunks for blank text, got %v", chunks)
+	}
+}
+
+func TestParseChunkStrategy(t *testing.T) {
+	tests := map[string]services.ChunkStrategy{
+		"":          services.ChunkStrategyFixed,
+		"fixed":     services.ChunkStrategyFixed,
+		"Sentence":  services.ChunkStrategySentence,
+		"MARKDOWN":  services.ChunkStrategyMarkdown,
+		"Semantic":  services.ChunkStrategySemantic,
+		"not-a-key": services.ChunkStrategyFixed,
+	}
+	for input, want := range tests {
+		if got := services.ParseChunkStrategy(input); got != want {
+			t.Errorf("ParseChunkStrategy(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestParseChunkTokens(t *testing.T) {
+	if got := services.ParseChunkTokens("", 42); got != 42 {
+		t.Errorf("expected default 42 for empty string, got %d", got)
+	}
+	if got := services.ParseChunkTokens("not-a-number", 42); got != 42 {
+		t.Errorf("expected default 42 for invalid input, got %d", got)
+	}
+	if got := services.ParseChunkTokens("0", 42); got != 42 {
+		t.Errorf("expected default 42 for non-positive input, got %d", got)
+	}
+	if got := services.ParseChunkTokens("10", 42); got != 10 {
+		t.Errorf("expected 10, got %d", got)
+	}
+}
@@ -3,6 +3,7 @@ package test
 import (
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"aws-serverless-rag/internal/services"
 )
@@ -34,22 +35,25 @@ func TestTextExtractor_ChunkText(t *testing.T) {
 		{
 			name:          "text requiring multiple chunks",
 			text:          "This is sentence one. This is sentence two. This is sentence three. This is sentence four.",
-			maxChunkSize:  30,
-			expectedCount: 4, // Adjusted based on actual sentence splitting behavior
-			description:   "Text longer than chunk size should be split into multiple chunks",
+			maxChunkSize:  6, // Each sentence alone is ~6-7 estimated tokens.
+			expectedCount: 4,
+			description:   "Text longer than the token budget should be split one sentence per chunk",
 		},
 		{
 			name:          "text with default chunk size",
 			text:          "This is a test sentence.",
-			maxChunkSize:  0, // Should use default
+			maxChunkSize:  0, // Should use services.DefaultMaxTokens
 			expectedCount: 1,
-			description:   "Zero chunk size should use default value",
+			description:   "Zero chunk size should use the default token budget",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			chunks := extractor.ChunkText(tt.text, tt.maxChunkSize)
+			chunks := extractor.ChunkText(tt.text, services.ChunkTextOptions{
+				MaxTokens: tt.maxChunkSize,
+				Strategy:  services.ChunkStrategySentence,
+			})
 
 			if len(chunks) != tt.expectedCount {
 				t.Errorf("ChunkText() got %d chunks, want %d chunks for test: %s",
@@ -65,11 +69,12 @@ func TestTextExtractor_ChunkText(t *testing.T) {
 				}
 			}
 
-			// Verify that all chunks respect the size limit (with some tolerance for sentence boundaries)
+			// Verify every chunk respects the token budget (with some tolerance, since
+			// a chunk never splits a sentence even if that sentence alone is over budget).
 			if tt.maxChunkSize > 0 {
 				for i, chunk := range chunks {
-					if len(chunk) > tt.maxChunkSize*2 { // Allow some tolerance
-						t.Errorf("Chunk %d exceeds size limit: got %d, max %d", i, len(chunk), tt.maxChunkSize)
+					if tokens := services.EstimateTokens(chunk); tokens > tt.maxChunkSize*2 {
+						t.Errorf("Chunk %d exceeds token budget: got ~%d tokens, max %d", i, tokens, tt.maxChunkSize)
 					}
 				}
 			}
@@ -77,6 +82,52 @@ func TestTextExtractor_ChunkText(t *testing.T) {
 	}
 }
 
+func TestTextExtractor_ChunkText_IsRuneSafeForJapanese(t *testing.T) {
+	extractor := services.NewTextExtractor()
+
+	text := "これは最初の文です。これは二番目の文です。これは三番目の文です。"
+	chunks := extractor.ChunkText(text, services.ChunkTextOptions{MaxTokens: 4, Strategy: services.ChunkStrategySentence})
+
+	if len(chunks) == 0 {
+		t.Fatal("expected at least one chunk")
+	}
+	for i, chunk := range chunks {
+		if !utf8.ValidString(chunk) {
+			t.Errorf("chunk %d is not valid UTF-8: %q", i, chunk)
+		}
+	}
+}
+
+func TestTextExtractor_ExtractTextStream(t *testing.T) {
+	extractor := services.NewTextExtractor()
+
+	text := "This is sentence one. This is sentence two. This is sentence three. This is sentence four."
+	out, err := extractor.ExtractTextStream(strings.NewReader(text), "text/plain")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for chunk := range out {
+		got = append(got, chunk)
+	}
+
+	if strings.Join(got, " ") != text {
+		t.Errorf("streamed chunks joined = %q, want %q", strings.Join(got, " "), text)
+	}
+	if len(got) < 2 {
+		t.Errorf("expected multiple streamed chunks, got %d: %v", len(got), got)
+	}
+}
+
+func TestTextExtractor_ExtractTextStream_UnsupportedContentType(t *testing.T) {
+	extractor := services.NewTextExtractor()
+
+	if _, err := extractor.ExtractTextStream(strings.NewReader("anything"), "application/pdf"); err == nil {
+		t.Fatal("expected an error for a content type with no streaming extractor")
+	}
+}
+
 func TestTextExtractor_GetMetadata(t *testing.T) {
 	extractor := services.NewTextExtractor()
 
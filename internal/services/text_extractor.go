@@ -1,11 +1,17 @@
 package services
 
 import (
+	"fmt"
+	"io"
 	"strings"
 
 	"aws-serverless-rag/internal/models"
 )
 
+// streamReadWindow is how many bytes ExtractTextStream reads from its io.Reader at
+// a time before re-splitting the accumulated text into sentences.
+const streamReadWindow = 64 * 1024
+
 // TextExtractor handles text extraction from different file formats
 type TextExtractor struct {
 	officeExtractor *OfficeExtractor
@@ -39,85 +45,111 @@ func (te *TextExtractor) extractFromPDF(content []byte) (string, error) {
 	return te.officeExtractor.ExtractFromPDF(content)
 }
 
-
-
 // extractFromDOCX extracts text from Word documents
 func (te *TextExtractor) extractFromDOCX(content []byte) (string, error) {
 	return te.officeExtractor.ExtractFromDOCX(content)
 }
 
-
-
 // extractFromPPTX extracts text from PowerPoint presentations
 func (te *TextExtractor) extractFromPPTX(content []byte) (string, error) {
 	return te.officeExtractor.ExtractFromPPTX(content)
 }
 
-// ChunkText splits text into chunks suitable for embedding
-func (te *TextExtractor) ChunkText(text string, maxChunkSize int) []string {
-	if maxChunkSize <= 0 {
-		maxChunkSize = 1000 // Default chunk size
-	}
-
-	// Simple sentence-based chunking
-	sentences := te.splitIntoSentences(text)
-	var chunks []string
-	var currentChunk strings.Builder
-
-	for _, sentence := range sentences {
-		sentence = strings.TrimSpace(sentence)
-		if sentence == "" {
-			continue
-		}
-
-		// If adding this sentence would exceed max size, start a new chunk
-		if currentChunk.Len() > 0 && currentChunk.Len()+len(sentence)+1 > maxChunkSize {
-			chunks = append(chunks, strings.TrimSpace(currentChunk.String()))
-			currentChunk.Reset()
-		}
-
-		if currentChunk.Len() > 0 {
-			currentChunk.WriteString(" ")
-		}
-		currentChunk.WriteString(sentence)
-	}
-
-	// Add the last chunk if it has content
-	if currentChunk.Len() > 0 {
-		chunks = append(chunks, strings.TrimSpace(currentChunk.String()))
-	}
-
-	return chunks
+// ChunkTextOptions configures TextExtractor.ChunkText.
+type ChunkTextOptions struct {
+	// MaxTokens is the approximate per-chunk token budget (see EstimateTokens).
+	// DefaultMaxTokens is used if zero or negative.
+	MaxTokens int
+	// OverlapTokens is how many trailing tokens of a chunk are repeated at the
+	// start of the next one. Zero disables overlap.
+	OverlapTokens int
+	// Strategy selects the chunking strategy: ChunkStrategyFixed (the zero
+	// value), ChunkStrategySentence, ChunkStrategyMarkdown, or
+	// ChunkStrategySemantic.
+	Strategy ChunkStrategy
+	// Embed is required by ChunkStrategySemantic (e.g.
+	// BedrockClient.GenerateEmbedding); ignored by every other strategy.
+	Embed EmbedFunc
+	// SimilarityPercentile is the adjacent-sentence cosine-similarity
+	// percentile below which ChunkStrategySemantic starts a new chunk.
+	// DefaultSimilarityPercentile is used if zero or negative. Ignored by
+	// every other strategy.
+	SimilarityPercentile float64
 }
 
-// splitIntoSentences splits text into sentences
-func (te *TextExtractor) splitIntoSentences(text string) []string {
-	// Simple sentence splitting based on common sentence endings
-	text = strings.ReplaceAll(text, "\n", " ")
-	text = strings.ReplaceAll(text, "\r", " ")
+// ChunkText splits text into chunks suitable for embedding, according to opts.
+// Splitting is rune-safe, respecting both ASCII and Japanese/CJK sentence
+// punctuation; see SemanticChunker for what each strategy does.
+func (te *TextExtractor) ChunkText(text string, opts ChunkTextOptions) []string {
+	chunker := NewSemanticChunker(ChunkerConfig{
+		Strategy:             opts.Strategy,
+		MaxTokens:            opts.MaxTokens,
+		OverlapTokens:        opts.OverlapTokens,
+		Embed:                opts.Embed,
+		SimilarityPercentile: opts.SimilarityPercentile,
+	})
+	return chunker.Chunk(text)
+}
 
-	// Split on sentence endings
-	sentences := []string{}
-	current := ""
+// ExtractTextStream extracts chunk-sized text segments from r as it is read,
+// rather than buffering the whole file in memory first like ExtractText does. It
+// only supports the plain-text content types (text/plain, text/markdown); PDF and
+// DOCX extraction (go-fitz, unioffice) operate on a complete in-memory buffer and
+// have no streaming API, so callers handling those types should keep using
+// ExtractText with a fully downloaded buffer.
+//
+// The returned channel emits chunks in order using the sentence strategy (see
+// SemanticChunker) and is closed once r is exhausted or returns an error; a
+// mid-stream read error is logged and ends the stream early rather than being
+// surfaced through the channel, since the signature has no error channel.
+func (te *TextExtractor) ExtractTextStream(r io.Reader, contentType string) (<-chan string, error) {
+	switch contentType {
+	case "text/plain", "text/markdown":
+	default:
+		return nil, fmt.Errorf("ExtractTextStream does not support streaming extraction for %s; download the full object and use ExtractText instead", contentType)
+	}
 
-	for i, char := range text {
-		current += string(char)
+	out := make(chan string)
+
+	go func() {
+		defer close(out)
+
+		chunker := NewSemanticChunker(ChunkerConfig{Strategy: ChunkStrategySentence, MaxTokens: DefaultMaxTokens})
+		var pending strings.Builder
+		buf := make([]byte, streamReadWindow)
+
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				pending.Write(buf[:n])
+
+				sentences := splitIntoSentences(pending.String())
+				if len(sentences) > 1 {
+					complete := strings.Join(sentences[:len(sentences)-1], " ")
+					for _, chunk := range chunker.Chunk(complete) {
+						out <- chunk
+					}
+					pending.Reset()
+					pending.WriteString(sentences[len(sentences)-1])
+				}
+			}
 
-		if char == '.' || char == '!' || char == '?' {
-			// Look ahead to see if this is actually the end of a sentence
-			if i+1 < len(text) && (text[i+1] == ' ' || text[i+1] == '\n' || text[i+1] == '\t') {
-				sentences = append(sentences, strings.TrimSpace(current))
-				current = ""
+			if err == io.EOF {
+				if pending.Len() > 0 {
+					for _, chunk := range chunker.Chunk(pending.String()) {
+						out <- chunk
+					}
+				}
+				return
+			}
+			if err != nil {
+				fmt.Printf("ExtractTextStream: read error, ending stream early: %v\n", err)
+				return
 			}
 		}
-	}
-
-	// Add any remaining text
-	if strings.TrimSpace(current) != "" {
-		sentences = append(sentences, strings.TrimSpace(current))
-	}
+	}()
 
-	return sentences
+	return out, nil
 }
 
 // GetMetadata extracts metadata from the text and file
@@ -0,0 +1,59 @@
+package sigv4
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// SecretsManagerClient is the subset of *secretsmanager.SecretsManager used to
+// resolve signing secrets.
+type SecretsManagerClient interface {
+	GetSecretValue(input *secretsmanager.GetSecretValueInput) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// SecretsManagerLookup builds a SecretLookup that fetches the secret for
+// accessKeyID from Secrets Manager, stored under "{secretPrefix}{accessKeyID}".
+func SecretsManagerLookup(client SecretsManagerClient, secretPrefix string) SecretLookup {
+	return func(accessKeyID string) (string, bool, error) {
+		out, err := client.GetSecretValue(&secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(secretPrefix + accessKeyID),
+		})
+		if err != nil {
+			var awsErr awserr.Error
+			if errors.As(err, &awsErr) && awsErr.Code() == secretsmanager.ErrCodeResourceNotFoundException {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		return aws.StringValue(out.SecretString), true, nil
+	}
+}
+
+// SSMClient is the subset of *ssm.SSM used to resolve signing secrets.
+type SSMClient interface {
+	GetParameter(input *ssm.GetParameterInput) (*ssm.GetParameterOutput, error)
+}
+
+// SSMParameterStoreLookup builds a SecretLookup that fetches the secret for
+// accessKeyID from SSM Parameter Store, stored under
+// "{parameterPrefix}{accessKeyID}" as a SecureString.
+func SSMParameterStoreLookup(client SSMClient, parameterPrefix string) SecretLookup {
+	return func(accessKeyID string) (string, bool, error) {
+		out, err := client.GetParameter(&ssm.GetParameterInput{
+			Name:           aws.String(parameterPrefix + accessKeyID),
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			var awsErr awserr.Error
+			if errors.As(err, &awsErr) && awsErr.Code() == ssm.ErrCodeParameterNotFound {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		return aws.StringValue(out.Parameter.Value), true, nil
+	}
+}
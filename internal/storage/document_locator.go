@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// DocumentLocator finds the S3 object backing a document ID within a bucket/prefix,
+// matching either on the `document-id` object metadata or on the object's filename.
+type DocumentLocator struct {
+	client S3APIClient
+	bucket string
+	prefix string
+}
+
+// NewDocumentLocator creates a locator scoped to a single bucket and key prefix.
+func NewDocumentLocator(client S3APIClient, bucket, prefix string) *DocumentLocator {
+	return &DocumentLocator{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+// Locate searches the bucket for an object matching documentID, trying the URL-decoded
+// form as well so Japanese and other percent-encoded filenames still match. It walks the
+// full ListObjectsV2 result set, following continuation tokens across pages.
+func (l *DocumentLocator) Locate(documentID string) (string, error) {
+	decodedDocumentID, err := url.QueryUnescape(documentID)
+	if err != nil {
+		decodedDocumentID = documentID
+	}
+
+	var continuationToken *string
+
+	for {
+		result, err := l.client.ListObjectsV2(&s3.ListObjectsV2Input{
+			Bucket:            aws.String(l.bucket),
+			Prefix:            aws.String(l.prefix),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to list S3 objects: %w", err)
+		}
+
+		for _, item := range result.Contents {
+			if strings.HasSuffix(*item.Key, "/") {
+				continue // directory marker
+			}
+
+			keyParts := strings.Split(*item.Key, "/")
+			filename := keyParts[len(keyParts)-1]
+
+			headResult, err := l.client.HeadObject(&s3.HeadObjectInput{
+				Bucket: aws.String(l.bucket),
+				Key:    item.Key,
+			})
+			if err != nil {
+				continue // object we can't access, keep scanning
+			}
+
+			docID := filename // fallback to filename
+			if headResult.Metadata["document-id"] != nil {
+				docID = *headResult.Metadata["document-id"]
+			}
+
+			if docID == documentID || docID == decodedDocumentID ||
+				strings.Contains(docID, documentID) || strings.Contains(docID, decodedDocumentID) ||
+				strings.Contains(filename, documentID) || strings.Contains(filename, decodedDocumentID) {
+				return *item.Key, nil
+			}
+		}
+
+		if result.IsTruncated == nil || !*result.IsTruncated {
+			break
+		}
+		continuationToken = result.NextContinuationToken
+	}
+
+	return "", fmt.Errorf("document with ID %s not found", documentID)
+}
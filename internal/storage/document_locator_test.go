@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage/mocks"
+)
+
+func TestDocumentLocator_Locate(t *testing.T) {
+	japaneseName := "documents/prod/" + "日本語のファイル.pdf"
+	encodedJapaneseName := url.QueryEscape("日本語のファイル.pdf")
+
+	tests := []struct {
+		name       string
+		documentID string
+		objects    []*s3.Object
+		metadata   map[string]map[string]*string // key -> metadata
+		wantKey    string
+		wantErr    bool
+	}{
+		{
+			name:       "matches by document-id metadata",
+			documentID: "doc-123",
+			objects:    []*s3.Object{{Key: aws.String("documents/prod/report.pdf")}},
+			metadata: map[string]map[string]*string{
+				"documents/prod/report.pdf": {"document-id": aws.String("doc-123")},
+			},
+			wantKey: "documents/prod/report.pdf",
+		},
+		{
+			name:       "matches URL-decoded Japanese filename",
+			documentID: encodedJapaneseName,
+			objects:    []*s3.Object{{Key: aws.String(japaneseName)}},
+			metadata: map[string]map[string]*string{
+				japaneseName: {},
+			},
+			wantKey: japaneseName,
+		},
+		{
+			name:       "matches by partial filename",
+			documentID: "invoice",
+			objects:    []*s3.Object{{Key: aws.String("documents/prod/invoice-2024.pdf")}},
+			metadata: map[string]map[string]*string{
+				"documents/prod/invoice-2024.pdf": {},
+			},
+			wantKey: "documents/prod/invoice-2024.pdf",
+		},
+		{
+			name:       "not found",
+			documentID: "missing",
+			objects:    []*s3.Object{{Key: aws.String("documents/prod/other.pdf")}},
+			metadata: map[string]map[string]*string{
+				"documents/prod/other.pdf": {},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.S3Client{
+				ListObjectsV2Func: func(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+					return &s3.ListObjectsV2Output{Contents: tt.objects}, nil
+				},
+				HeadObjectFunc: func(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+					return &s3.HeadObjectOutput{Metadata: tt.metadata[*input.Key]}, nil
+				},
+			}
+
+			locator := NewDocumentLocator(client, "bucket", "documents/prod/")
+			key, err := locator.Locate(tt.documentID)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got key %q", key)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if key != tt.wantKey {
+				t.Errorf("got key %q, want %q", key, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestDocumentLocator_Locate_FollowsContinuationToken(t *testing.T) {
+	pageOne := []*s3.Object{{Key: aws.String("documents/prod/a.pdf")}}
+	pageTwo := []*s3.Object{{Key: aws.String("documents/prod/b.pdf")}}
+	metadata := map[string]map[string]*string{
+		"documents/prod/a.pdf": {},
+		"documents/prod/b.pdf": {"document-id": aws.String("doc-b")},
+	}
+
+	calls := 0
+	client := &mocks.S3Client{
+		ListObjectsV2Func: func(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+			calls++
+			if input.ContinuationToken == nil {
+				truthy := true
+				return &s3.ListObjectsV2Output{
+					Contents:              pageOne,
+					IsTruncated:           &truthy,
+					NextContinuationToken: aws.String("page-2"),
+				}, nil
+			}
+			return &s3.ListObjectsV2Output{Contents: pageTwo}, nil
+		},
+		HeadObjectFunc: func(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return &s3.HeadObjectOutput{Metadata: metadata[*input.Key]}, nil
+		},
+	}
+
+	locator := NewDocumentLocator(client, "bucket", "documents/prod/")
+	key, err := locator.Locate("doc-b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key != "documents/prod/b.pdf" {
+		t.Errorf("got key %q, want documents/prod/b.pdf", key)
+	}
+	if calls != 2 {
+		t.Errorf("expected ListObjectsV2 to be called across 2 pages, got %d calls", calls)
+	}
+}
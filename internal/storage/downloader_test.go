@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage/mocks"
+)
+
+func TestDownloader_Download(t *testing.T) {
+	manager := &mocks.Downloader{
+		DownloadWithContextFunc: func(input *s3.GetObjectInput) ([]byte, error) {
+			if aws.StringValue(input.Key) != "documents/prod/a.pdf" {
+				t.Errorf("got key %q, want documents/prod/a.pdf", aws.StringValue(input.Key))
+			}
+			if input.Range != nil {
+				t.Errorf("got Range %q, want none for a full download", aws.StringValue(input.Range))
+			}
+			return []byte("full contents"), nil
+		},
+	}
+
+	downloader := newDownloader(manager, "bucket")
+	body, err := downloader.Download(context.Background(), "documents/prod/a.pdf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != "full contents" {
+		t.Errorf("got %q, want %q", body, "full contents")
+	}
+}
+
+func TestDownloader_DownloadRange(t *testing.T) {
+	manager := &mocks.Downloader{
+		DownloadWithContextFunc: func(input *s3.GetObjectInput) ([]byte, error) {
+			if got, want := aws.StringValue(input.Range), "bytes=10-19"; got != want {
+				t.Errorf("got Range %q, want %q", got, want)
+			}
+			return []byte("0123456789"), nil
+		},
+	}
+
+	downloader := newDownloader(manager, "bucket")
+	body, err := downloader.DownloadRange(context.Background(), "documents/prod/a.pdf", 10, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != "0123456789" {
+		t.Errorf("got %q, want %q", body, "0123456789")
+	}
+}
@@ -0,0 +1,22 @@
+package storage
+
+import "os"
+
+// DocumentBucketFromEnv returns the document S3 bucket name from DOCUMENT_BUCKET,
+// falling back to the production bucket name so existing deployments keep working
+// without redeploying their environment variables.
+func DocumentBucketFromEnv() string {
+	if bucket := os.Getenv("DOCUMENT_BUCKET"); bucket != "" {
+		return bucket
+	}
+	return "aws-serverless-rag-prod-documents-prod"
+}
+
+// DocumentPrefixFromEnv returns the S3 key prefix documents are stored under, from
+// DOCUMENT_PREFIX, defaulting to the prefix every existing deployment already uses.
+func DocumentPrefixFromEnv() string {
+	if prefix := os.Getenv("DOCUMENT_PREFIX"); prefix != "" {
+		return prefix
+	}
+	return "documents/prod/"
+}
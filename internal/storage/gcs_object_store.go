@@ -0,0 +1,261 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// GCSObjectStore is an ObjectStore backed by the Google Cloud Storage JSON API,
+// scoped to a single bucket. There is no vendored Google Cloud SDK in this module
+// (go.mod only carries the AWS SDK), so this talks to the JSON API directly over
+// net/http rather than pulling in cloud.google.com/go/storage, mirroring how
+// internal/backend/openaiprovider calls a REST API by hand instead of vendoring
+// an SDK.
+//
+// Authentication is a bearer access token rather than a full service-account-key
+// OAuth2 flow: GCSObjectStore expects something else (Workload Identity, a
+// metadata-server token, a sidecar refresher) to keep TokenSource returning a
+// fresh token. GCSTokenFromEnv is the simplest such source, reading a
+// pre-minted token from an environment variable.
+type GCSObjectStore struct {
+	bucket      string
+	tokenSource func() (string, error)
+	httpClient  *http.Client
+	baseURL     string // overridable in tests; defaults to the real JSON API host.
+}
+
+// GCSTokenFromEnv reads a pre-minted OAuth2 access token from GCS_ACCESS_TOKEN.
+// It is meant to be paired with a process that keeps the env var refreshed (e.g.
+// gcloud auth print-access-token on a timer, or a Workload Identity sidecar);
+// GCSObjectStore does not perform the OAuth2 token exchange itself.
+func GCSTokenFromEnv() (string, error) {
+	token := os.Getenv("GCS_ACCESS_TOKEN")
+	if token == "" {
+		return "", fmt.Errorf("GCS_ACCESS_TOKEN is not set")
+	}
+	return token, nil
+}
+
+// NewGCSObjectStore creates a GCSObjectStore scoped to bucket, using tokenSource to
+// obtain a bearer token for each request.
+func NewGCSObjectStore(bucket string, tokenSource func() (string, error)) *GCSObjectStore {
+	return &GCSObjectStore{
+		bucket:      bucket,
+		tokenSource: tokenSource,
+		httpClient:  &http.Client{Timeout: 60 * time.Second},
+		baseURL:     "https://storage.googleapis.com",
+	}
+}
+
+func (g *GCSObjectStore) authorize(req *http.Request) error {
+	token, err := g.tokenSource()
+	if err != nil {
+		return fmt.Errorf("failed to obtain GCS access token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+type gcsObject struct {
+	Name        string            `json:"name"`
+	Size        string            `json:"size"`
+	Updated     string            `json:"updated"`
+	ContentType string            `json:"contentType"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+type gcsListResponse struct {
+	Items         []gcsObject `json:"items"`
+	NextPageToken string      `json:"nextPageToken"`
+}
+
+// List returns every object whose key starts with prefix, following page tokens
+// across pages of the JSON API's objects.list response.
+func (g *GCSObjectStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var objects []ObjectInfo
+	pageToken := ""
+
+	for {
+		reqURL := fmt.Sprintf("%s/storage/v1/b/%s/o?prefix=%s", g.baseURL, url.PathEscape(g.bucket), url.QueryEscape(prefix))
+		if pageToken != "" {
+			reqURL += "&pageToken=" + url.QueryEscape(pageToken)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build GCS list request: %w", err)
+		}
+		if err := g.authorize(req); err != nil {
+			return nil, err
+		}
+
+		var page gcsListResponse
+		if err := g.do(req, &page); err != nil {
+			return nil, fmt.Errorf("failed to list GCS objects: %w", err)
+		}
+
+		for _, item := range page.Items {
+			size, _ := strconv.ParseInt(item.Size, 10, 64)
+			lastModified, _ := time.Parse(time.RFC3339, item.Updated)
+			objects = append(objects, ObjectInfo{
+				Key:          item.Name,
+				Size:         size,
+				LastModified: lastModified,
+			})
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+
+	return objects, nil
+}
+
+// Head returns key's content type and user metadata.
+func (g *GCSObjectStore) Head(ctx context.Context, key string) (Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return Metadata{}, err
+	}
+
+	reqURL := fmt.Sprintf("%s/storage/v1/b/%s/o/%s", g.baseURL, url.PathEscape(g.bucket), url.PathEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("failed to build GCS head request: %w", err)
+	}
+	if err := g.authorize(req); err != nil {
+		return Metadata{}, err
+	}
+
+	var obj gcsObject
+	if err := g.do(req, &obj); err != nil {
+		return Metadata{}, fmt.Errorf("failed to head GCS object %s: %w", key, err)
+	}
+
+	return Metadata{ContentType: obj.ContentType, UserMetadata: obj.Metadata}, nil
+}
+
+// Get returns key's full body via the JSON API's media download (alt=media).
+func (g *GCSObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", g.baseURL, url.PathEscape(g.bucket), url.PathEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GCS get request: %w", err)
+	}
+	if err := g.authorize(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GCS object %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GCS object %s: %w", key, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get GCS object %s: status %d: %s", key, resp.StatusCode, body)
+	}
+
+	return body, nil
+}
+
+// Put writes body to key with the given content type via a simple (non-resumable)
+// media upload.
+func (g *GCSObjectStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	reqURL := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		g.baseURL, url.PathEscape(g.bucket), url.QueryEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build GCS put request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if err := g.authorize(req); err != nil {
+		return err
+	}
+
+	if err := g.do(req, nil); err != nil {
+		return fmt.Errorf("failed to put GCS object %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key. Deleting a key that doesn't exist is not an error, matching
+// the other ObjectStore implementations.
+func (g *GCSObjectStore) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	reqURL := fmt.Sprintf("%s/storage/v1/b/%s/o/%s", g.baseURL, url.PathEscape(g.bucket), url.PathEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, reqURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build GCS delete request: %w", err)
+	}
+	if err := g.authorize(req); err != nil {
+		return err
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete GCS object %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to delete GCS object %s: status %d: %s", key, resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+// do executes req and decodes a JSON response body into out (if out is non-nil),
+// returning an error built from the response body for any non-2xx status.
+func (g *GCSObjectStore) do(req *http.Request, out interface{}) error {
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, body)
+	}
+
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
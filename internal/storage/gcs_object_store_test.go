@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGCSObjectStore_ListHeadGetPutDelete(t *testing.T) {
+	objects := map[string][]byte{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("expected bearer token on request, got %q", r.Header.Get("Authorization"))
+		}
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/upload/storage/v1/b/my-bucket/o":
+			body := make([]byte, r.ContentLength)
+			r.Body.Read(body)
+			objects[r.URL.Query().Get("name")] = body
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/my-bucket/o":
+			fmt.Fprintf(w, `{"items":[{"name":"documents/prod/a.txt","size":"5","updated":"2024-01-01T00:00:00Z","contentType":"text/plain"}]}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/my-bucket/o/documents/prod/a.txt" && r.URL.Query().Get("alt") == "media":
+			w.Write(objects["documents/prod/a.txt"])
+		case r.Method == http.MethodGet && r.URL.Path == "/storage/v1/b/my-bucket/o/documents/prod/a.txt":
+			fmt.Fprintf(w, `{"contentType":"text/plain","metadata":{"document-id":"doc-123"}}`)
+		case r.Method == http.MethodDelete && r.URL.Path == "/storage/v1/b/my-bucket/o/documents/prod/a.txt":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	store := NewGCSObjectStore("my-bucket", func() (string, error) { return "test-token", nil })
+	store.baseURL = server.URL
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "documents/prod/a.txt", []byte("hello"), "text/plain"); err != nil {
+		t.Fatalf("unexpected error from Put: %v", err)
+	}
+
+	objectList, err := store.List(ctx, "documents/prod/")
+	if err != nil || len(objectList) != 1 || objectList[0].Key != "documents/prod/a.txt" {
+		t.Fatalf("got (%+v, %v), want one object documents/prod/a.txt", objectList, err)
+	}
+
+	meta, err := store.Head(ctx, "documents/prod/a.txt")
+	if err != nil || meta.ContentType != "text/plain" || meta.UserMetadata["document-id"] != "doc-123" {
+		t.Fatalf("got (%+v, %v), want text/plain with document-id doc-123", meta, err)
+	}
+
+	body, err := store.Get(ctx, "documents/prod/a.txt")
+	if err != nil || string(body) != "hello" {
+		t.Fatalf("got (%q, %v), want (\"hello\", nil)", body, err)
+	}
+
+	if err := store.Delete(ctx, "documents/prod/a.txt"); err != nil {
+		t.Fatalf("unexpected error from Delete: %v", err)
+	}
+}
+
+func TestGCSObjectStore_TokenSourceError(t *testing.T) {
+	store := NewGCSObjectStore("my-bucket", func() (string, error) { return "", fmt.Errorf("no token") })
+
+	if _, err := store.Get(context.Background(), "documents/prod/a.txt"); err == nil {
+		t.Fatal("expected an error when the token source fails")
+	}
+}
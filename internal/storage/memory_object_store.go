@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryObject struct {
+	body         []byte
+	contentType  string
+	userMetadata map[string]string
+	lastModified time.Time
+}
+
+// MemoryObjectStore is an in-memory ObjectStore for tests and local development. It
+// requires no AWS credentials and no network access.
+type MemoryObjectStore struct {
+	mu      sync.Mutex
+	objects map[string]memoryObject
+}
+
+// NewMemoryObjectStore creates an empty MemoryObjectStore.
+func NewMemoryObjectStore() *MemoryObjectStore {
+	return &MemoryObjectStore{objects: make(map[string]memoryObject)}
+}
+
+// List returns every object whose key starts with prefix, in no particular order.
+func (m *MemoryObjectStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var objects []ObjectInfo
+	for key, obj := range m.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          key,
+			Size:         int64(len(obj.body)),
+			LastModified: obj.lastModified,
+		})
+	}
+
+	return objects, nil
+}
+
+// Head returns key's content type and user metadata.
+func (m *MemoryObjectStore) Head(ctx context.Context, key string) (Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return Metadata{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.objects[key]
+	if !ok {
+		return Metadata{}, fmt.Errorf("object %s not found", key)
+	}
+
+	return Metadata{ContentType: obj.contentType, UserMetadata: obj.userMetadata}, nil
+}
+
+// Get returns key's full body.
+func (m *MemoryObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("object %s not found", key)
+	}
+
+	return obj.body, nil
+}
+
+// Put writes body to key with the given content type.
+func (m *MemoryObjectStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.objects[key] = memoryObject{
+		body:         body,
+		contentType:  contentType,
+		userMetadata: map[string]string{},
+		lastModified: time.Now(),
+	}
+	return nil
+}
+
+// Delete removes key. Deleting a key that doesn't exist is not an error.
+func (m *MemoryObjectStore) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.objects, key)
+	return nil
+}
+
+// PutWithMetadata is a test helper for seeding an object with user metadata (e.g.
+// "document-id"), which Put alone has no way to set.
+func (m *MemoryObjectStore) PutWithMetadata(key string, body []byte, contentType string, userMetadata map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.objects[key] = memoryObject{
+		body:         body,
+		contentType:  contentType,
+		userMetadata: userMetadata,
+		lastModified: time.Now(),
+	}
+}
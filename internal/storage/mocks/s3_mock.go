@@ -0,0 +1,129 @@
+// Package mocks provides a hand-rolled test double for storage.S3APIClient.
+package mocks
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3Client is an in-memory stand-in for storage.S3APIClient. Each method call is
+// recorded and its response/error is resolved from the corresponding *Func field,
+// falling back to a zero value when unset.
+type S3Client struct {
+	ListObjectsV2Func func(*s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error)
+	HeadObjectFunc    func(*s3.HeadObjectInput) (*s3.HeadObjectOutput, error)
+	DeleteObjectFunc  func(*s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error)
+	PutObjectFunc     func(*s3.PutObjectInput) (*s3.PutObjectOutput, error)
+	GetObjectFunc     func(*s3.GetObjectInput) (*s3.GetObjectOutput, error)
+
+	CreateMultipartUploadFunc   func(*s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error)
+	UploadPartFunc              func(*s3.UploadPartInput) (*s3.UploadPartOutput, error)
+	CompleteMultipartUploadFunc func(*s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUploadFunc    func(*s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error)
+	ListMultipartUploadsFunc    func(*s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error)
+
+	CopyObjectWithContextFunc func(*s3.CopyObjectInput) (*s3.CopyObjectOutput, error)
+
+	ListObjectsV2Calls []*s3.ListObjectsV2Input
+	HeadObjectCalls    []*s3.HeadObjectInput
+	DeleteObjectCalls  []*s3.DeleteObjectInput
+	PutObjectCalls     []*s3.PutObjectInput
+	GetObjectCalls     []*s3.GetObjectInput
+
+	CreateMultipartUploadCalls   []*s3.CreateMultipartUploadInput
+	UploadPartCalls              []*s3.UploadPartInput
+	CompleteMultipartUploadCalls []*s3.CompleteMultipartUploadInput
+	AbortMultipartUploadCalls    []*s3.AbortMultipartUploadInput
+	ListMultipartUploadsCalls    []*s3.ListMultipartUploadsInput
+
+	CopyObjectWithContextCalls []*s3.CopyObjectInput
+}
+
+func (m *S3Client) ListObjectsV2(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+	m.ListObjectsV2Calls = append(m.ListObjectsV2Calls, input)
+	if m.ListObjectsV2Func != nil {
+		return m.ListObjectsV2Func(input)
+	}
+	return &s3.ListObjectsV2Output{}, nil
+}
+
+func (m *S3Client) HeadObject(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+	m.HeadObjectCalls = append(m.HeadObjectCalls, input)
+	if m.HeadObjectFunc != nil {
+		return m.HeadObjectFunc(input)
+	}
+	return &s3.HeadObjectOutput{}, nil
+}
+
+func (m *S3Client) DeleteObject(input *s3.DeleteObjectInput) (*s3.DeleteObjectOutput, error) {
+	m.DeleteObjectCalls = append(m.DeleteObjectCalls, input)
+	if m.DeleteObjectFunc != nil {
+		return m.DeleteObjectFunc(input)
+	}
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (m *S3Client) PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	m.PutObjectCalls = append(m.PutObjectCalls, input)
+	if m.PutObjectFunc != nil {
+		return m.PutObjectFunc(input)
+	}
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (m *S3Client) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	m.GetObjectCalls = append(m.GetObjectCalls, input)
+	if m.GetObjectFunc != nil {
+		return m.GetObjectFunc(input)
+	}
+	return &s3.GetObjectOutput{}, nil
+}
+
+func (m *S3Client) CreateMultipartUpload(input *s3.CreateMultipartUploadInput) (*s3.CreateMultipartUploadOutput, error) {
+	m.CreateMultipartUploadCalls = append(m.CreateMultipartUploadCalls, input)
+	if m.CreateMultipartUploadFunc != nil {
+		return m.CreateMultipartUploadFunc(input)
+	}
+	return &s3.CreateMultipartUploadOutput{}, nil
+}
+
+func (m *S3Client) UploadPart(input *s3.UploadPartInput) (*s3.UploadPartOutput, error) {
+	m.UploadPartCalls = append(m.UploadPartCalls, input)
+	if m.UploadPartFunc != nil {
+		return m.UploadPartFunc(input)
+	}
+	return &s3.UploadPartOutput{}, nil
+}
+
+func (m *S3Client) CompleteMultipartUpload(input *s3.CompleteMultipartUploadInput) (*s3.CompleteMultipartUploadOutput, error) {
+	m.CompleteMultipartUploadCalls = append(m.CompleteMultipartUploadCalls, input)
+	if m.CompleteMultipartUploadFunc != nil {
+		return m.CompleteMultipartUploadFunc(input)
+	}
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (m *S3Client) AbortMultipartUpload(input *s3.AbortMultipartUploadInput) (*s3.AbortMultipartUploadOutput, error) {
+	m.AbortMultipartUploadCalls = append(m.AbortMultipartUploadCalls, input)
+	if m.AbortMultipartUploadFunc != nil {
+		return m.AbortMultipartUploadFunc(input)
+	}
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func (m *S3Client) ListMultipartUploads(input *s3.ListMultipartUploadsInput) (*s3.ListMultipartUploadsOutput, error) {
+	m.ListMultipartUploadsCalls = append(m.ListMultipartUploadsCalls, input)
+	if m.ListMultipartUploadsFunc != nil {
+		return m.ListMultipartUploadsFunc(input)
+	}
+	return &s3.ListMultipartUploadsOutput{}, nil
+}
+
+func (m *S3Client) CopyObjectWithContext(ctx aws.Context, input *s3.CopyObjectInput, opts ...request.Option) (*s3.CopyObjectOutput, error) {
+	m.CopyObjectWithContextCalls = append(m.CopyObjectWithContextCalls, input)
+	if m.CopyObjectWithContextFunc != nil {
+		return m.CopyObjectWithContextFunc(input)
+	}
+	return &s3.CopyObjectOutput{}, nil
+}
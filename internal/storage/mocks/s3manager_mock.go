@@ -0,0 +1,61 @@
+package mocks
+
+import (
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// Uploader is an in-memory stand-in for s3manageriface.UploaderAPI.
+type Uploader struct {
+	UploadWithContextFunc  func(*s3manager.UploadInput) (*s3manager.UploadOutput, error)
+	UploadWithContextCalls []*s3manager.UploadInput
+}
+
+func (m *Uploader) Upload(input *s3manager.UploadInput, _ ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	return m.UploadWithContext(aws.BackgroundContext(), input)
+}
+
+func (m *Uploader) UploadWithContext(_ aws.Context, input *s3manager.UploadInput, _ ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	m.UploadWithContextCalls = append(m.UploadWithContextCalls, input)
+	// A real s3manager.Uploader fully drains input.Body while streaming parts to S3;
+	// mirror that here so callers computing a checksum as the body is read (via
+	// io.TeeReader) see the same result they would against the real transfer.
+	if input.Body != nil {
+		io.Copy(io.Discard, input.Body)
+	}
+	if m.UploadWithContextFunc != nil {
+		return m.UploadWithContextFunc(input)
+	}
+	return &s3manager.UploadOutput{}, nil
+}
+
+// Downloader is an in-memory stand-in for s3manageriface.DownloaderAPI. It writes
+// DownloadWithContextFunc's returned body into the caller's io.WriterAt at offset 0,
+// which is sufficient for callers that hand it an aws.WriteAtBuffer.
+type Downloader struct {
+	DownloadWithContextFunc  func(*s3.GetObjectInput) ([]byte, error)
+	DownloadWithContextCalls []*s3.GetObjectInput
+}
+
+func (m *Downloader) Download(w io.WriterAt, input *s3.GetObjectInput, opts ...func(*s3manager.Downloader)) (int64, error) {
+	return m.DownloadWithContext(aws.BackgroundContext(), w, input, opts...)
+}
+
+func (m *Downloader) DownloadWithContext(_ aws.Context, w io.WriterAt, input *s3.GetObjectInput, _ ...func(*s3manager.Downloader)) (int64, error) {
+	m.DownloadWithContextCalls = append(m.DownloadWithContextCalls, input)
+	if m.DownloadWithContextFunc == nil {
+		return 0, nil
+	}
+	body, err := m.DownloadWithContextFunc(input)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.WriteAt(body, 0)
+	if err != nil {
+		return 0, err
+	}
+	return int64(n), nil
+}
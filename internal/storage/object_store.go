@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// ObjectInfo describes one object returned by ObjectStore.List.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Metadata describes an object's content type and caller-set metadata, as returned by
+// ObjectStore.Head.
+type Metadata struct {
+	ContentType  string
+	UserMetadata map[string]string
+}
+
+// ObjectStore is a minimal key/value object store abstraction, implemented by
+// S3ObjectStore (real AWS S3 or any S3-compatible endpoint) and MemoryObjectStore
+// (tests). Depending on this instead of *s3.S3 directly lets handlers run against
+// local development stores and in unit tests without real AWS credentials.
+type ObjectStore interface {
+	// List returns every object whose key starts with prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// Head returns metadata for key without fetching its body.
+	Head(ctx context.Context, key string) (Metadata, error)
+	// Get returns the full body of key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put writes body to key with the given content type.
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	// Delete removes key. Deleting a key that doesn't exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
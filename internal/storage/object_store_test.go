@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage/mocks"
+)
+
+func TestMemoryObjectStore_PutGetHeadDeleteList(t *testing.T) {
+	store := NewMemoryObjectStore()
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "documents/prod/a.txt", []byte("hello"), "text/plain"); err != nil {
+		t.Fatalf("unexpected error from Put: %v", err)
+	}
+
+	body, err := store.Get(ctx, "documents/prod/a.txt")
+	if err != nil || string(body) != "hello" {
+		t.Fatalf("got (%q, %v), want (\"hello\", nil)", body, err)
+	}
+
+	meta, err := store.Head(ctx, "documents/prod/a.txt")
+	if err != nil || meta.ContentType != "text/plain" {
+		t.Fatalf("got (%+v, %v), want ContentType text/plain", meta, err)
+	}
+
+	objects, err := store.List(ctx, "documents/prod/")
+	if err != nil || len(objects) != 1 || objects[0].Key != "documents/prod/a.txt" {
+		t.Fatalf("got (%+v, %v), want one object documents/prod/a.txt", objects, err)
+	}
+
+	if err := store.Delete(ctx, "documents/prod/a.txt"); err != nil {
+		t.Fatalf("unexpected error from Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "documents/prod/a.txt"); err == nil {
+		t.Fatal("expected error getting a deleted object")
+	}
+}
+
+func TestMemoryObjectStore_List_FiltersByPrefix(t *testing.T) {
+	store := NewMemoryObjectStore()
+	ctx := context.Background()
+
+	store.PutWithMetadata("documents/prod/a.txt", []byte("a"), "text/plain", nil)
+	store.PutWithMetadata("documents/dev/b.txt", []byte("b"), "text/plain", nil)
+
+	objects, err := store.List(ctx, "documents/prod/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(objects) != 1 || objects[0].Key != "documents/prod/a.txt" {
+		t.Fatalf("got %+v, want only documents/prod/a.txt", objects)
+	}
+}
+
+func TestS3ObjectStore_List_SkipsDirectoryMarkersAndFollowsContinuation(t *testing.T) {
+	pageOne := []*s3.Object{
+		{Key: aws.String("documents/prod/"), Size: aws.Int64(0)},
+		{Key: aws.String("documents/prod/a.pdf"), Size: aws.Int64(10)},
+	}
+	pageTwo := []*s3.Object{{Key: aws.String("documents/prod/b.pdf"), Size: aws.Int64(20)}}
+
+	calls := 0
+	client := &mocks.S3Client{
+		ListObjectsV2Func: func(input *s3.ListObjectsV2Input) (*s3.ListObjectsV2Output, error) {
+			calls++
+			if input.ContinuationToken == nil {
+				truthy := true
+				return &s3.ListObjectsV2Output{
+					Contents:              pageOne,
+					IsTruncated:           &truthy,
+					NextContinuationToken: aws.String("page-2"),
+				}, nil
+			}
+			return &s3.ListObjectsV2Output{Contents: pageTwo}, nil
+		},
+	}
+
+	store := NewS3ObjectStore(client, "bucket")
+	objects, err := store.List(context.Background(), "documents/prod/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(objects) != 2 || objects[0].Key != "documents/prod/a.pdf" || objects[1].Key != "documents/prod/b.pdf" {
+		t.Fatalf("got %+v, want a.pdf and b.pdf only", objects)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 ListObjectsV2 calls across pages, got %d", calls)
+	}
+}
+
+func TestS3ObjectStore_Head(t *testing.T) {
+	client := &mocks.S3Client{
+		HeadObjectFunc: func(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+			return &s3.HeadObjectOutput{
+				ContentType: aws.String("application/pdf"),
+				Metadata:    map[string]*string{"document-id": aws.String("doc-123")},
+			}, nil
+		},
+	}
+
+	store := NewS3ObjectStore(client, "bucket")
+	meta, err := store.Head(context.Background(), "documents/prod/a.pdf")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if meta.ContentType != "application/pdf" || meta.UserMetadata["document-id"] != "doc-123" {
+		t.Fatalf("got %+v, want application/pdf with document-id doc-123", meta)
+	}
+}
+
+func TestS3ObjectStore_GetAndPut(t *testing.T) {
+	var putBody []byte
+	client := &mocks.S3Client{
+		PutObjectFunc: func(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+			putBody, _ = io.ReadAll(input.Body)
+			return &s3.PutObjectOutput{}, nil
+		},
+		GetObjectFunc: func(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader([]byte("contents")))}, nil
+		},
+	}
+
+	store := NewS3ObjectStore(client, "bucket")
+
+	if err := store.Put(context.Background(), "documents/prod/a.pdf", []byte("upload me"), "application/pdf"); err != nil {
+		t.Fatalf("unexpected error from Put: %v", err)
+	}
+	if string(putBody) != "upload me" {
+		t.Errorf("got put body %q, want %q", putBody, "upload me")
+	}
+
+	body, err := store.Get(context.Background(), "documents/prod/a.pdf")
+	if err != nil || string(body) != "contents" {
+		t.Fatalf("got (%q, %v), want (\"contents\", nil)", body, err)
+	}
+}
@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OSSObjectStore is an ObjectStore backed by Alibaba Cloud Object Storage Service
+// (OSS), scoped to a single bucket. The request that asked for this named
+// github.com/denverdino/aliyungo/oss as the reference implementation, but that
+// package (like any other non-AWS cloud SDK) isn't vendored in go.mod and this
+// sandbox has no network access to add it, so OSSObjectStore instead signs plain
+// net/http requests itself using OSS's documented HMAC-SHA1 request-signing
+// algorithm (the same approach internal/backend/openaiprovider uses for an
+// external REST API with no vendored client).
+type OSSObjectStore struct {
+	bucket          string
+	endpoint        string // e.g. "https://oss-cn-hangzhou.aliyuncs.com"
+	accessKeyID     string
+	accessKeySecret string
+	httpClient      *http.Client
+}
+
+// OSSConfig configures an OSSObjectStore.
+type OSSConfig struct {
+	Endpoint        string
+	AccessKeyID     string
+	AccessKeySecret string
+}
+
+// OSSConfigFromEnv reads OSSConfig from OSS_ENDPOINT, OSS_ACCESS_KEY_ID, and
+// OSS_ACCESS_KEY_SECRET.
+func OSSConfigFromEnv() OSSConfig {
+	return OSSConfig{
+		Endpoint:        os.Getenv("OSS_ENDPOINT"),
+		AccessKeyID:     os.Getenv("OSS_ACCESS_KEY_ID"),
+		AccessKeySecret: os.Getenv("OSS_ACCESS_KEY_SECRET"),
+	}
+}
+
+// NewOSSObjectStore creates an OSSObjectStore scoped to bucket.
+func NewOSSObjectStore(bucket string, cfg OSSConfig) *OSSObjectStore {
+	return &OSSObjectStore{
+		bucket:          bucket,
+		endpoint:        strings.TrimSuffix(cfg.Endpoint, "/"),
+		accessKeyID:     cfg.AccessKeyID,
+		accessKeySecret: cfg.AccessKeySecret,
+		httpClient:      &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// sign implements OSS's classic (v1) signature algorithm: the Authorization header
+// is "OSS accessKeyId:signature", where signature is a base64-encoded HMAC-SHA1 of
+// a canonicalized request string. See:
+// https://www.alibabacloud.com/help/en/oss/developer-reference/include-signatures-in-the-authorization-header
+func (o *OSSObjectStore) sign(req *http.Request, key string) {
+	date := time.Now().UTC().Format(http.TimeFormat)
+	req.Header.Set("Date", date)
+
+	var canonicalizedHeaders []string
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-oss-") {
+			canonicalizedHeaders = append(canonicalizedHeaders, fmt.Sprintf("%s:%s", lower, strings.Join(values, ",")))
+		}
+	}
+	sort.Strings(canonicalizedHeaders)
+	canonicalizedHeadersStr := ""
+	if len(canonicalizedHeaders) > 0 {
+		canonicalizedHeadersStr = strings.Join(canonicalizedHeaders, "\n") + "\n"
+	}
+
+	canonicalizedResource := "/" + o.bucket + "/" + key
+
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		date,
+		canonicalizedHeadersStr + canonicalizedResource,
+	}, "\n")
+
+	mac := hmac.New(sha1.New, []byte(o.accessKeySecret))
+	mac.Write([]byte(stringToSign))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	req.Header.Set("Authorization", fmt.Sprintf("OSS %s:%s", o.accessKeyID, signature))
+}
+
+func (o *OSSObjectStore) objectURL(key string) string {
+	return fmt.Sprintf("%s/%s/%s", o.endpoint, o.bucket, key)
+}
+
+type ossListResult struct {
+	XMLName     xml.Name       `xml:"ListBucketResult"`
+	IsTruncated bool           `xml:"IsTruncated"`
+	NextMarker  string         `xml:"NextMarker"`
+	Contents    []ossObjectXML `xml:"Contents"`
+}
+
+type ossObjectXML struct {
+	Key          string `xml:"Key"`
+	Size         int64  `xml:"Size"`
+	LastModified string `xml:"LastModified"`
+}
+
+// List returns every object whose key starts with prefix, following OSS's marker
+// based pagination.
+func (o *OSSObjectStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var objects []ObjectInfo
+	marker := ""
+
+	for {
+		reqURL := fmt.Sprintf("%s/%s?prefix=%s", o.endpoint, o.bucket, strings.ReplaceAll(prefix, " ", "%20"))
+		if marker != "" {
+			reqURL += "&marker=" + marker
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build OSS list request: %w", err)
+		}
+		o.sign(req, "")
+
+		body, status, err := o.execute(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list OSS objects: %w", err)
+		}
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("failed to list OSS objects: status %d: %s", status, body)
+		}
+
+		var result ossListResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse OSS list response: %w", err)
+		}
+
+		for _, item := range result.Contents {
+			if strings.HasSuffix(item.Key, "/") {
+				continue // directory marker
+			}
+			lastModified, _ := time.Parse(time.RFC3339, item.LastModified)
+			objects = append(objects, ObjectInfo{
+				Key:          item.Key,
+				Size:         item.Size,
+				LastModified: lastModified,
+			})
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextMarker
+	}
+
+	return objects, nil
+}
+
+// Head returns key's content type and user metadata (OSS returns caller-set
+// metadata as "X-Oss-Meta-*" headers).
+func (o *OSSObjectStore) Head(ctx context.Context, key string) (Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return Metadata{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, o.objectURL(key), nil)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("failed to build OSS head request: %w", err)
+	}
+	o.sign(req, key)
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("failed to head OSS object %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Metadata{}, fmt.Errorf("failed to head OSS object %s: status %d", key, resp.StatusCode)
+	}
+
+	userMetadata := map[string]string{}
+	for name, values := range resp.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-oss-meta-") && len(values) > 0 {
+			userMetadata[strings.TrimPrefix(lower, "x-oss-meta-")] = values[0]
+		}
+	}
+
+	return Metadata{ContentType: resp.Header.Get("Content-Type"), UserMetadata: userMetadata}, nil
+}
+
+// Get returns key's full body.
+func (o *OSSObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.objectURL(key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OSS get request: %w", err)
+	}
+	o.sign(req, key)
+
+	body, status, err := o.execute(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OSS object %s: %w", key, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to get OSS object %s: status %d: %s", key, status, body)
+	}
+
+	return body, nil
+}
+
+// Put writes body to key with the given content type.
+func (o *OSSObjectStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, o.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build OSS put request: %w", err)
+	}
+	req.ContentLength = int64(len(body))
+	req.Header.Set("Content-Type", contentType)
+	o.sign(req, key)
+
+	respBody, status, err := o.execute(req)
+	if err != nil {
+		return fmt.Errorf("failed to put OSS object %s: %w", key, err)
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("failed to put OSS object %s: status %d: %s", key, status, respBody)
+	}
+	return nil
+}
+
+// Delete removes key. Deleting a key that doesn't exist is not an error.
+func (o *OSSObjectStore) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, o.objectURL(key), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build OSS delete request: %w", err)
+	}
+	o.sign(req, key)
+
+	respBody, status, err := o.execute(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete OSS object %s: %w", key, err)
+	}
+	if status != http.StatusNoContent && status != http.StatusOK && status != http.StatusNotFound {
+		return fmt.Errorf("failed to delete OSS object %s: status %d: %s", key, status, respBody)
+	}
+	return nil
+}
+
+func (o *OSSObjectStore) execute(req *http.Request) ([]byte, int, error) {
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOSSObjectStore_ListHeadGetPutDelete(t *testing.T) {
+	objects := map[string][]byte{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "OSS test-key:") {
+			t.Errorf("expected an OSS-signed Authorization header, got %q", r.Header.Get("Authorization"))
+		}
+
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/my-bucket/documents/prod/a.txt":
+			buf := make([]byte, r.ContentLength)
+			r.Body.Read(buf)
+			objects["documents/prod/a.txt"] = buf
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodGet && r.URL.Path == "/my-bucket" && r.URL.Query().Get("prefix") == "documents/prod/":
+			fmt.Fprint(w, `<ListBucketResult><IsTruncated>false</IsTruncated><Contents><Key>documents/prod/a.txt</Key><Size>5</Size><LastModified>2024-01-01T00:00:00Z</LastModified></Contents></ListBucketResult>`)
+		case r.Method == http.MethodHead && r.URL.Path == "/my-bucket/documents/prod/a.txt":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("X-Oss-Meta-Document-Id", "doc-123")
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodGet && r.URL.Path == "/my-bucket/documents/prod/a.txt":
+			w.Write(objects["documents/prod/a.txt"])
+		case r.Method == http.MethodDelete && r.URL.Path == "/my-bucket/documents/prod/a.txt":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.String())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	store := NewOSSObjectStore("my-bucket", OSSConfig{
+		Endpoint:        server.URL,
+		AccessKeyID:     "test-key",
+		AccessKeySecret: "test-secret",
+	})
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "documents/prod/a.txt", []byte("hello"), "text/plain"); err != nil {
+		t.Fatalf("unexpected error from Put: %v", err)
+	}
+
+	objectList, err := store.List(ctx, "documents/prod/")
+	if err != nil || len(objectList) != 1 || objectList[0].Key != "documents/prod/a.txt" {
+		t.Fatalf("got (%+v, %v), want one object documents/prod/a.txt", objectList, err)
+	}
+
+	meta, err := store.Head(ctx, "documents/prod/a.txt")
+	if err != nil || meta.ContentType != "text/plain" || meta.UserMetadata["document-id"] != "doc-123" {
+		t.Fatalf("got (%+v, %v), want text/plain with document-id doc-123", meta, err)
+	}
+
+	body, err := store.Get(ctx, "documents/prod/a.txt")
+	if err != nil || string(body) != "hello" {
+		t.Fatalf("got (%q, %v), want (\"hello\", nil)", body, err)
+	}
+
+	if err := store.Delete(ctx, "documents/prod/a.txt"); err != nil {
+		t.Fatalf("unexpected error from Delete: %v", err)
+	}
+}
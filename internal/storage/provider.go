@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// NewObjectStoreFromEnv builds the ObjectStore selected by STORAGE_PROVIDER, so a
+// handler can depend on the ObjectStore interface alone and run unmodified against
+// AWS S3, an S3-compatible gateway (MinIO, Ceph, FrostFS), Alibaba OSS, or GCS.
+// sess is only used by the "s3"/"minio" providers; it may be nil otherwise.
+//
+// STORAGE_PROVIDER defaults to "s3". Recognized values:
+//   - "s3" or "minio": S3ObjectStore. MinIO and other S3-compatible gateways are
+//     just S3 with a custom endpoint and path-style addressing, both already
+//     supported by S3ClientConfig/NewS3ClientConfigFromEnv, so "minio" is an alias
+//     rather than a separate implementation.
+//   - "oss": OSSObjectStore, configured from OSSConfigFromEnv.
+//   - "gcs": GCSObjectStore, configured from GCSTokenFromEnv.
+func NewObjectStoreFromEnv(sess *session.Session) (ObjectStore, error) {
+	bucket := DocumentBucketFromEnv()
+
+	switch provider := os.Getenv("STORAGE_PROVIDER"); provider {
+	case "", "s3", "minio":
+		client := NewS3Client(sess, NewS3ClientConfigFromEnv())
+		return NewS3ObjectStore(client, bucket), nil
+	case "oss":
+		return NewOSSObjectStore(bucket, OSSConfigFromEnv()), nil
+	case "gcs":
+		return NewGCSObjectStore(bucket, GCSTokenFromEnv), nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_PROVIDER %q (want s3, minio, oss, or gcs)", provider)
+	}
+}
+
+// NewRangeReaderFromEnv returns an io.Reader that streams key in fixed-size
+// windows (see S3RangeReader) instead of loading the whole object into memory,
+// for the providers that support it. It reports false for "oss" and "gcs", which
+// don't have a ranged reader yet; callers should fall back to ObjectStore.Get for
+// those. The window size comes from STREAM_WINDOW_BYTES, defaulting to
+// DefaultRangeWindowSize.
+func NewRangeReaderFromEnv(sess *session.Session, key string) (io.Reader, bool) {
+	switch os.Getenv("STORAGE_PROVIDER") {
+	case "", "s3", "minio":
+		client := NewS3Client(sess, NewS3ClientConfigFromEnv())
+		return NewS3RangeReader(client, DocumentBucketFromEnv(), key, rangeWindowBytesFromEnv()), true
+	default:
+		return nil, false
+	}
+}
+
+func rangeWindowBytesFromEnv() int64 {
+	n, err := strconv.ParseInt(os.Getenv("STREAM_WINDOW_BYTES"), 10, 64)
+	if err != nil || n <= 0 {
+		return DefaultRangeWindowSize
+	}
+	return n
+}
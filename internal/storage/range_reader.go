@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// DefaultRangeWindowSize is the window used by S3RangeReader when no size is given.
+const DefaultRangeWindowSize = 8 * 1024 * 1024 // 8MB
+
+// S3RangeReader is an io.Reader that pulls a single S3 object through sequential
+// ranged GetObject calls of at most windowSize bytes each, instead of the
+// io.ReadAll(result.Body) pattern used elsewhere in this codebase, which loads the
+// whole object into memory and can OOM a Lambda on a large PDF/DOCX/text file.
+type S3RangeReader struct {
+	client     S3APIClient
+	bucket     string
+	key        string
+	windowSize int64
+
+	offset int64
+	buf    []byte
+	done   bool
+}
+
+// NewS3RangeReader creates an S3RangeReader for bucket/key. A non-positive
+// windowSize falls back to DefaultRangeWindowSize.
+func NewS3RangeReader(client S3APIClient, bucket, key string, windowSize int64) *S3RangeReader {
+	if windowSize <= 0 {
+		windowSize = DefaultRangeWindowSize
+	}
+	return &S3RangeReader{client: client, bucket: bucket, key: key, windowSize: windowSize}
+}
+
+// Read implements io.Reader, fetching a new ranged window from S3 whenever the
+// previously fetched one is exhausted.
+func (r *S3RangeReader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.fetchNextWindow(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *S3RangeReader) fetchNextWindow() error {
+	end := r.offset + r.windowSize - 1
+
+	result, err := r.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(r.bucket),
+		Key:    aws.String(r.key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", r.offset, end)),
+	})
+	if err != nil {
+		var awsErr awserr.Error
+		if errors.As(err, &awsErr) && awsErr.Code() == "InvalidRange" {
+			// The previous window ended exactly at the object's last byte.
+			r.done = true
+			return io.EOF
+		}
+		return fmt.Errorf("failed to get S3 object %s range %d-%d: %w", r.key, r.offset, end, err)
+	}
+	defer result.Body.Close()
+
+	body, err := io.ReadAll(result.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read S3 object %s range %d-%d: %w", r.key, r.offset, end, err)
+	}
+
+	if len(body) == 0 {
+		r.done = true
+		return io.EOF
+	}
+
+	r.offset += int64(len(body))
+	r.buf = body
+	if int64(len(body)) < r.windowSize {
+		// Short read means we reached the end of the object.
+		r.done = true
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage/mocks"
+)
+
+func TestS3RangeReader_ReadsSuccessiveWindows(t *testing.T) {
+	data := []byte("abcdefghijklmnopqrst") // 20 bytes
+	client := &mocks.S3Client{
+		GetObjectFunc: func(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			var start, end int
+			if _, err := fmt.Sscanf(*input.Range, "bytes=%d-%d", &start, &end); err != nil {
+				t.Fatalf("unexpected Range header %q: %v", *input.Range, err)
+			}
+			if start >= len(data) {
+				return nil, awserr.New("InvalidRange", "range out of bounds", nil)
+			}
+			if end >= len(data) {
+				end = len(data) - 1
+			}
+			return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(data[start : end+1]))}, nil
+		},
+	}
+
+	reader := NewS3RangeReader(client, "bucket", "key", 6)
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != string(data) {
+		t.Fatalf("got %q, want %q", got, data)
+	}
+	if len(client.GetObjectCalls) < 3 {
+		t.Errorf("expected multiple ranged GetObject calls for a 20-byte object with a 6-byte window, got %d", len(client.GetObjectCalls))
+	}
+}
+
+func TestS3RangeReader_EmptyObject(t *testing.T) {
+	client := &mocks.S3Client{
+		GetObjectFunc: func(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+			return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(nil))}, nil
+		},
+	}
+
+	reader := NewS3RangeReader(client, "bucket", "key", 6)
+	got, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected no bytes, got %q", got)
+	}
+}
+
+func TestS3RangeReader_DefaultWindowSize(t *testing.T) {
+	reader := NewS3RangeReader(&mocks.S3Client{}, "bucket", "key", 0)
+	if reader.windowSize != DefaultRangeWindowSize {
+		t.Errorf("got window size %d, want default %d", reader.windowSize, DefaultRangeWindowSize)
+	}
+}
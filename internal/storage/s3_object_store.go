@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3ClientConfig configures how the S3 client underlying S3ObjectStore is built, so it
+// can target real AWS S3 or an S3-compatible gateway (MinIO, Ceph, FrostFS, etc).
+type S3ClientConfig struct {
+	Region string
+	// Endpoint overrides the default AWS S3 endpoint. Leave empty for real AWS S3.
+	Endpoint string
+	// ForcePathStyle addresses buckets as host/bucket/key instead of bucket.host/key,
+	// which most S3-compatible gateways require.
+	ForcePathStyle bool
+}
+
+// NewS3ClientConfigFromEnv reads S3ClientConfig from the environment: AWS_REGION,
+// S3_ENDPOINT (optional), and S3_FORCE_PATH_STYLE ("true" to enable).
+func NewS3ClientConfigFromEnv() S3ClientConfig {
+	return S3ClientConfig{
+		Region:         os.Getenv("AWS_REGION"),
+		Endpoint:       os.Getenv("S3_ENDPOINT"),
+		ForcePathStyle: os.Getenv("S3_FORCE_PATH_STYLE") == "true",
+	}
+}
+
+// NewS3Client builds an S3APIClient from cfg. Passing a non-empty Endpoint with
+// ForcePathStyle set targets an S3-compatible gateway instead of real AWS S3.
+func NewS3Client(sess *session.Session, cfg S3ClientConfig) S3APIClient {
+	awsCfg := &aws.Config{}
+	if cfg.Region != "" {
+		awsCfg.Region = aws.String(cfg.Region)
+	}
+	if cfg.Endpoint != "" {
+		awsCfg.Endpoint = aws.String(cfg.Endpoint)
+	}
+	if cfg.ForcePathStyle {
+		awsCfg.S3ForcePathStyle = aws.Bool(true)
+	}
+	return s3.New(sess, awsCfg)
+}
+
+// S3ObjectStore is an ObjectStore backed by an S3APIClient, scoped to a single bucket.
+type S3ObjectStore struct {
+	client S3APIClient
+	bucket string
+}
+
+// NewS3ObjectStore creates an S3ObjectStore scoped to bucket.
+func NewS3ObjectStore(client S3APIClient, bucket string) *S3ObjectStore {
+	return &S3ObjectStore{client: client, bucket: bucket}
+}
+
+// List returns every object under prefix, following continuation tokens across pages
+// and skipping directory markers (keys ending in "/").
+func (s *S3ObjectStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var objects []ObjectInfo
+	var continuationToken *string
+
+	for {
+		result, err := s.client.ListObjectsV2(&s3.ListObjectsV2Input{
+			Bucket:            aws.String(s.bucket),
+			Prefix:            aws.String(prefix),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list S3 objects: %w", err)
+		}
+
+		for _, item := range result.Contents {
+			if strings.HasSuffix(*item.Key, "/") {
+				continue // directory marker
+			}
+			objects = append(objects, ObjectInfo{
+				Key:          *item.Key,
+				Size:         aws.Int64Value(item.Size),
+				LastModified: aws.TimeValue(item.LastModified),
+			})
+		}
+
+		if result.IsTruncated == nil || !*result.IsTruncated {
+			break
+		}
+		continuationToken = result.NextContinuationToken
+	}
+
+	return objects, nil
+}
+
+// Head returns key's content type and user metadata.
+func (s *S3ObjectStore) Head(ctx context.Context, key string) (Metadata, error) {
+	if err := ctx.Err(); err != nil {
+		return Metadata{}, err
+	}
+
+	result, err := s.client.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return Metadata{}, fmt.Errorf("failed to head S3 object %s: %w", key, err)
+	}
+
+	userMetadata := make(map[string]string, len(result.Metadata))
+	for k, v := range result.Metadata {
+		userMetadata[k] = aws.StringValue(v)
+	}
+
+	return Metadata{
+		ContentType:  aws.StringValue(result.ContentType),
+		UserMetadata: userMetadata,
+	}, nil
+}
+
+// Get returns key's full body.
+func (s *S3ObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get S3 object %s: %w", key, err)
+	}
+	defer result.Body.Close()
+
+	body, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read S3 object %s: %w", key, err)
+	}
+
+	return body, nil
+}
+
+// Put writes body to key with the given content type.
+func (s *S3ObjectStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := s.client.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put S3 object %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key. Deleting a key that doesn't exist is not an error, matching S3's
+// own DeleteObject semantics.
+func (s *S3ObjectStore) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete S3 object %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager/s3manageriface"
+)
+
+// ChecksumMetadataKey is the user-metadata key Upload stores the uploaded object's
+// SHA-256 under. S3 surfaces it back on GetObject/HeadObject as the
+// x-amz-meta-content-sha256 header.
+const ChecksumMetadataKey = "content-sha256"
+
+// ProgressFunc is called as an Upload streams, with the cumulative number of bytes
+// sent to S3 so far, so a caller can emit per-part progress metrics.
+type ProgressFunc func(bytesSent int64)
+
+// UploaderConfig tunes the underlying s3manager.Uploader. Zero values fall back to
+// the s3manager defaults (5MB parts, 5-way concurrency).
+type UploaderConfig struct {
+	PartSize    int64
+	Concurrency int
+}
+
+// Uploader streams large documents to S3 via multipart upload so the whole body never
+// needs to be held in memory at once. It records the SHA-256 of the uploaded content
+// in the object's user metadata, since that can only be known once the stream has been
+// fully read, which is after the multipart upload S3 manager issues has already
+// completed.
+type Uploader struct {
+	manager s3manageriface.UploaderAPI
+	client  S3APIClient
+	bucket  string
+}
+
+// NewUploader creates an Uploader targeting bucket, using sess for both the
+// s3manager transfer and the follow-up metadata write.
+func NewUploader(sess *session.Session, bucket string, cfg UploaderConfig) *Uploader {
+	manager := s3manager.NewUploader(sess, func(u *s3manager.Uploader) {
+		if cfg.PartSize > 0 {
+			u.PartSize = cfg.PartSize
+		}
+		if cfg.Concurrency > 0 {
+			u.Concurrency = cfg.Concurrency
+		}
+		u.LeavePartsOnError = false
+	})
+	return newUploader(manager, s3.New(sess), bucket)
+}
+
+// newUploader builds an Uploader from its collaborators directly, so tests can inject
+// mocks for both the multipart transfer and the metadata-recording copy.
+func newUploader(manager s3manageriface.UploaderAPI, client S3APIClient, bucket string) *Uploader {
+	return &Uploader{manager: manager, client: client, bucket: bucket}
+}
+
+// Upload streams r to key with the given content type and user metadata, reporting
+// cumulative bytes sent via onProgress (which may be nil). On success, key's metadata
+// also gains ChecksumMetadataKey set to the SHA-256 of the uploaded content.
+func (u *Uploader) Upload(ctx context.Context, key string, r io.Reader, contentType string, metadata map[string]string, onProgress ProgressFunc) error {
+	hasher := sha256.New()
+	body := io.TeeReader(r, hasher)
+	if onProgress != nil {
+		body = &progressReader{r: body, onProgress: onProgress}
+	}
+
+	input := &s3manager.UploadInput{
+		Bucket:   aws.String(u.bucket),
+		Key:      aws.String(key),
+		Body:     body,
+		Metadata: toAWSMetadata(metadata),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	if _, err := u.manager.UploadWithContext(ctx, input); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+	if err := u.recordChecksum(ctx, key, contentType, metadata, checksum); err != nil {
+		return fmt.Errorf("failed to record checksum for %s: %w", key, err)
+	}
+	return nil
+}
+
+// recordChecksum adds ChecksumMetadataKey to key's metadata via a self-copy, since S3
+// object metadata is immutable after upload except by copying the object onto itself
+// with a replacement metadata set.
+func (u *Uploader) recordChecksum(ctx context.Context, key, contentType string, metadata map[string]string, checksum string) error {
+	withChecksum := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		withChecksum[k] = v
+	}
+	withChecksum[ChecksumMetadataKey] = checksum
+
+	input := &s3.CopyObjectInput{
+		Bucket:            aws.String(u.bucket),
+		Key:               aws.String(key),
+		CopySource:        aws.String(u.bucket + "/" + key),
+		Metadata:          toAWSMetadata(withChecksum),
+		MetadataDirective: aws.String(s3.MetadataDirectiveReplace),
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	_, err := u.client.CopyObjectWithContext(ctx, input)
+	return err
+}
+
+// progressReader wraps an io.Reader, invoking onProgress with the cumulative byte
+// count after every successful Read.
+type progressReader struct {
+	r          io.Reader
+	sent       int64
+	onProgress ProgressFunc
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.r.Read(buf)
+	if n > 0 {
+		p.sent += int64(n)
+		p.onProgress(p.sent)
+	}
+	return n, err
+}
+
+func toAWSMetadata(metadata map[string]string) map[string]*string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	out := make(map[string]*string, len(metadata))
+	for k, v := range metadata {
+		out[k] = aws.String(v)
+	}
+	return out
+}
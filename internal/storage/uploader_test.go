@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"aws-serverless-rag/internal/storage/mocks"
+)
+
+func TestUploader_Upload_RecordsChecksumAndProgress(t *testing.T) {
+	content := "the quick brown fox jumps over the lazy dog"
+	want := sha256.Sum256([]byte(content))
+	wantChecksum := hex.EncodeToString(want[:])
+
+	manager := &mocks.Uploader{}
+	client := &mocks.S3Client{
+		CopyObjectWithContextFunc: func(input *s3.CopyObjectInput) (*s3.CopyObjectOutput, error) {
+			return &s3.CopyObjectOutput{}, nil
+		},
+	}
+
+	uploader := newUploader(manager, client, "bucket")
+
+	var progressCalls []int64
+	err := uploader.Upload(context.Background(), "documents/prod/a.txt", strings.NewReader(content), "text/plain", map[string]string{"document-id": "doc-1"}, func(sent int64) {
+		progressCalls = append(progressCalls, sent)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(manager.UploadWithContextCalls) != 1 {
+		t.Fatalf("expected one UploadWithContext call, got %d", len(manager.UploadWithContextCalls))
+	}
+
+	if len(client.CopyObjectWithContextCalls) != 1 {
+		t.Fatalf("expected one CopyObjectWithContext call, got %d", len(client.CopyObjectWithContextCalls))
+	}
+	copyInput := client.CopyObjectWithContextCalls[0]
+	if got := aws.StringValue(copyInput.Metadata[ChecksumMetadataKey]); got != wantChecksum {
+		t.Errorf("got checksum metadata %q, want %q", got, wantChecksum)
+	}
+	if got := aws.StringValue(copyInput.Metadata["document-id"]); got != "doc-1" {
+		t.Errorf("got document-id metadata %q, want %q", got, "doc-1")
+	}
+	if aws.StringValue(copyInput.MetadataDirective) != s3.MetadataDirectiveReplace {
+		t.Errorf("got metadata directive %q, want REPLACE", aws.StringValue(copyInput.MetadataDirective))
+	}
+
+	if len(progressCalls) == 0 || progressCalls[len(progressCalls)-1] != int64(len(content)) {
+		t.Errorf("got progress calls %v, want final value %d", progressCalls, len(content))
+	}
+}
+
+func TestUploader_Upload_ReturnsErrorOnFailedTransfer(t *testing.T) {
+	manager := &mocks.Uploader{
+		UploadWithContextFunc: func(*s3manager.UploadInput) (*s3manager.UploadOutput, error) {
+			return nil, bytes.ErrTooLarge
+		},
+	}
+	client := &mocks.S3Client{}
+	uploader := newUploader(manager, client, "bucket")
+
+	err := uploader.Upload(context.Background(), "key", strings.NewReader("body"), "text/plain", nil, nil)
+	if err == nil {
+		t.Fatal("expected an error from a failed transfer")
+	}
+	if len(client.CopyObjectWithContextCalls) != 0 {
+		t.Error("expected no checksum copy after a failed upload")
+	}
+}
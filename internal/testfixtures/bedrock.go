@@ -0,0 +1,132 @@
+package testfixtures
+
+import (
+	"context"
+	"time"
+
+	"aws-serverless-rag/internal/models"
+)
+
+// MockBedrock is a test double for services.BedrockClient's embedding,
+// answer, and streaming-answer methods. Build one with NewMockBedrock and the
+// WithX options below.
+type MockBedrock struct {
+	embeddings       map[string][]float32
+	defaultEmbedding []float32
+	answer           string
+	failEmbedding    bool
+	failAnswer       bool
+	streamTokens     []string
+	streamDelay      time.Duration
+	failStream       bool
+
+	embeddingCalls []string
+	answerCalls    []string
+}
+
+// BedrockOption configures a MockBedrock constructed by NewMockBedrock.
+type BedrockOption func(*MockBedrock)
+
+// WithEmbedding sets the embedding returned for any question without a more
+// specific WithEmbeddingFor entry.
+func WithEmbedding(embedding []float32) BedrockOption {
+	return func(m *MockBedrock) { m.defaultEmbedding = embedding }
+}
+
+// WithEmbeddingFor returns embedding only when GenerateEmbedding is called
+// with the exact given question, so a test can exercise multiple questions
+// against one mock.
+func WithEmbeddingFor(question string, embedding []float32) BedrockOption {
+	return func(m *MockBedrock) { m.embeddings[question] = embedding }
+}
+
+// WithFailEmbedding makes GenerateEmbedding return models.ErrEmbeddingFailed.
+func WithFailEmbedding() BedrockOption {
+	return func(m *MockBedrock) { m.failEmbedding = true }
+}
+
+// WithAnswer sets the answer returned by GenerateAnswer.
+func WithAnswer(answer string) BedrockOption {
+	return func(m *MockBedrock) { m.answer = answer }
+}
+
+// WithFailAnswer makes GenerateAnswer return models.ErrLLMGenerationFailed.
+func WithFailAnswer() BedrockOption {
+	return func(m *MockBedrock) { m.failAnswer = true }
+}
+
+// WithStream configures GenerateAnswerStream to emit tokens one at a time,
+// pausing delay between each, faking Bedrock's InvokeModelWithResponseStream.
+func WithStream(tokens []string, delay time.Duration) BedrockOption {
+	return func(m *MockBedrock) {
+		m.streamTokens = tokens
+		m.streamDelay = delay
+	}
+}
+
+// WithFailStream makes GenerateAnswerStream return models.ErrLLMGenerationFailed
+// before emitting any tokens.
+func WithFailStream() BedrockOption {
+	return func(m *MockBedrock) { m.failStream = true }
+}
+
+// NewMockBedrock constructs a MockBedrock, applying opts in order.
+func NewMockBedrock(opts ...BedrockOption) *MockBedrock {
+	m := &MockBedrock{embeddings: make(map[string][]float32)}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// GenerateEmbedding implements the BedrockClient embedding method, recording
+// question in EmbeddingCalls.
+func (m *MockBedrock) GenerateEmbedding(question string) ([]float32, error) {
+	m.embeddingCalls = append(m.embeddingCalls, question)
+	if m.failEmbedding {
+		return nil, models.ErrEmbeddingFailed
+	}
+	if embedding, ok := m.embeddings[question]; ok {
+		return embedding, nil
+	}
+	return m.defaultEmbedding, nil
+}
+
+// GenerateAnswer implements the BedrockClient answer method, recording
+// question in AnswerCalls.
+func (m *MockBedrock) GenerateAnswer(question string, sources []models.Source) (string, error) {
+	m.answerCalls = append(m.answerCalls, question)
+	if m.failAnswer {
+		return "", models.ErrLLMGenerationFailed
+	}
+	return m.answer, nil
+}
+
+// GenerateAnswerStream fakes Bedrock's streaming InvokeModelWithResponseStream
+// API: it writes each configured token to out (pausing streamDelay between
+// tokens), then closes out so the caller's range loop ends.
+func (m *MockBedrock) GenerateAnswerStream(ctx context.Context, question string, sources []models.Source, out chan<- string) error {
+	defer close(out)
+
+	if m.failStream {
+		return models.ErrLLMGenerationFailed
+	}
+
+	for _, token := range m.streamTokens {
+		if m.streamDelay > 0 {
+			time.Sleep(m.streamDelay)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case out <- token:
+		}
+	}
+	return nil
+}
+
+// EmbeddingCalls returns the questions passed to GenerateEmbedding, in call order.
+func (m *MockBedrock) EmbeddingCalls() []string { return m.embeddingCalls }
+
+// AnswerCalls returns the questions passed to GenerateAnswer, in call order.
+func (m *MockBedrock) AnswerCalls() []string { return m.answerCalls }
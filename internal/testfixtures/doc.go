@@ -0,0 +1,8 @@
+// Package testfixtures provides shared test doubles for the Lambda handler
+// tests under cmd/. Each fixture is built with a NewMockX(opts...)
+// constructor and functional options (WithX) that control per-call
+// failures, canned responses, and streaming behavior. Every fixture records
+// the arguments it was called with so a test can assert on them, e.g.
+// mockBedrock.EmbeddingCalls() to check GenerateEmbedding was called with the
+// expected question.
+package testfixtures
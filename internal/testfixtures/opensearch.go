@@ -0,0 +1,56 @@
+package testfixtures
+
+import (
+	"context"
+
+	"aws-serverless-rag/internal/models"
+)
+
+// VectorSearchCall records the arguments a single VectorSearch call was made with.
+type VectorSearchCall struct {
+	Embedding  []float32
+	MaxResults int
+}
+
+// MockOpenSearch is a test double for the OpenSearchClient vector-search method.
+type MockOpenSearch struct {
+	sources []models.Source
+	fail    bool
+
+	searchCalls []VectorSearchCall
+}
+
+// OpenSearchOption configures a MockOpenSearch constructed by NewMockOpenSearch.
+type OpenSearchOption func(*MockOpenSearch)
+
+// WithSources sets the sources returned by VectorSearch.
+func WithSources(sources []models.Source) OpenSearchOption {
+	return func(m *MockOpenSearch) { m.sources = sources }
+}
+
+// WithFailSearch makes VectorSearch return models.ErrVectorSearchFailed.
+func WithFailSearch() OpenSearchOption {
+	return func(m *MockOpenSearch) { m.fail = true }
+}
+
+// NewMockOpenSearch constructs a MockOpenSearch, applying opts in order.
+func NewMockOpenSearch(opts ...OpenSearchOption) *MockOpenSearch {
+	m := &MockOpenSearch{}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// VectorSearch implements the OpenSearchClient interface, recording its
+// arguments in SearchCalls.
+func (m *MockOpenSearch) VectorSearch(ctx context.Context, embedding []float32, maxResults int) ([]models.Source, error) {
+	m.searchCalls = append(m.searchCalls, VectorSearchCall{Embedding: embedding, MaxResults: maxResults})
+	if m.fail {
+		return nil, models.ErrVectorSearchFailed
+	}
+	return m.sources, nil
+}
+
+// SearchCalls returns the arguments passed to VectorSearch, in call order.
+func (m *MockOpenSearch) SearchCalls() []VectorSearchCall { return m.searchCalls }
@@ -0,0 +1,226 @@
+package transcribe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/transcribeservice"
+	"github.com/google/uuid"
+)
+
+// wordsPerSegment bounds how many transcribed words are grouped into one Segment,
+// so a chunk built from several segments still has a reasonably tight start/end
+// time range rather than spanning the entire transcript.
+const wordsPerSegment = 20
+
+// pollInterval is how often AWSTranscriber checks a submitted job's status.
+const pollInterval = 5 * time.Second
+
+// AWSTranscriber submits uploaded media to Amazon Transcribe, polls until the job
+// completes, and downloads and parses the resulting transcript JSON.
+type AWSTranscriber struct {
+	client     *transcribeservice.TranscribeService
+	httpClient *http.Client
+}
+
+// NewAWSTranscriber creates an AWSTranscriber backed by client.
+func NewAWSTranscriber(client *transcribeservice.TranscribeService) *AWSTranscriber {
+	return &AWSTranscriber{
+		client:     client,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Transcribe submits the S3 object at bucket/key as a transcription job, polls
+// GetTranscriptionJob until it completes, and downloads and parses the result.
+func (t *AWSTranscriber) Transcribe(ctx context.Context, bucket, key string) (*Transcript, error) {
+	jobName := "transcribe-" + uuid.New().String()
+	mediaURI := fmt.Sprintf("s3://%s/%s", bucket, key)
+
+	_, err := t.client.StartTranscriptionJobWithContext(ctx, &transcribeservice.StartTranscriptionJobInput{
+		TranscriptionJobName: aws.String(jobName),
+		Media:                &transcribeservice.Media{MediaFileUri: aws.String(mediaURI)},
+		IdentifyLanguage:     aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transcription job: %w", err)
+	}
+
+	transcriptURI, err := t.waitForCompletion(ctx, jobName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := t.download(ctx, transcriptURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download transcript: %w", err)
+	}
+
+	return parseTranscribeResult(data)
+}
+
+func (t *AWSTranscriber) waitForCompletion(ctx context.Context, jobName string) (string, error) {
+	for {
+		output, err := t.client.GetTranscriptionJobWithContext(ctx, &transcribeservice.GetTranscriptionJobInput{
+			TranscriptionJobName: aws.String(jobName),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to get transcription job status: %w", err)
+		}
+
+		job := output.TranscriptionJob
+		switch aws.StringValue(job.TranscriptionJobStatus) {
+		case transcribeservice.TranscriptionJobStatusCompleted:
+			if job.Transcript == nil || job.Transcript.TranscriptFileUri == nil {
+				return "", fmt.Errorf("transcription job %s completed without a transcript URI", jobName)
+			}
+			return aws.StringValue(job.Transcript.TranscriptFileUri), nil
+		case transcribeservice.TranscriptionJobStatusFailed:
+			return "", fmt.Errorf("transcription job %s failed: %s", jobName, aws.StringValue(job.FailureReason))
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (t *AWSTranscriber) download(ctx context.Context, transcriptURI string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, transcriptURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("transcript download failed with status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// transcribeResultItem is one entry in the Amazon Transcribe output JSON's
+// results.items array: either a spoken word ("pronunciation") with timing, or
+// punctuation attached to the previous word with no timing of its own.
+type transcribeResultItem struct {
+	Type         string `json:"type"`
+	StartTime    string `json:"start_time"`
+	EndTime      string `json:"end_time"`
+	Alternatives []struct {
+		Content string `json:"content"`
+	} `json:"alternatives"`
+}
+
+type transcribeOutput struct {
+	Results struct {
+		Transcripts []struct {
+			Transcript string `json:"transcript"`
+		} `json:"transcripts"`
+		Items []transcribeResultItem `json:"items"`
+	} `json:"results"`
+}
+
+// parseTranscribeResult decodes Amazon Transcribe's output JSON into a Transcript,
+// grouping consecutive spoken words into Segments of up to wordsPerSegment words
+// each.
+func parseTranscribeResult(data []byte) (*Transcript, error) {
+	var output transcribeOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("failed to parse transcribe output: %w", err)
+	}
+
+	transcript := &Transcript{}
+	if len(output.Results.Transcripts) > 0 {
+		transcript.Text = output.Results.Transcripts[0].Transcript
+	}
+
+	var segmentWords []string
+	var segmentStartMS, segmentEndMS int64
+	wordsInSegment := 0
+
+	flushSegment := func() {
+		if wordsInSegment == 0 {
+			return
+		}
+		transcript.Segments = append(transcript.Segments, Segment{
+			Text:    joinWords(segmentWords),
+			StartMS: segmentStartMS,
+			EndMS:   segmentEndMS,
+		})
+		segmentWords = nil
+		wordsInSegment = 0
+	}
+
+	for _, item := range output.Results.Items {
+		content := ""
+		if len(item.Alternatives) > 0 {
+			content = item.Alternatives[0].Content
+		}
+		if content == "" {
+			continue
+		}
+
+		if item.Type != "pronunciation" {
+			// Punctuation attaches to the previous word with no space.
+			if wordsInSegment > 0 {
+				segmentWords[len(segmentWords)-1] += content
+			}
+			continue
+		}
+
+		startMS, err := secondsToMS(item.StartTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start_time %q: %w", item.StartTime, err)
+		}
+		endMS, err := secondsToMS(item.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end_time %q: %w", item.EndTime, err)
+		}
+
+		if wordsInSegment == 0 {
+			segmentStartMS = startMS
+		}
+		segmentEndMS = endMS
+		segmentWords = append(segmentWords, content)
+		wordsInSegment++
+
+		if wordsInSegment >= wordsPerSegment {
+			flushSegment()
+		}
+	}
+	flushSegment()
+
+	return transcript, nil
+}
+
+func secondsToMS(seconds string) (int64, error) {
+	value, err := strconv.ParseFloat(seconds, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(value * 1000), nil
+}
+
+func joinWords(words []string) string {
+	result := ""
+	for i, word := range words {
+		if i > 0 {
+			result += " "
+		}
+		result += word
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+// Package transcribe converts uploaded audio/video into a timestamped transcript,
+// so the document processor can chunk and embed spoken content the same way it
+// already does for text documents. See AWSTranscriber for the Amazon Transcribe
+// implementation of the Transcriber interface.
+package transcribe
+
+import "context"
+
+// Segment is one span of transcribed speech, with its position in the source
+// media in milliseconds.
+type Segment struct {
+	Text    string
+	StartMS int64
+	EndMS   int64
+}
+
+// Transcript is the full result of transcribing one audio/video file.
+type Transcript struct {
+	Text     string
+	Segments []Segment
+}
+
+// Transcriber submits a media object for transcription and returns the resulting
+// transcript once the job completes.
+type Transcriber interface {
+	Transcribe(ctx context.Context, bucket, key string) (*Transcript, error)
+}
+
+// audioContentTypes are the upload content types routed through a Transcriber
+// before internal/performance.ProcessTranscriptIntoChunks, instead of the plain-text
+// chunker.
+var audioContentTypes = map[string]bool{
+	"audio/mpeg":  true,
+	"audio/wav":   true,
+	"audio/x-wav": true,
+	"audio/mp4":   true,
+	"video/mp4":   true,
+}
+
+// IsAudioContentType reports whether contentType should be routed through a
+// Transcriber before chunking, rather than treated as extractable text.
+func IsAudioContentType(contentType string) bool {
+	return audioContentTypes[contentType]
+}
@@ -0,0 +1,84 @@
+package transcribe
+
+import "testing"
+
+func TestIsAudioContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"audio/mpeg", true},
+		{"audio/wav", true},
+		{"audio/x-wav", true},
+		{"audio/mp4", true},
+		{"video/mp4", true},
+		{"application/pdf", false},
+		{"text/plain", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsAudioContentType(tt.contentType); got != tt.want {
+			t.Errorf("IsAudioContentType(%q) = %v, want %v", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+const sampleTranscribeOutput = `{
+	"results": {
+		"transcripts": [{"transcript": "Hello world. This is a test."}],
+		"items": [
+			{"type": "pronunciation", "start_time": "0.0", "end_time": "0.5", "alternatives": [{"content": "Hello"}]},
+			{"type": "pronunciation", "start_time": "0.6", "end_time": "1.0", "alternatives": [{"content": "world"}]},
+			{"type": "punctuation", "alternatives": [{"content": "."}]},
+			{"type": "pronunciation", "start_time": "1.5", "end_time": "1.8", "alternatives": [{"content": "This"}]},
+			{"type": "pronunciation", "start_time": "1.9", "end_time": "2.0", "alternatives": [{"content": "is"}]},
+			{"type": "pronunciation", "start_time": "2.1", "end_time": "2.2", "alternatives": [{"content": "a"}]},
+			{"type": "pronunciation", "start_time": "2.3", "end_time": "2.6", "alternatives": [{"content": "test"}]},
+			{"type": "punctuation", "alternatives": [{"content": "."}]}
+		]
+	}
+}`
+
+func TestParseTranscribeResult(t *testing.T) {
+	transcript, err := parseTranscribeResult([]byte(sampleTranscribeOutput))
+	if err != nil {
+		t.Fatalf("parseTranscribeResult failed: %v", err)
+	}
+
+	if transcript.Text != "Hello world. This is a test." {
+		t.Errorf("unexpected transcript text: %q", transcript.Text)
+	}
+
+	if len(transcript.Segments) != 1 {
+		t.Fatalf("expected 1 segment, got %d", len(transcript.Segments))
+	}
+
+	segment := transcript.Segments[0]
+	if segment.Text != "Hello world. This is a test." {
+		t.Errorf("unexpected segment text: %q", segment.Text)
+	}
+	if segment.StartMS != 0 {
+		t.Errorf("expected StartMS 0, got %d", segment.StartMS)
+	}
+	if segment.EndMS != 2600 {
+		t.Errorf("expected EndMS 2600, got %d", segment.EndMS)
+	}
+}
+
+func TestParseTranscribeResult_InvalidJSON(t *testing.T) {
+	_, err := parseTranscribeResult([]byte("not json"))
+	if err == nil {
+		t.Error("expected an error for invalid JSON, got none")
+	}
+}
+
+func TestParseTranscribeResult_EmptyItems(t *testing.T) {
+	transcript, err := parseTranscribeResult([]byte(`{"results": {"transcripts": [{"transcript": ""}], "items": []}}`))
+	if err != nil {
+		t.Fatalf("parseTranscribeResult failed: %v", err)
+	}
+	if len(transcript.Segments) != 0 {
+		t.Errorf("expected no segments, got %d", len(transcript.Segments))
+	}
+}
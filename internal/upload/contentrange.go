@@ -0,0 +1,65 @@
+package upload
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ContentRange is a parsed "Content-Range: bytes {start}-{end}/{total}" header, as
+// sent by a client PATCHing one chunk of a resumable upload.
+type ContentRange struct {
+	Start int64
+	End   int64
+	Total int64
+}
+
+// ParseContentRange parses a "bytes {start}-{end}/{total}" Content-Range header
+// value.
+func ParseContentRange(header string) (ContentRange, error) {
+	const prefix = "bytes "
+	if !strings.HasPrefix(header, prefix) {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: missing %q prefix", header, prefix)
+	}
+
+	rangeAndTotal := strings.SplitN(header[len(prefix):], "/", 2)
+	if len(rangeAndTotal) != 2 {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: missing total", header)
+	}
+
+	startEnd := strings.SplitN(rangeAndTotal[0], "-", 2)
+	if len(startEnd) != 2 {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: missing start-end", header)
+	}
+
+	start, err := strconv.ParseInt(startEnd[0], 10, 64)
+	if err != nil {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: bad start: %w", header, err)
+	}
+	end, err := strconv.ParseInt(startEnd[1], 10, 64)
+	if err != nil {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: bad end: %w", header, err)
+	}
+	total, err := strconv.ParseInt(rangeAndTotal[1], 10, 64)
+	if err != nil {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: bad total: %w", header, err)
+	}
+
+	if start < 0 || end < start || total < end+1 {
+		return ContentRange{}, fmt.Errorf("invalid Content-Range %q: start/end/total out of order", header)
+	}
+
+	return ContentRange{Start: start, End: end, Total: total}, nil
+}
+
+// ValidateResumes reports whether cr begins exactly where session left off, which
+// is required since S3 multipart parts must be appended in order with no gaps.
+func (cr ContentRange) ValidateResumes(session *Session) error {
+	if cr.Start != session.BytesReceived {
+		return fmt.Errorf("chunk starts at byte %d, but %d bytes have already been received", cr.Start, session.BytesReceived)
+	}
+	if session.TotalSize > 0 && cr.Total != session.TotalSize {
+		return fmt.Errorf("chunk declares total size %d, session was started with %d", cr.Total, session.TotalSize)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package upload
+
+import "testing"
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		want        ContentRange
+		expectError bool
+	}{
+		{
+			name:   "valid range",
+			header: "bytes 0-5242879/10485760",
+			want:   ContentRange{Start: 0, End: 5242879, Total: 10485760},
+		},
+		{
+			name:   "resuming mid-file",
+			header: "bytes 5242880-10485759/10485760",
+			want:   ContentRange{Start: 5242880, End: 10485759, Total: 10485760},
+		},
+		{
+			name:        "missing bytes prefix",
+			header:      "0-5242879/10485760",
+			expectError: true,
+		},
+		{
+			name:        "missing total",
+			header:      "bytes 0-5242879",
+			expectError: true,
+		},
+		{
+			name:        "end before start",
+			header:      "bytes 100-50/200",
+			expectError: true,
+		},
+		{
+			name:        "total smaller than range",
+			header:      "bytes 0-100/50",
+			expectError: true,
+		},
+		{
+			name:        "not a number",
+			header:      "bytes a-b/c",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseContentRange(tt.header)
+
+			if tt.expectError {
+				if err == nil {
+					t.Fatal("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContentRange_ValidateResumes(t *testing.T) {
+	session := &Session{BytesReceived: 1024, TotalSize: 4096}
+
+	if err := (ContentRange{Start: 1024, End: 2047, Total: 4096}).ValidateResumes(session); err != nil {
+		t.Errorf("expected a chunk starting exactly at BytesReceived to be valid, got %v", err)
+	}
+
+	if err := (ContentRange{Start: 0, End: 1023, Total: 4096}).ValidateResumes(session); err == nil {
+		t.Error("expected an error for a chunk that re-sends already-received bytes")
+	}
+
+	if err := (ContentRange{Start: 1024, End: 2047, Total: 8192}).ValidateResumes(session); err == nil {
+		t.Error("expected an error for a chunk whose declared total disagrees with the session")
+	}
+}
+
+func TestSession_NextRangeHeader(t *testing.T) {
+	tests := []struct {
+		name          string
+		bytesReceived int64
+		want          string
+	}{
+		{name: "nothing received yet", bytesReceived: 0, want: "0-0"},
+		{name: "one chunk received", bytesReceived: 5242880, want: "0-5242879"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{BytesReceived: tt.bytesReceived}
+			if got := session.NextRangeHeader(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/storage"
+)
+
+// DefaultChunkSize is the chunk size StartUpload tells clients to send. It matches
+// S3 multipart upload's minimum part size (5MiB, except for the final part).
+const DefaultChunkSize = 5 * 1024 * 1024
+
+// Manager drives the resumable-upload protocol: each Session maps 1:1 to an S3
+// multipart upload, with every accepted chunk becoming one S3 part.
+type Manager struct {
+	s3       storage.S3APIClient
+	sessions *SessionStore
+	bucket   string
+}
+
+// NewManager creates a Manager that stores objects in bucket and tracks sessions in
+// sessions.
+func NewManager(s3Client storage.S3APIClient, sessions *SessionStore, bucket string) *Manager {
+	return &Manager{s3: s3Client, sessions: sessions, bucket: bucket}
+}
+
+// StartUpload begins a new resumable upload for key and returns its Session,
+// including the chunk size the client should send. Handles POST /documents/uploads.
+func (m *Manager) StartUpload(ctx context.Context, key, contentType string, totalSize int64) (*Session, error) {
+	out, err := m.s3.CreateMultipartUpload(&s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(m.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start S3 multipart upload: %w", err)
+	}
+
+	uploadID, err := newUploadID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate upload id: %w", err)
+	}
+
+	session := &Session{
+		UploadID:    uploadID,
+		Key:         key,
+		S3UploadID:  aws.StringValue(out.UploadId),
+		ContentType: contentType,
+		TotalSize:   totalSize,
+		ChunkSize:   DefaultChunkSize,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := m.sessions.Put(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// AppendChunk uploads one chunk as the next S3 part and advances the session.
+// contentRange must start exactly at session.BytesReceived. Handles
+// PATCH /documents/uploads/{upload_id}.
+func (m *Manager) AppendChunk(ctx context.Context, uploadID string, contentRange ContentRange, data []byte) (*Session, error) {
+	session, err := m.sessions.Get(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Finalized {
+		return nil, fmt.Errorf("upload %s is already finalized", uploadID)
+	}
+	if err := contentRange.ValidateResumes(session); err != nil {
+		return nil, err
+	}
+
+	partNumber := int64(len(session.Parts) + 1)
+	out, err := m.s3.UploadPart(&s3.UploadPartInput{
+		Bucket:     aws.String(m.bucket),
+		Key:        aws.String(session.Key),
+		UploadId:   aws.String(session.S3UploadID),
+		PartNumber: aws.Int64(partNumber),
+		Body:       aws.ReadSeekCloser(bytes.NewReader(data)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+
+	session.Parts = append(session.Parts, PartInfo{
+		PartNumber: partNumber,
+		ETag:       aws.StringValue(out.ETag),
+		Size:       int64(len(data)),
+	})
+	session.BytesReceived += int64(len(data))
+
+	if err := m.sessions.Put(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// FinalizeUpload completes the S3 multipart upload and returns the session, with
+// DocumentID set. Handles PUT /documents/uploads/{upload_id}.
+func (m *Manager) FinalizeUpload(ctx context.Context, uploadID string) (*Session, error) {
+	session, err := m.sessions.Get(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Finalized {
+		return session, nil
+	}
+	if session.TotalSize > 0 && session.BytesReceived != session.TotalSize {
+		return nil, fmt.Errorf("upload %s has received %d of %d bytes", uploadID, session.BytesReceived, session.TotalSize)
+	}
+
+	completedParts := make([]*s3.CompletedPart, len(session.Parts))
+	for i, part := range session.Parts {
+		completedParts[i] = &s3.CompletedPart{
+			PartNumber: aws.Int64(part.PartNumber),
+			ETag:       aws.String(part.ETag),
+		}
+	}
+
+	_, err = m.s3.CompleteMultipartUpload(&s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(m.bucket),
+		Key:             aws.String(session.Key),
+		UploadId:        aws.String(session.S3UploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completedParts},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete S3 multipart upload: %w", err)
+	}
+
+	session.Finalized = true
+	session.DocumentID = uploadID
+	if err := m.sessions.Put(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// GetSession returns the session for uploadID so a client can resume after an
+// interruption. Handles HEAD /documents/uploads/{upload_id}.
+func (m *Manager) GetSession(ctx context.Context, uploadID string) (*Session, error) {
+	return m.sessions.Get(ctx, uploadID)
+}
+
+func newUploadID() (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
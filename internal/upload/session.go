@@ -0,0 +1,42 @@
+// Package upload implements a Docker-distribution-style resumable upload protocol
+// over S3 multipart upload, so large documents can be sent in chunks small enough
+// to fit API Gateway's payload limit and Lambda's timeout, and interrupted clients
+// can resume instead of restarting.
+package upload
+
+import (
+	"strconv"
+	"time"
+)
+
+// PartInfo records one part already accepted into the underlying S3 multipart
+// upload.
+type PartInfo struct {
+	PartNumber int64  `json:"part_number"`
+	ETag       string `json:"etag"`
+	Size       int64  `json:"size"`
+}
+
+// Session tracks one in-progress resumable upload, keyed by UploadID.
+type Session struct {
+	UploadID      string     `json:"upload_id"`
+	Key           string     `json:"key"`
+	S3UploadID    string     `json:"s3_upload_id"`
+	ContentType   string     `json:"content_type"`
+	TotalSize     int64      `json:"total_size"`
+	ChunkSize     int64      `json:"chunk_size"`
+	BytesReceived int64      `json:"bytes_received"`
+	Parts         []PartInfo `json:"parts"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Finalized     bool       `json:"finalized"`
+	DocumentID    string     `json:"document_id,omitempty"`
+}
+
+// NextRangeHeader returns the Range header value ("0-{offset}") a client should
+// read back from HEAD /documents/uploads/{upload_id} to learn where to resume.
+func (s *Session) NextRangeHeader() string {
+	if s.BytesReceived == 0 {
+		return "0-0"
+	}
+	return "0-" + strconv.FormatInt(s.BytesReceived-1, 10)
+}
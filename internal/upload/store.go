@@ -0,0 +1,65 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+// ErrSessionNotFound is returned by SessionStore.Get when upload_id doesn't exist.
+var ErrSessionNotFound = errors.New("upload session not found")
+
+// SessionStore persists resumable-upload Sessions, keyed by UploadID, in DynamoDB.
+type SessionStore struct {
+	client    *dynamodb.DynamoDB
+	tableName string
+}
+
+// NewSessionStore creates a SessionStore backed by tableName, which needs a string
+// partition key "upload_id".
+func NewSessionStore(client *dynamodb.DynamoDB, tableName string) *SessionStore {
+	return &SessionStore{client: client, tableName: tableName}
+}
+
+// Put creates or overwrites session.
+func (s *SessionStore) Put(ctx context.Context, session *Session) error {
+	item, err := dynamodbattribute.MarshalMap(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal upload session: %w", err)
+	}
+
+	_, err = s.client.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store upload session: %w", err)
+	}
+	return nil
+}
+
+// Get returns the session for uploadID, or ErrSessionNotFound if it doesn't exist.
+func (s *SessionStore) Get(ctx context.Context, uploadID string) (*Session, error) {
+	out, err := s.client.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"upload_id": {S: aws.String(uploadID)},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch upload session: %w", err)
+	}
+	if out.Item == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	var session Session
+	if err := dynamodbattribute.UnmarshalMap(out.Item, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal upload session: %w", err)
+	}
+	return &session, nil
+}
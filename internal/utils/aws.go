@@ -1,21 +1,49 @@
 package utils
 
 import (
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/bedrockruntime"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/s3"
+
+	"aws-serverless-rag/internal/reliability"
+)
+
+// Service names accepted by AWSConfig.Do.
+const (
+	ServiceS3       = "s3"
+	ServiceDynamoDB = "dynamodb"
+	ServiceBedrock  = "bedrock"
 )
 
+// breakerConfig is used for all three per-service breakers: five consecutive failures
+// trips the breaker, and it waits 30s before letting a single half-open probe through.
+func breakerConfig() reliability.CircuitBreakerConfig {
+	return reliability.CircuitBreakerConfig{
+		MaxFailures:       5,
+		ResetTimeout:      30 * time.Second,
+		HalfOpenMaxProbes: 1,
+	}
+}
+
 // AWSConfig holds AWS service clients
 type AWSConfig struct {
 	Session        *session.Session
 	S3Client       *s3.S3
 	DynamoDBClient *dynamodb.DynamoDB
 	BedrockClient  *bedrockruntime.BedrockRuntime
+
+	// S3Breaker, DynamoDBBreaker, and BedrockBreaker isolate each downstream service so
+	// that, e.g., a Bedrock throttling storm can't also starve S3/DynamoDB callers
+	// sharing this AWSConfig.
+	S3Breaker       *reliability.CircuitBreaker
+	DynamoDBBreaker *reliability.CircuitBreaker
+	BedrockBreaker  *reliability.CircuitBreaker
 }
 
 // NewAWSConfig creates and initializes AWS service clients
@@ -35,14 +63,36 @@ func NewAWSConfig() (*AWSConfig, error) {
 		Region: aws.String(getBedrockRegion()),
 	})
 
+	logger := NewLogger()
+
 	return &AWSConfig{
 		Session:        sess,
 		S3Client:       s3Client,
 		DynamoDBClient: dynamoDBClient,
 		BedrockClient:  bedrockClient,
+
+		S3Breaker:       reliability.NewCircuitBreaker(breakerConfig(), logger),
+		DynamoDBBreaker: reliability.NewCircuitBreaker(breakerConfig(), logger),
+		BedrockBreaker:  reliability.NewCircuitBreaker(breakerConfig(), logger),
 	}, nil
 }
 
+// Do runs op through the circuit breaker for the named service (ServiceS3,
+// ServiceDynamoDB, or ServiceBedrock), isolating callers from failures in the other
+// services sharing this AWSConfig.
+func (c *AWSConfig) Do(service string, op reliability.RetryableOperation) error {
+	switch service {
+	case ServiceS3:
+		return c.S3Breaker.Execute(op)
+	case ServiceDynamoDB:
+		return c.DynamoDBBreaker.Execute(op)
+	case ServiceBedrock:
+		return c.BedrockBreaker.Execute(op)
+	default:
+		return fmt.Errorf("aws config: unknown service %q", service)
+	}
+}
+
 // getRegion returns the AWS region from environment or defaults to us-east-1
 func getRegion() string {
 	if region := os.Getenv("AWS_REGION"); region != "" {
@@ -74,6 +124,12 @@ func GetDynamoDBTableName() string {
 	return os.Getenv("DYNAMODB_TABLE_NAME")
 }
 
+// GetAnalyticsTableName returns the query-analytics DynamoDB table name from
+// ANALYTICS_TABLE_NAME.
+func GetAnalyticsTableName() string {
+	return os.Getenv("ANALYTICS_TABLE_NAME")
+}
+
 // GetEnvironment returns the current environment (dev, staging, prod)
 func GetEnvironment() string {
 	if env := os.Getenv("ENVIRONMENT"); env != "" {
@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+
+	"aws-serverless-rag/internal/models"
+)
+
+// IdempotencyKeyHeader is the request header a client sets to make a write-endpoint
+// retry safe.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// DefaultIdempotencyTTL is how long a cached response is honored before its
+// Idempotency-Key can be reused for an unrelated request.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// APIGatewayHandlerFunc is the signature every Lambda main in this repo hands to
+// lambda.Start.
+type APIGatewayHandlerFunc func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
+
+// idempotencyRecord is what's stored in DynamoDB, keyed by "key_hash" (the hash of
+// the client-supplied Idempotency-Key) alongside "request_hash" (the hash of
+// method+path+body), so a conflicting retry with the same key but a different
+// request is detectable.
+type idempotencyRecord struct {
+	KeyHash     string `json:"key_hash"`
+	RequestHash string `json:"request_hash"`
+	StatusCode  int    `json:"status_code"`
+	Headers     string `json:"headers"`
+	Body        string `json:"body"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
+
+// Idempotency wraps Lambda handlers with DynamoDB-backed Idempotency-Key support.
+type Idempotency struct {
+	client    *dynamodb.DynamoDB
+	tableName string
+	ttl       time.Duration
+}
+
+// NewIdempotency creates an Idempotency middleware backed by tableName, which needs a
+// string partition key "key_hash" and a TTL attribute "expires_at". ttl <= 0 uses
+// DefaultIdempotencyTTL.
+func NewIdempotency(client *dynamodb.DynamoDB, tableName string, ttl time.Duration) *Idempotency {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	return &Idempotency{client: client, tableName: tableName, ttl: ttl}
+}
+
+// WithIdempotency wraps handler so a Lambda main can opt in with one line. A request
+// with no Idempotency-Key header passes through unchanged. A request carrying a key
+// already seen with the same method, path, and body returns the cached response
+// without calling handler again; the same key with a different body returns a 422
+// conflict.
+func (m *Idempotency) WithIdempotency(handler APIGatewayHandlerFunc) APIGatewayHandlerFunc {
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		key := requestHeader(request, IdempotencyKeyHeader)
+		if key == "" {
+			return handler(ctx, request)
+		}
+
+		keyHash := hashString(key)
+		requestHash := hashString(fmt.Sprintf("%s\n%s\n%s", request.HTTPMethod, request.Path, request.Body))
+
+		existing, err := m.get(ctx, keyHash)
+		if err != nil {
+			return events.APIGatewayProxyResponse{}, fmt.Errorf("idempotency: failed to look up key: %w", err)
+		}
+
+		if existing != nil {
+			if existing.RequestHash != requestHash {
+				return NewAPIResponse(http.StatusUnprocessableEntity,
+					models.NewErrorResponse(models.ErrCodeInvalidRequest,
+						"Idempotency-Key was already used with a different request", "")).ToLambdaResponse()
+			}
+
+			var headers map[string]string
+			if existing.Headers != "" {
+				if err := json.Unmarshal([]byte(existing.Headers), &headers); err != nil {
+					return events.APIGatewayProxyResponse{}, fmt.Errorf("idempotency: failed to unmarshal cached headers: %w", err)
+				}
+			}
+			return events.APIGatewayProxyResponse{
+				StatusCode: existing.StatusCode,
+				Headers:    headers,
+				Body:       existing.Body,
+			}, nil
+		}
+
+		response, err := handler(ctx, request)
+		if err != nil {
+			return response, err
+		}
+
+		if err := m.put(ctx, keyHash, requestHash, response); err != nil {
+			return events.APIGatewayProxyResponse{}, fmt.Errorf("idempotency: failed to store response: %w", err)
+		}
+
+		return response, nil
+	}
+}
+
+func (m *Idempotency) get(ctx context.Context, keyHash string) (*idempotencyRecord, error) {
+	out, err := m.client.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(m.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"key_hash": {S: aws.String(keyHash)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.Item == nil {
+		return nil, nil
+	}
+
+	var record idempotencyRecord
+	if err := dynamodbattribute.UnmarshalMap(out.Item, &record); err != nil {
+		return nil, err
+	}
+	if record.ExpiresAt > 0 && record.ExpiresAt <= time.Now().Unix() {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (m *Idempotency) put(ctx context.Context, keyHash, requestHash string, response events.APIGatewayProxyResponse) error {
+	headerBytes, err := json.Marshal(response.Headers)
+	if err != nil {
+		return err
+	}
+
+	record := idempotencyRecord{
+		KeyHash:     keyHash,
+		RequestHash: requestHash,
+		StatusCode:  response.StatusCode,
+		Headers:     string(headerBytes),
+		Body:        response.Body,
+		ExpiresAt:   time.Now().Add(m.ttl).Unix(),
+	}
+
+	item, err := dynamodbattribute.MarshalMap(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.client.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(m.tableName),
+		Item:      item,
+	})
+	return err
+}
+
+// requestHeader looks up name in request.Headers, trying both the canonical and
+// lowercased forms since API Gateway doesn't normalize header casing.
+func requestHeader(request events.APIGatewayProxyRequest, name string) string {
+	if v, ok := request.Headers[name]; ok {
+		return v
+	}
+	return request.Headers[strings.ToLower(name)]
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
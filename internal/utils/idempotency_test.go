@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestRequestHeader_FallsBackToLowercase(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{
+			name:    "canonical case",
+			headers: map[string]string{"Idempotency-Key": "abc"},
+			want:    "abc",
+		},
+		{
+			name:    "lowercase",
+			headers: map[string]string{"idempotency-key": "abc"},
+			want:    "abc",
+		},
+		{
+			name:    "missing",
+			headers: map[string]string{},
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := events.APIGatewayProxyRequest{Headers: tt.headers}
+			if got := requestHeader(request, IdempotencyKeyHeader); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashString_DeterministicAndDistinct(t *testing.T) {
+	a := hashString("hello")
+	b := hashString("hello")
+	c := hashString("world")
+
+	if a != b {
+		t.Errorf("expected identical input to hash the same, got %q and %q", a, b)
+	}
+	if a == c {
+		t.Errorf("expected different input to hash differently, got %q for both", a)
+	}
+}
@@ -0,0 +1,10 @@
+// Package vectorstore holds operations that keep the vector index consistent
+// with the documents stored in object storage, starting with cascade deletion.
+package vectorstore
+
+import "context"
+
+// Deleter removes every chunk belonging to a document from the vector index.
+type Deleter interface {
+	DeleteByDocumentID(ctx context.Context, documentID string) error
+}
@@ -0,0 +1,46 @@
+package vectorstore
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// FailedDeletion records a subsystem that did not complete during a document delete,
+// so a retry Lambda can pick it up later.
+type FailedDeletion struct {
+	DocumentID string
+	Subsystem  string // "s3", "vectors", or "metadata"
+	Reason     string
+	FailedAt   time.Time
+}
+
+// FailedDeletionRecorder persists FailedDeletion entries to DynamoDB for retry.
+type FailedDeletionRecorder struct {
+	client    *dynamodb.DynamoDB
+	tableName string
+}
+
+// NewFailedDeletionRecorder creates a recorder backed by the given table.
+func NewFailedDeletionRecorder(client *dynamodb.DynamoDB, tableName string) *FailedDeletionRecorder {
+	return &FailedDeletionRecorder{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// Record writes a failed deletion so a retry Lambda can process it later. Errors are
+// returned to the caller but are intentionally non-fatal to the delete request itself.
+func (r *FailedDeletionRecorder) Record(f FailedDeletion) error {
+	_, err := r.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item: map[string]*dynamodb.AttributeValue{
+			"document_id": {S: aws.String(f.DocumentID)},
+			"subsystem":   {S: aws.String(f.Subsystem)},
+			"reason":      {S: aws.String(f.Reason)},
+			"failed_at":   {S: aws.String(f.FailedAt.Format(time.RFC3339))},
+		},
+	})
+	return err
+}
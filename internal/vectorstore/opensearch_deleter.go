@@ -0,0 +1,71 @@
+package vectorstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/opensearch-project/opensearch-go/v2"
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+
+	"aws-serverless-rag/internal/models"
+	"aws-serverless-rag/internal/utils"
+)
+
+// OpenSearchDeleter removes all chunks for a document from the vector index via
+// OpenSearch's _delete_by_query API.
+type OpenSearchDeleter struct {
+	client    *opensearch.Client
+	logger    *utils.Logger
+	indexName string
+}
+
+// NewOpenSearchDeleter creates a deleter targeting the given OpenSearch client and index.
+func NewOpenSearchDeleter(client *opensearch.Client, indexName string, logger *utils.Logger) *OpenSearchDeleter {
+	return &OpenSearchDeleter{
+		client:    client,
+		indexName: indexName,
+		logger:    logger,
+	}
+}
+
+// DeleteByDocumentID deletes every chunk whose document_id matches documentID.
+func (d *OpenSearchDeleter) DeleteByDocumentID(ctx context.Context, documentID string) error {
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{
+				"document_id": documentID,
+			},
+		},
+	}
+
+	queryJSON, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delete_by_query body: %w", err)
+	}
+
+	req := opensearchapi.DeleteByQueryRequest{
+		Index: []string{d.indexName},
+		Body:  strings.NewReader(string(queryJSON)),
+	}
+
+	res, err := req.Do(ctx, d.client)
+	if err != nil {
+		return fmt.Errorf("%w: %v", models.ErrVectorDeletionFailed, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("%w: delete_by_query failed with status %s", models.ErrVectorDeletionFailed, res.Status())
+	}
+
+	if d.logger != nil {
+		d.logger.Info("Deleted vectors for document", map[string]interface{}{
+			"document_id": documentID,
+			"index_name":  d.indexName,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+// Package ragclient is an HTTP client for the deployed RAG API (POST /documents,
+// GET /documents/{id}/status, POST /query), shared by tests/ and cmd/loadtest so
+// there's one implementation of "how to talk to this API" instead of two.
+package ragclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"aws-serverless-rag/internal/models"
+)
+
+// DefaultTimeout is used by NewClient when timeout <= 0.
+const DefaultTimeout = 60 * time.Second
+
+// Client talks to one RAG API deployment.
+type Client struct {
+	Endpoint   string
+	AuthToken  string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a Client targeting endpoint (no trailing slash expected), with
+// requests timing out after timeout. timeout <= 0 uses DefaultTimeout.
+func NewClient(endpoint string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{
+		Endpoint:   endpoint,
+		HTTPClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// UploadDocument posts content as a multipart/form-data file named "file" to
+// POST /documents and returns the assigned document ID.
+func (c *Client) UploadDocument(fileName string, content []byte, contentType string) (string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(content); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.Endpoint+"/documents", &buf)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	c.setAuthHeader(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var uploadResponse struct {
+		DocumentID string `json:"document_id"`
+		Message    string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&uploadResponse); err != nil {
+		return "", err
+	}
+	return uploadResponse.DocumentID, nil
+}
+
+// GetDocumentStatus fetches GET /documents/{documentID}/status.
+func (c *Client) GetDocumentStatus(documentID string) (*models.StatusResponse, error) {
+	url := fmt.Sprintf("%s/documents/%s/status", c.Endpoint, documentID)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setAuthHeader(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("status request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var status models.StatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// WaitForProcessingComplete polls GetDocumentStatus until documentID reaches
+// StatusCompleted, returns an error on StatusFailed, or gives up after timeout.
+func (c *Client) WaitForProcessingComplete(documentID string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		status, err := c.GetDocumentStatus(documentID)
+		if err != nil {
+			return fmt.Errorf("failed to check status: %w", err)
+		}
+
+		switch status.Status {
+		case models.StatusCompleted:
+			return nil
+		case models.StatusFailed:
+			return fmt.Errorf("processing failed: %s", status.LastError)
+		default:
+			time.Sleep(2 * time.Second)
+		}
+	}
+
+	return fmt.Errorf("processing timeout after %v", timeout)
+}
+
+// QueryDocuments posts question (capped at maxResults sources) to POST /query.
+func (c *Client) QueryDocuments(question string, maxResults int) (*models.QueryResponse, error) {
+	requestBody := models.QueryRequest{
+		Question:   question,
+		MaxResults: maxResults,
+	}
+
+	jsonData, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.Endpoint+"/query", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.setAuthHeader(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("query failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var queryResponse models.QueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&queryResponse); err != nil {
+		return nil, err
+	}
+	return &queryResponse, nil
+}
+
+func (c *Client) setAuthHeader(req *http.Request) {
+	if c.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AuthToken)
+	}
+}
@@ -0,0 +1,144 @@
+package ragclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+)
+
+// resumableMaxRetries bounds how many times ResumableUpload retries a single chunk
+// PATCH before giving up.
+const resumableMaxRetries = 5
+
+// ResumableUpload uploads content via the chunked resumable-upload protocol
+// (POST /documents/uploads, PATCH .../{upload_id}, PUT .../{upload_id}), sending
+// chunkSize bytes per PATCH and retrying a chunk with exponential backoff if the
+// server returns a 5xx. It returns the finalized document ID.
+func (c *Client) ResumableUpload(filename, contentType string, content []byte, chunkSize int64) (string, error) {
+	if chunkSize <= 0 {
+		chunkSize = 5 * 1024 * 1024
+	}
+
+	uploadID, err := c.startResumableUpload(filename, contentType, int64(len(content)))
+	if err != nil {
+		return "", fmt.Errorf("failed to start resumable upload: %w", err)
+	}
+
+	for offset := int64(0); offset < int64(len(content)); {
+		end := offset + chunkSize
+		if end > int64(len(content)) {
+			end = int64(len(content))
+		}
+
+		if err := c.patchChunkWithRetry(uploadID, content[offset:end], offset, end-1, int64(len(content))); err != nil {
+			return "", fmt.Errorf("failed to upload chunk [%d-%d): %w", offset, end, err)
+		}
+		offset = end
+	}
+
+	return c.finalizeResumableUpload(uploadID)
+}
+
+func (c *Client) startResumableUpload(filename, contentType string, totalSize int64) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"filename":     filename,
+		"content_type": contentType,
+		"total_size":   totalSize,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.Endpoint+"/documents/uploads", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.setAuthHeader(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("start upload failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var started struct {
+		UploadID string `json:"upload_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&started); err != nil {
+		return "", err
+	}
+	return started.UploadID, nil
+}
+
+// patchChunkWithRetry PATCHes one chunk, retrying with exponential backoff
+// (100ms, 200ms, 400ms, ...) on a 5xx response.
+func (c *Client) patchChunkWithRetry(uploadID string, chunk []byte, start, end, total int64) error {
+	var lastErr error
+	for attempt := 0; attempt <= resumableMaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(math.Pow(2, float64(attempt-1))) * 100 * time.Millisecond)
+		}
+
+		req, err := http.NewRequest(http.MethodPatch, c.Endpoint+"/documents/uploads/"+uploadID, bytes.NewReader(chunk))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, total))
+		c.setAuthHeader(req)
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("server error %d: %s", resp.StatusCode, string(respBody))
+			continue
+		}
+		if resp.StatusCode != http.StatusAccepted {
+			return fmt.Errorf("chunk rejected with status %d: %s", resp.StatusCode, string(respBody))
+		}
+		return nil
+	}
+	return fmt.Errorf("exhausted %d retries: %w", resumableMaxRetries, lastErr)
+}
+
+func (c *Client) finalizeResumableUpload(uploadID string) (string, error) {
+	req, err := http.NewRequest(http.MethodPut, c.Endpoint+"/documents/uploads/"+uploadID, nil)
+	if err != nil {
+		return "", err
+	}
+	c.setAuthHeader(req)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("finalize failed with status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var finalized struct {
+		DocumentID string `json:"document_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&finalized); err != nil {
+		return "", err
+	}
+	return finalized.DocumentID, nil
+}
@@ -0,0 +1,138 @@
+package ragclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"aws-serverless-rag/internal/models"
+)
+
+// QueryEvent is one Server-Sent Event received from POST /query/stream, decoded
+// according to its event kind. Exactly one of Sources/Delta/(Usage,StopReason) is
+// populated, matching which Type the event carries; Err is set instead of the
+// others if the stream ended abnormally.
+type QueryEvent struct {
+	Type       string
+	Sources    []models.Source
+	Delta      string
+	Usage      models.Usage
+	StopReason string
+	Err        error
+}
+
+const (
+	// QueryEventSources is fired once with the full ranked source list.
+	QueryEventSources = "sources"
+	// QueryEventDelta is fired once per answer token as it arrives from Bedrock.
+	QueryEventDelta = "delta"
+	// QueryEventDone is the final event, carrying token usage and the stop reason.
+	QueryEventDone = "done"
+)
+
+// QueryStream posts req to POST /query/stream and returns a channel of QueryEvent,
+// decoded from the response's Server-Sent Events as they're parsed. The channel is
+// closed once the stream ends, whether normally (after a "done" event) or abnormally
+// (a QueryEvent with Err set is sent first). Canceling ctx aborts the in-flight
+// request and closes the channel.
+func (c *Client) QueryStream(ctx context.Context, req models.QueryRequest) (<-chan QueryEvent, error) {
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint+"/query/stream", bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	c.setAuthHeader(httpReq)
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("query stream failed with status %d", resp.StatusCode)
+	}
+
+	events := make(chan QueryEvent)
+	go func() {
+		defer close(events)
+		defer resp.Body.Close()
+
+		go func() {
+			<-ctx.Done()
+			resp.Body.Close()
+		}()
+
+		if err := scanSSE(resp.Body, events); err != nil {
+			select {
+			case events <- QueryEvent{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// scanSSE reads "event: <type>\ndata: <json>\n\n" blocks from body, decoding each
+// into a QueryEvent and sending it on events, until EOF.
+func scanSSE(body io.Reader, events chan<- QueryEvent) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var eventName string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventName = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data := strings.TrimPrefix(line, "data: ")
+			event, err := decodeSSEData(eventName, data)
+			if err != nil {
+				return err
+			}
+			events <- event
+		}
+	}
+	return scanner.Err()
+}
+
+func decodeSSEData(eventName, data string) (QueryEvent, error) {
+	switch eventName {
+	case QueryEventSources:
+		var sources []models.Source
+		if err := json.Unmarshal([]byte(data), &sources); err != nil {
+			return QueryEvent{}, fmt.Errorf("failed to decode %q event: %w", eventName, err)
+		}
+		return QueryEvent{Type: QueryEventSources, Sources: sources}, nil
+	case QueryEventDelta:
+		var delta struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal([]byte(data), &delta); err != nil {
+			return QueryEvent{}, fmt.Errorf("failed to decode %q event: %w", eventName, err)
+		}
+		return QueryEvent{Type: QueryEventDelta, Delta: delta.Text}, nil
+	case QueryEventDone:
+		var done struct {
+			Usage      models.Usage `json:"usage"`
+			StopReason string       `json:"stop_reason"`
+		}
+		if err := json.Unmarshal([]byte(data), &done); err != nil {
+			return QueryEvent{}, fmt.Errorf("failed to decode %q event: %w", eventName, err)
+		}
+		return QueryEvent{Type: QueryEventDone, Usage: done.Usage, StopReason: done.StopReason}, nil
+	default:
+		return QueryEvent{}, fmt.Errorf("unknown event type %q", eventName)
+	}
+}
@@ -0,0 +1,109 @@
+package ragclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"aws-serverless-rag/internal/models"
+)
+
+func TestQueryStream_EventOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+
+		fmt.Fprint(w, "event: sources\ndata: [{\"document_id\":\"d1\",\"chunk_id\":\"c1\"}]\n\n")
+		flusher.Flush()
+		fmt.Fprint(w, "event: delta\ndata: {\"text\":\"hello\"}\n\n")
+		flusher.Flush()
+		fmt.Fprint(w, "event: delta\ndata: {\"text\":\" world\"}\n\n")
+		flusher.Flush()
+		fmt.Fprint(w, "event: done\ndata: {\"usage\":{\"prompt_tokens\":10,\"completion_tokens\":2},\"stop_reason\":\"FINISH\"}\n\n")
+		flusher.Flush()
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, time.Second)
+	events, err := client.QueryStream(context.Background(), models.QueryRequest{Question: "hi?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []QueryEvent
+	for event := range events {
+		got = append(got, event)
+	}
+
+	wantTypes := []string{QueryEventSources, QueryEventDelta, QueryEventDelta, QueryEventDone}
+	if len(got) != len(wantTypes) {
+		t.Fatalf("got %d events, want %d: %+v", len(got), len(wantTypes), got)
+	}
+	for i, want := range wantTypes {
+		if got[i].Err != nil {
+			t.Fatalf("event %d: unexpected error: %v", i, got[i].Err)
+		}
+		if got[i].Type != want {
+			t.Errorf("event %d: got type %q, want %q", i, got[i].Type, want)
+		}
+	}
+
+	if len(got[0].Sources) != 1 || got[0].Sources[0].DocumentID != "d1" {
+		t.Errorf("sources event: got %+v", got[0].Sources)
+	}
+	if got[1].Delta != "hello" || got[2].Delta != " world" {
+		t.Errorf("delta events: got %q, %q", got[1].Delta, got[2].Delta)
+	}
+	if got[3].Usage.PromptTokens != 10 || got[3].Usage.CompletionTokens != 2 || got[3].StopReason != "FINISH" {
+		t.Errorf("done event: got %+v", got[3])
+	}
+}
+
+func TestQueryStream_ContextCancellationAbortsPromptly(t *testing.T) {
+	blockUntilClientGone := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+
+		fmt.Fprint(w, "event: sources\ndata: []\n\n")
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+		case <-blockUntilClientGone:
+		}
+	}))
+	defer server.Close()
+	defer close(blockUntilClientGone)
+
+	client := NewClient(server.URL, 10*time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := client.QueryStream(ctx, models.QueryRequest{Question: "hi?"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Drain the one event the server already flushed before cancellation.
+	<-events
+
+	start := time.Now()
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			// A trailing error event is acceptable; the channel must still close next.
+			<-events
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel did not close within 1s of context cancellation")
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("channel took %v to close after cancellation, want well under 1s", elapsed)
+	}
+}